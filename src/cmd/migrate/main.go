@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/config"
+	"work-tracker/src/pkg/settings"
+	"work-tracker/src/pkg/store"
+	"work-tracker/src/pkg/util"
+)
+
+func main() {
+	util.CheckIfEnvVarsPresent([]string{})
+
+	// common flags
+	configPath := flag.String("config", "./cfg/config.json", "Path to your configuration file.")
+	settingsPath := flag.String("settings", settings.DefaultPath, "Path to the user settings file, provides defaults for the flags below.")
+	profile := flag.String("profile", "", "Profile from the settings file whose values to use (\"default\" for the top-level values); empty => the tracker's active profile")
+
+	// program's custom flags
+	flagInputDir := flag.String("dir", "./out", "Data directory to migrate, in --from-layout, the default or the old flat layout")
+	flagLayout := flag.String("layout", store.DefaultLayout, "Path template to write the day files in, see src/pkg/store")
+	flagFromLayout := flag.String("from-layout", "", "Path template --dir is in besides the default one; empty => --layout")
+	flagTo := flag.String("to", "", "Directory to write the migrated day files to; empty => migrate --dir in place")
+	flagBackup := flag.String("backup", "", "Where to copy the files changed in place; empty => <dir>.backup-<timestamp> next to --dir")
+	flagDryRun := flag.Bool("dry-run", false, "Print what would be done without writing anything")
+
+	// parse and init config
+	flag.Parse()
+	config.InitializeConfig(*configPath)
+
+	tl.Log(tl.Notice, palette.BlueBold, "%s migrate entrypoint. Config path: '%s'", "Running", *configPath)
+
+	// settings file provides defaults, explicit flags win
+	userSettings, e := settings.Load(*settingsPath)
+	e.QuitIf("error")
+	if !util.FlagWasSet(flag.CommandLine, "profile") {
+		*profile = userSettings.ActiveProfile
+	}
+	userSettings, e = userSettings.WithProfile(*profile)
+	e.QuitIf("error")
+	if !util.FlagWasSet(flag.CommandLine, "dir") {
+		*flagInputDir = userSettings.WorkDir
+	}
+	if !util.FlagWasSet(flag.CommandLine, "layout") {
+		*flagLayout = userSettings.DayFileLayout
+	}
+	if *flagFromLayout == "" {
+		*flagFromLayout = *flagLayout
+	}
+	layout, err := store.ParseLayout(*flagLayout)
+	xerr.QuitIfError(err, "Unable to parse --layout")
+	fromLayout, err := store.ParseLayout(*flagFromLayout)
+	xerr.QuitIfError(err, "Unable to parse --from-layout")
+	if *flagBackup == "" {
+		*flagBackup = filepath.Clean(*flagInputDir) + ".backup-" + time.Now().Format("20060102-150405")
+	}
+
+	result, e := store.Migrate(store.MigrateOptions{
+		From:       *flagInputDir,
+		To:         *flagTo,
+		Backup:     *flagBackup,
+		DryRun:     *flagDryRun,
+		Layout:     layout,
+		FromLayout: fromLayout,
+	})
+	e.QuitIf("error")
+
+	// what was done goes to stdout
+	fmt.Printf("%-10s %6d\n", "files", result.Files)
+	fmt.Printf("%-10s %6d\n", "rewritten", result.Rewritten)
+	fmt.Printf("%-10s %6d\n", "moved", result.Moved)
+	fmt.Printf("%-10s %6d\n", "unchanged", result.Unchanged)
+	if result.Backup != "" {
+		fmt.Printf("%-10s %s\n", "backup", result.Backup)
+	}
+	if *flagDryRun {
+		tl.Log(tl.Notice, palette.Cyan, "%s, nothing was written", "Dry run")
+	}
+}
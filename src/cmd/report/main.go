@@ -7,9 +7,13 @@ import (
 
 	tl "github.com/tuumbleweed/tintlog/logger"
 	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
 
 	"work-tracker/src/pkg/config"
+	"work-tracker/src/pkg/locale"
 	"work-tracker/src/pkg/report"
+	"work-tracker/src/pkg/settings"
+	"work-tracker/src/pkg/store"
 	"work-tracker/src/pkg/util"
 )
 
@@ -18,11 +22,15 @@ func main() {
 
 	// common flags
 	configPath := flag.String("config", "./cfg/config.json", "Path to your configuration file.")
+	settingsPath := flag.String("settings", settings.DefaultPath, "Path to the user settings file, provides defaults for the flags below.")
+	profile := flag.String("profile", "", "Profile from the settings file whose values to use (\"default\" for the top-level values); empty => the tracker's active profile")
 
 	// program's custom flags
-	flagStart := flag.String("start", "", "Start date (inclusive) in DD-MM-YYYY; empty => this Monday")
-	flagEnd := flag.String("end", "", "End date (inclusive) in DD-MM-YYYY; empty => this Sunday (or start if start set)")
+	flagStart := flag.String("start", "", "Start date (inclusive) in DD-MM-YYYY; empty => first day of this week (the locale's)")
+	flagEnd := flag.String("end", "", "End date (inclusive) in DD-MM-YYYY; empty => last day of this week (or start if start set)")
+	flagPreset := flag.String("preset", "", "Named period (today, this-week, last-month, ...), used when --start and --end are empty")
 	flagInputDir := flag.String("dir", "./out", "Directory with day JSONL files")
+	flagLayout := flag.String("layout", store.DefaultLayout, "Path template of the day files under --dir, see src/pkg/store")
 	flagOutputPath := flag.String("output", "./out/report.html", "Path to write the HTML report")
 	flagTZ := flag.String("tz", "America/Bogota", "IANA timezone for week boundaries and display")
 	flagBarRef := flag.Duration("ref", 12*time.Hour, "Reference duration for the horizontal marker line (N hours)")
@@ -34,12 +42,54 @@ func main() {
 
 	tl.Log(tl.Notice, palette.BlueBold, "%s report entrypoint. Config path: '%s'", "Running", *configPath)
 
+	// settings file provides defaults, explicit flags win
+	userSettings, e := settings.Load(*settingsPath)
+	e.QuitIf("error")
+	if !util.FlagWasSet(flag.CommandLine, "profile") {
+		*profile = userSettings.ActiveProfile
+	}
+	userSettings, e = userSettings.WithProfile(*profile)
+	e.QuitIf("error")
+	if !util.FlagWasSet(flag.CommandLine, "dir") {
+		*flagInputDir = userSettings.WorkDir
+	}
+	if !util.FlagWasSet(flag.CommandLine, "layout") {
+		*flagLayout = userSettings.DayFileLayout
+	}
+	if !util.FlagWasSet(flag.CommandLine, "output") {
+		*flagOutputPath = userSettings.Report.OutputPath
+	}
+	if !util.FlagWasSet(flag.CommandLine, "tz") {
+		*flagTZ = userSettings.Report.Timezone
+	}
+	if !util.FlagWasSet(flag.CommandLine, "ref") {
+		*flagBarRef = userSettings.Report.BarRef.Duration
+	}
+	if !util.FlagWasSet(flag.CommandLine, "smooth") {
+		*flagSmooth = userSettings.Report.Smooth
+	}
+	if !util.FlagWasSet(flag.CommandLine, "preset") && *flagStart == "" && *flagEnd == "" {
+		*flagPreset = userSettings.Report.Preset
+	}
+
+	// language, date formats and first day of the week for the report
+	userLocale, e := locale.New(userSettings.Locale)
+	e.QuitIf("error")
+
 	// Resolve TZ + date range
-	_, startDate, endDate, e := report.ResolveRange(*flagTZ, *flagStart, *flagEnd)
+	loc, startDate, endDate, e := report.ResolveRange(*flagTZ, *flagStart, *flagEnd, userLocale.FirstWeekday)
 	e.QuitIf("error")
+	if *flagPreset != "" && *flagStart == "" && *flagEnd == "" {
+		var err error
+		startDate, endDate, err = report.PresetRange(report.Preset(*flagPreset), time.Now().In(loc), userLocale.FirstWeekday)
+		xerr.QuitIfError(err, "Unable to resolve --preset")
+	}
+
+	layout, err := store.ParseLayout(*flagLayout)
+	xerr.QuitIfError(err, "Unable to parse --layout")
 
 	// Build the report
-	e = report.BuildReport(*flagInputDir, startDate, endDate, *flagOutputPath, *flagBarRef, *flagSmooth)
+	e = report.BuildReport(*flagInputDir, layout, startDate, endDate, *flagOutputPath, *flagBarRef, *flagSmooth, userLocale)
 	e.QuitIf("error")
 
 	// Open in Chrome
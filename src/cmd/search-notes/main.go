@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/config"
+	"work-tracker/src/pkg/locale"
+	"work-tracker/src/pkg/report"
+	"work-tracker/src/pkg/settings"
+	"work-tracker/src/pkg/store"
+	"work-tracker/src/pkg/util"
+)
+
+func main() {
+	util.CheckIfEnvVarsPresent([]string{})
+
+	// common flags
+	configPath := flag.String("config", "./cfg/config.json", "Path to your configuration file.")
+	settingsPath := flag.String("settings", settings.DefaultPath, "Path to the user settings file, provides defaults for the flags below.")
+	profile := flag.String("profile", "", "Profile from the settings file whose values to use (\"default\" for the top-level values); empty => the tracker's active profile")
+
+	// program's custom flags
+	flagQuery := flag.String("q", "", "Text to look for in notes and task names (case-insensitive); empty lists every note")
+	flagStart := flag.String("start", "", "Start date (inclusive) in DD-MM-YYYY; empty => use --preset")
+	flagEnd := flag.String("end", "", "End date (inclusive) in DD-MM-YYYY; empty => today (or start if start set)")
+	flagPreset := flag.String("preset", string(report.PresetLast30Days), "Named period (today, this-week, last-month, ...), used when --start and --end are empty")
+	flagInputDir := flag.String("dir", "./out", "Directory with day JSONL files")
+	flagLayout := flag.String("layout", store.DefaultLayout, "Path template of the day files under --dir, see src/pkg/store")
+	flagTZ := flag.String("tz", "America/Bogota", "IANA timezone for dates and times")
+
+	// parse and init config
+	flag.Parse()
+	config.InitializeConfig(*configPath)
+
+	tl.Log(tl.Notice, palette.BlueBold, "%s search-notes entrypoint. Config path: '%s'", "Running", *configPath)
+
+	// settings file provides defaults, explicit flags win
+	userSettings, e := settings.Load(*settingsPath)
+	e.QuitIf("error")
+	if !util.FlagWasSet(flag.CommandLine, "profile") {
+		*profile = userSettings.ActiveProfile
+	}
+	userSettings, e = userSettings.WithProfile(*profile)
+	e.QuitIf("error")
+	if !util.FlagWasSet(flag.CommandLine, "dir") {
+		*flagInputDir = userSettings.WorkDir
+	}
+	if !util.FlagWasSet(flag.CommandLine, "layout") {
+		*flagLayout = userSettings.DayFileLayout
+	}
+	if !util.FlagWasSet(flag.CommandLine, "tz") {
+		*flagTZ = userSettings.Report.Timezone
+	}
+
+	// only the first day of the week is taken from the locale, the output stays easy to grep
+	userLocale, e := locale.New(userSettings.Locale)
+	e.QuitIf("error")
+
+	// Resolve TZ + date range
+	loc, startDate, endDate, e := report.ResolveRange(*flagTZ, *flagStart, *flagEnd, userLocale.FirstWeekday)
+	e.QuitIf("error")
+	if *flagStart == "" && *flagEnd == "" {
+		var err error
+		startDate, endDate, err = report.PresetRange(report.Preset(*flagPreset), time.Now().In(loc), userLocale.FirstWeekday)
+		xerr.QuitIfError(err, "Unable to resolve --preset")
+	}
+
+	layout, err := store.ParseLayout(*flagLayout)
+	xerr.QuitIfError(err, "Unable to parse --layout")
+
+	matches, e := report.SearchNotes(*flagInputDir, layout, startDate, endDate, *flagQuery)
+	e.QuitIf("error")
+
+	// results go to stdout, one block per line, so they can be piped
+	for _, block := range matches {
+		fmt.Printf("%s  %s-%s  %6s  %s: %s\n",
+			block.Start.In(loc).Format("Mon 02-01-2006"),
+			block.Start.In(loc).Format("15:04"), block.End.In(loc).Format("15:04"),
+			hoursMinutes(block.End.Sub(block.Start)), block.Task, block.Note,
+		)
+	}
+	tl.Log(tl.Notice, palette.Green, "%s %s note blocks for '%s' in '%s'..'%s'",
+		"Found", len(matches), *flagQuery, startDate.Format("02-01-2006"), endDate.Format("02-01-2006"),
+	)
+}
+
+// "1h05m"
+func hoursMinutes(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
@@ -14,7 +14,9 @@ import (
 
 	"work-tracker/src/pkg/config"
 	"work-tracker/src/pkg/email"
+	"work-tracker/src/pkg/locale"
 	"work-tracker/src/pkg/report"
+	"work-tracker/src/pkg/settings"
 	"work-tracker/src/pkg/util"
 )
 
@@ -86,6 +88,9 @@ func sendReport(subprogram string, flags []string) {
 	subprogramCmd := flag.NewFlagSet(subprogram, flag.ExitOnError)
 	configPath := subprogramCmd.String("config", "./cfg/config.json", "Log level. Default is LOG_LEVEL env var value")
 
+	settingsPath := subprogramCmd.String("settings", settings.DefaultPath, "Path to the user settings file, provides defaults for the flags below")
+	profile := subprogramCmd.String("profile", "", "Profile from the settings file whose values to use (\"default\" for the top-level values); empty => the tracker's active profile")
+
 	// custom flags
 	provider := subprogramCmd.String("provider", "mailgun", "Provider to use when sending emails")
 	senderAddress := subprogramCmd.String("sender", "", "Sender's address")
@@ -96,6 +101,27 @@ func sendReport(subprogram string, flags []string) {
 	xerr.QuitIfError(subprogramCmd.Parse(flags), "Unable to subprogramCmd.Parse")
 	config.InitializeConfig(*configPath)
 
+	// settings file provides defaults, explicit flags win
+	userSettings, e := settings.Load(*settingsPath)
+	e.QuitIf("error")
+	if !util.FlagWasSet(subprogramCmd, "profile") {
+		*profile = userSettings.ActiveProfile
+	}
+	userSettings, e = userSettings.WithProfile(*profile)
+	e.QuitIf("error")
+	if !util.FlagWasSet(subprogramCmd, "provider") {
+		*provider = userSettings.Report.Provider
+	}
+	if !util.FlagWasSet(subprogramCmd, "sender") {
+		*senderAddress = userSettings.Report.Sender
+	}
+	if !util.FlagWasSet(subprogramCmd, "recipient") {
+		*recipientAddress = strings.Join(userSettings.Report.Recipients, ",")
+	}
+	if !util.FlagWasSet(subprogramCmd, "html-file") {
+		*emailHtmlFilePath = userSettings.Report.OutputPath
+	}
+
 	util.RequiredFlag(senderAddress, "--sender")
 	util.RequiredFlag(recipientAddress, "--recipient")
 	util.EnsureFlags()
@@ -111,10 +137,9 @@ func sendReport(subprogram string, flags []string) {
 
 	reportTitle, e := report.ReadHTMLTitleFromBytes(htmlFileContentsBytes)
 	e.QuitIf("error")
-	subject := fmt.Sprintf(
-		"Work Tracker\u00A0\u00A0\u00A0·\u00A0\u00A0\u00A0%s\u00A0\u00A0\u00A0·\u00A0\u00A0\u00A0%s",
-		reportTitle, time.Now().Format("2006-01-02 (Mon) 15:04:05"),
-	)
+	userLocale, e := locale.New(userSettings.Locale)
+	e.QuitIf("error")
+	subject := report.EmailSubject(reportTitle, time.Now(), userLocale)
 
 	// send email here
 	sendEmails := true
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/config"
+	"work-tracker/src/pkg/history"
+	"work-tracker/src/pkg/settings"
+	"work-tracker/src/pkg/store"
+	"work-tracker/src/pkg/util"
+)
+
+func main() {
+	util.CheckIfEnvVarsPresent([]string{})
+
+	// common flags
+	configPath := flag.String("config", "./cfg/config.json", "Path to your configuration file.")
+	settingsPath := flag.String("settings", settings.DefaultPath, "Path to the user settings file, provides defaults for the flags below.")
+	profile := flag.String("profile", "", "Profile from the settings file whose values to use (\"default\" for the top-level values); empty => the tracker's active profile")
+
+	// program's custom flags
+	flagDate := flag.String("date", "", "Day to edit in DD-MM-YYYY; empty => today")
+	flagFrom := flag.String("from", "", "Start of the range to split (HH:MM); may be empty when every task has a time range")
+	flagTo := flag.String("to", "", "End of the range to split (HH:MM); may be empty when every task has a time range")
+	flagDryRun := flag.Bool("dry-run", false, "Print the split without writing the day file")
+	flagInputDir := flag.String("dir", "./out", "Directory with day JSONL files")
+	flagLayout := flag.String("layout", store.DefaultLayout, "Path template of the day files under --dir, see src/pkg/store")
+	flagTZ := flag.String("tz", "America/Bogota", "IANA timezone for the date and times")
+
+	// parse and init config
+	flag.Parse()
+	config.InitializeConfig(*configPath)
+
+	tl.Log(tl.Notice, palette.BlueBold, "%s split entrypoint. Config path: '%s'", "Running", *configPath)
+
+	// settings file provides defaults, explicit flags win
+	userSettings, e := settings.Load(*settingsPath)
+	e.QuitIf("error")
+	if !util.FlagWasSet(flag.CommandLine, "profile") {
+		*profile = userSettings.ActiveProfile
+	}
+	userSettings, e = userSettings.WithProfile(*profile)
+	e.QuitIf("error")
+	if !util.FlagWasSet(flag.CommandLine, "dir") {
+		*flagInputDir = userSettings.WorkDir
+	}
+	if !util.FlagWasSet(flag.CommandLine, "layout") {
+		*flagLayout = userSettings.DayFileLayout
+	}
+	if !util.FlagWasSet(flag.CommandLine, "tz") {
+		*flagTZ = userSettings.Report.Timezone
+	}
+
+	loc, err := time.LoadLocation(*flagTZ)
+	xerr.QuitIfError(err, "Unable to load --tz")
+	layout, err := store.ParseLayout(*flagLayout)
+	xerr.QuitIfError(err, "Unable to parse --layout")
+	day := time.Now().In(loc)
+	if *flagDate != "" {
+		day, err = time.ParseInLocation("02-01-2006", *flagDate, loc)
+		xerr.QuitIfError(err, "Unable to parse --date")
+	}
+
+	// tasks to split into are the arguments: "Task", "Task=2" or "Task=09:00-10:30"
+	parts, err := history.ParseSplitParts(flag.Args(), day)
+	xerr.QuitIfError(err, "Unable to read the tasks to split into")
+	from, err := clockOn(day, *flagFrom)
+	xerr.QuitIfError(err, "Unable to parse --from")
+	to, err := clockOn(day, *flagTo)
+	xerr.QuitIfError(err, "Unable to parse --to")
+	if parts[0].From.IsZero() && (from.IsZero() || to.IsZero()) {
+		xerr.QuitIfError(fmt.Errorf("--from and --to are required"), "Splitting by shares needs a range")
+	}
+	// with time ranges the range defaults to their span, the day file is picked by from's date
+	for _, part := range parts {
+		if *flagFrom == "" && (from.IsZero() || part.From.Before(from)) {
+			from = part.From
+		}
+		if *flagTo == "" && part.To.After(to) {
+			to = part.To
+		}
+	}
+
+	before, after, e := history.SplitDayFile(*flagInputDir, layout, from, to, parts, *flagDryRun)
+	e.QuitIf("error")
+
+	// time per task in the range, before -> after, goes to stdout
+	var taskNames []string
+	for taskName := range before {
+		taskNames = append(taskNames, taskName)
+	}
+	for taskName := range after {
+		if !slices.Contains(taskNames, taskName) {
+			taskNames = append(taskNames, taskName)
+		}
+	}
+	slices.Sort(taskNames)
+	for _, taskName := range taskNames {
+		label := taskName
+		if label == "" {
+			label = "Unassigned"
+		}
+		fmt.Printf("%-30s %8s -> %8s\n", label, hoursMinutes(before[taskName]), hoursMinutes(after[taskName]))
+	}
+	if *flagDryRun {
+		tl.Log(tl.Notice, palette.Cyan, "%s, nothing was written", "Dry run")
+	}
+	tl.Log(tl.Notice, palette.Green, "%s %s across %v tasks", "Split", day.Format("02-01-2006"), len(parts))
+}
+
+// clockOn reads "09:30" as that time on day, empty is the zero time
+func clockOn(day time.Time, text string) (at time.Time, err error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return at, nil
+	}
+	clock, err := time.ParseInLocation("15:04", text, day.Location())
+	if err != nil {
+		return at, err
+	}
+	year, month, date := day.Date()
+	return time.Date(year, month, date, clock.Hour(), clock.Minute(), 0, 0, day.Location()), nil
+}
+
+// "1h05m"
+func hoursMinutes(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
@@ -10,15 +10,18 @@ import (
 	"github.com/tuumbleweed/tintlog/palette"
 
 	"work-tracker/src/pkg/config"
-	"work-tracker/src/pkg/util"
+	"work-tracker/src/pkg/settings"
 	"work-tracker/src/pkg/tracker-app"
+	"work-tracker/src/pkg/util"
 )
 
 func main() {
 	util.CheckIfEnvVarsPresent([]string{})
 	// common flags
 	configPath := flag.String("config", "./cfg/config.json", "Path to your configuration file.")
-	// program's custom flags
+	settingsPath := flag.String("settings", settings.DefaultPath, "Path to the user settings file (edited from the Settings window).")
+	profile := flag.String("profile", "", "Profile from the settings file to start with (\"default\" for the top-level values); empty => the last one used")
+	// program's custom flags, they override values from the settings file when given
 	activityTickInterval := flag.Duration("activity-tick-interval", 1000*time.Millisecond, "UI and activity update period (e.g. 2m, 10m, 1h)")
 	uiTickInterval := flag.Duration("ui-tick-interval", 1*time.Second, "UI and activity update period (e.g. 2m, 10m, 1h)")
 	flushTickInterval := flag.Duration("flush-tick-interval", 10*time.Second, "Autosave period (e.g. 2m, 10m, 1h)")
@@ -28,14 +31,38 @@ func main() {
 	flag.Parse()
 	config.InitializeConfig(*configPath)
 
+	userSettings, e := settings.Load(*settingsPath)
+	e.QuitIf("error")
+	if !util.FlagWasSet(flag.CommandLine, "profile") {
+		*profile = userSettings.ActiveProfile
+	}
+	userSettings, e = userSettings.WithProfile(*profile)
+	e.QuitIf("error")
+	if util.FlagWasSet(flag.CommandLine, "activity-tick-interval") {
+		userSettings.ActivityTickInterval.Duration = *activityTickInterval
+	}
+	if util.FlagWasSet(flag.CommandLine, "ui-tick-interval") {
+		userSettings.UITickInterval.Duration = *uiTickInterval
+	}
+	if util.FlagWasSet(flag.CommandLine, "flush-tick-interval") {
+		userSettings.FlushTickInterval.Duration = *flushTickInterval
+	}
+	if util.FlagWasSet(flag.CommandLine, "work-dir") {
+		userSettings.WorkDir = *workDir
+	}
+	if util.FlagWasSet(flag.CommandLine, "tasks") {
+		userSettings.TasksPath = *tasksFilePath
+	}
+	userSettings.Validate().QuitIf("error")
+
 	tl.Log(
-		tl.Notice, palette.BlueBold, "%s worktracker --ui-tick-interval %s, --activity-tick-interval %s, --flush-tick-interval %s, --work-dir %s. Config path: '%s'",
-		"Running", *uiTickInterval, activityTickInterval, *flushTickInterval, workDir, *configPath,
+		tl.Notice, palette.BlueBold, "%s worktracker --profile %s, --ui-tick-interval %s, --activity-tick-interval %s, --flush-tick-interval %s, --work-dir %s. Config path: '%s', settings path: '%s'",
+		"Running", settings.ProfileLabel(userSettings.ActiveProfile), userSettings.UITickInterval, userSettings.ActivityTickInterval, userSettings.FlushTickInterval, userSettings.WorkDir, *configPath, *settingsPath,
 	)
 
-	util.CreateDirIfDoesntExist(*workDir).QuitIf("error")
+	util.CreateDirIfDoesntExist(userSettings.WorkDir).QuitIf("error")
 
-	trackerApp, e := trackerapp.InitializeTrackerApp("Worktracker", "Work Tracker", *workDir, *tasksFilePath, *uiTickInterval, *activityTickInterval, *flushTickInterval)
+	trackerApp, e := trackerapp.InitializeTrackerApp("Worktracker", "Work Tracker", *settingsPath, userSettings)
 	e.QuitIf("error")
 	trackerApp.Start()
 }
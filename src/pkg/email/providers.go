@@ -2,6 +2,7 @@ package email
 
 import (
 	"fmt"
+	"os"
 	"slices"
 	"time"
 
@@ -40,3 +41,42 @@ func IsValidProvider(provider Provider) (e *xerr.Error) {
 		provider,
 	)
 }
+
+// RequiredEnvVars lists the environment variables a provider reads its credentials from.
+func RequiredEnvVars(provider Provider) []string {
+	switch provider {
+	case ProviderMailgun:
+		return []string{"MAILGUN_DOMAIN", "MAILGUN_API_KEY"}
+	case ProviderSendGrid:
+		return []string{"SENDGRID_API_KEY"}
+	case ProviderAmazonSES:
+		return []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_REGION"}
+	default:
+		return nil
+	}
+}
+
+/*
+CheckProviderEnv returns an error naming every missing credential env var for provider.
+Unlike util.CheckIfEnvVarsPresent it does not exit, so GUI callers can show the error instead.
+*/
+func CheckProviderEnv(provider Provider) (e *xerr.Error) {
+	e = IsValidProvider(provider)
+	if e != nil {
+		return e
+	}
+
+	var missing []string
+	for _, envVarName := range RequiredEnvVars(provider) {
+		if os.Getenv(envVarName) == "" {
+			missing = append(missing, envVarName)
+		}
+	}
+	if len(missing) > 0 {
+		return xerr.NewError(
+			fmt.Errorf("missing env vars for '%s': %v", provider, missing),
+			"Check your environment variables", missing,
+		)
+	}
+	return nil
+}
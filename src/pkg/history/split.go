@@ -0,0 +1,322 @@
+/*
+Package history edits what was already tracked in the day files: splitting a
+range between tasks and cutting chunks, with the active time shared in proportion.
+It has no interface, so the tracker and the commands in src/cmd both use it.
+*/
+package history
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/store"
+)
+
+/*
+Splitting hands the time tracked in a range to several tasks after the fact,
+for a span that really covered more than one (a debugging session that fixed
+bugs in two tickets).
+
+The shares are either weights, and the tracked time in the range is cut in that
+proportion in time order, or explicit sub-ranges. Work chunks are cut where the
+shares meet and active time follows the cut in proportion (see SplitChunk).
+Pauses and focus records are left alone, and so is time in the range that no
+sub-range covers.
+*/
+
+// unassignedPartName is the part written for time without a task, whatever the language
+const unassignedPartName = "Unassigned"
+
+// SplitPart is one task's share of a split: a weight, or a sub-range for explicit splits.
+type SplitPart struct {
+	TaskName string    // "" is unassigned
+	Weight   float64   // share of the tracked time, proportional splits only
+	From     time.Time // explicit splits only, zero for proportional ones
+	To       time.Time
+}
+
+/*
+ParseSplitParts reads one part per spec: "Task" (weight 1), "Task=2" (weight 2)
+or "Task=09:00-10:30" (a sub-range on day). "Unassigned" is time without a task.
+Weights and sub-ranges can't be mixed.
+*/
+func ParseSplitParts(specs []string, day time.Time) (parts []SplitPart, err error) {
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		name, share, hasShare := strings.Cut(spec, "=")
+		part := SplitPart{TaskName: strings.TrimSpace(name), Weight: 1}
+		if part.TaskName == unassignedPartName {
+			part.TaskName = ""
+		}
+		share = strings.TrimSpace(share)
+		switch {
+		case !hasShare:
+		case strings.Contains(share, ":"):
+			fromText, toText, found := strings.Cut(share, "-")
+			if !found {
+				return nil, fmt.Errorf("'%s': a range is written 09:00-10:30", spec)
+			}
+			part.From, err = parseClockOn(day, fromText)
+			if err != nil {
+				return nil, fmt.Errorf("'%s': %w", spec, err)
+			}
+			part.To, err = parseClockOn(day, toText)
+			if err != nil {
+				return nil, fmt.Errorf("'%s': %w", spec, err)
+			}
+			part.Weight = 0
+		default:
+			part.Weight, err = strconv.ParseFloat(share, 64)
+			if err != nil || part.Weight <= 0 {
+				return nil, fmt.Errorf("'%s': the share must be a positive number or a range like 09:00-10:30", spec)
+			}
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 0 {
+		return nil, errors.New("name at least one task to split into")
+	}
+	explicit := !parts[0].From.IsZero()
+	for _, part := range parts[1:] {
+		if part.From.IsZero() == explicit {
+			return nil, errors.New("give every task either a share or a time range, not a mix")
+		}
+	}
+	return parts, nil
+}
+
+/*
+SplitChunks reassigns the work tracked in [from, to) to parts. For explicit parts
+from and to may be zero, the range is then the span of the parts.
+*/
+func SplitChunks(chunks []store.Chunk, from, to time.Time, parts []SplitPart) (result []store.Chunk, e *xerr.Error) {
+	if len(parts) == 0 {
+		return chunks, xerr.NewErrorECOL(errors.New("no parts"), "Name at least one task to split into", "range", fmt.Sprintf("%s-%s", from.Format(time.TimeOnly), to.Format(time.TimeOnly)))
+	}
+
+	explicit := !parts[0].From.IsZero()
+	if explicit {
+		parts = slices.Clone(parts)
+		slices.SortFunc(parts, func(a, b SplitPart) int { return a.From.Compare(b.From) })
+		if from.IsZero() {
+			from = parts[0].From
+		}
+		if to.IsZero() {
+			to = parts[len(parts)-1].To
+		}
+	}
+	if !to.After(from) {
+		return chunks, xerr.NewErrorECOL(errors.New("empty range"), "The range to split must end after it starts", "range", fmt.Sprintf("%s-%s", from.Format(time.TimeOnly), to.Format(time.TimeOnly)))
+	}
+	if TrackedBetween(chunks, from, to) == 0 {
+		return chunks, xerr.NewErrorECOL(errors.New("nothing tracked"), "Nothing was tracked in the range to split", "range", fmt.Sprintf("%s-%s", from.Format(time.TimeOnly), to.Format(time.TimeOnly)))
+	}
+
+	if explicit {
+		for i, part := range parts {
+			switch {
+			case !part.To.After(part.From):
+				return chunks, xerr.NewErrorECOL(errors.New("empty sub-range"), "A sub-range must end after it starts", "task name", part.TaskName)
+			case part.From.Before(from), part.To.After(to):
+				return chunks, xerr.NewErrorECOL(errors.New("sub-range outside the range"), "Sub-ranges must lie within the range to split", "task name", part.TaskName)
+			case i > 0 && part.From.Before(parts[i-1].To):
+				return chunks, xerr.NewErrorECOL(errors.New("overlapping sub-ranges"), "Sub-ranges must not overlap", "task name", part.TaskName)
+			}
+		}
+	} else {
+		parts = proportionalParts(chunks, from, to, parts)
+	}
+
+	result = slices.Clone(chunks)
+	for _, part := range parts {
+		result = cutChunksAt(cutChunksAt(result, part.From), part.To)
+		for i := range result {
+			if result[i].Kind == store.ChunkKindWork && !result[i].StartedAt.Before(part.From) && !result[i].FinishedAt.After(part.To) {
+				result[i].TaskName = part.TaskName
+			}
+		}
+	}
+	return result, nil
+}
+
+/*
+proportionalParts turns weights into sub-ranges: the cut between two parts falls
+where their share of the time tracked in [from, to) is reached, so gaps in
+tracking don't count towards anyone. Cuts are rounded to the second.
+*/
+func proportionalParts(chunks []store.Chunk, from, to time.Time, parts []SplitPart) (explicit []SplitPart) {
+	var spans []trackedSpan
+	for _, chunk := range chunks {
+		if chunk.Kind != store.ChunkKindWork {
+			continue
+		}
+		start, end := latest(chunk.StartedAt, from), earliest(chunk.FinishedAt, to)
+		if end.After(start) {
+			spans = append(spans, trackedSpan{start: start, end: end})
+		}
+	}
+	slices.SortFunc(spans, func(a, b trackedSpan) int { return a.start.Compare(b.start) })
+
+	totalWeight := 0.0
+	for _, part := range parts {
+		totalWeight += part.Weight
+	}
+	tracked := TrackedBetween(chunks, from, to)
+
+	cut := from
+	cumulativeWeight := 0.0
+	for i, part := range parts {
+		next := to
+		cumulativeWeight += part.Weight
+		if i < len(parts)-1 {
+			next = timeAtTracked(spans, time.Duration(float64(tracked)*cumulativeWeight/totalWeight)).Round(time.Second)
+			next = earliest(latest(next, cut), to)
+		}
+		explicit = append(explicit, SplitPart{TaskName: part.TaskName, From: cut, To: next})
+		cut = next
+	}
+	return explicit
+}
+
+type trackedSpan struct{ start, end time.Time }
+
+// timeAtTracked is the moment the spans (sorted, not overlapping) add up to target
+func timeAtTracked(spans []trackedSpan, target time.Duration) time.Time {
+	var sum time.Duration
+	for _, span := range spans {
+		length := span.end.Sub(span.start)
+		if sum+length >= target {
+			return span.start.Add(target - sum)
+		}
+		sum += length
+	}
+	return spans[len(spans)-1].end
+}
+
+// cutChunksAt splits the work chunk spanning at, if any, into the parts before and after it
+func cutChunksAt(chunks []store.Chunk, at time.Time) (result []store.Chunk) {
+	for _, chunk := range chunks {
+		if chunk.Kind == store.ChunkKindWork && chunk.StartedAt.Before(at) && chunk.FinishedAt.After(at) {
+			before, after := SplitChunk(chunk, at)
+			result = append(result, before, after)
+			continue
+		}
+		result = append(result, chunk)
+	}
+	return result
+}
+
+// TrackedBetween is the work time inside [from, to), whatever the task.
+func TrackedBetween(chunks []store.Chunk, from, to time.Time) (tracked time.Duration) {
+	for _, d := range TimeByTaskBetween(chunks, from, to) {
+		tracked += d
+	}
+	return tracked
+}
+
+// TimeByTaskBetween is the work time per task inside [from, to), "" is unassigned.
+func TimeByTaskBetween(chunks []store.Chunk, from, to time.Time) (timeByTask map[string]time.Duration) {
+	timeByTask = make(map[string]time.Duration)
+	for _, chunk := range chunks {
+		if chunk.Kind != store.ChunkKindWork {
+			continue
+		}
+		start, end := latest(chunk.StartedAt, from), earliest(chunk.FinishedAt, to)
+		if end.After(start) {
+			timeByTask[chunk.TaskName] += end.Sub(start)
+		}
+	}
+	return timeByTask
+}
+
+/*
+SplitDayFile splits [from, to) in the day of from's date under workDir, laid out by layout, and returns
+the time per task in the range before and after. With dryRun nothing is written.
+
+Meant for days the tracker isn't running on: a running tracker keeps its own totals
+and the next rewrite of today's file would not know about the split.
+*/
+func SplitDayFile(workDir string, layout *store.Layout, from, to time.Time, parts []SplitPart, dryRun bool) (before, after map[string]time.Duration, e *xerr.Error) {
+	_, filePath := layout.DayFilePath(workDir, from)
+	tl.Log(tl.Info, palette.Blue, "%s %s-%s in '%s'", "Splitting", from.Format(time.TimeOnly), to.Format(time.TimeOnly), filePath)
+
+	chunks, e := store.NewReader(workDir, layout, store.Strict).ReadDay(from)
+	if e != nil {
+		return nil, nil, e
+	}
+	split, e := SplitChunks(chunks, from, to, parts)
+	if e != nil {
+		return nil, nil, e
+	}
+	before, after = TimeByTaskBetween(chunks, from, to), TimeByTaskBetween(split, from, to)
+	if dryRun {
+		tl.Log(tl.Info, palette.Cyan, "%s, '%s' is left as it was", "Dry run", filePath)
+		return before, after, nil
+	}
+	e = store.NewWriter(workDir, layout).RewriteDay(from, split)
+	if e != nil {
+		return nil, nil, e
+	}
+	tl.Log(tl.Info, palette.Green, "%s %s-%s across %v tasks in '%s'", "Split", from.Format(time.TimeOnly), to.Format(time.TimeOnly), len(parts), filePath)
+	return before, after, nil
+}
+
+// parseClockOn reads "09:30" as that time on day
+func parseClockOn(day time.Time, text string) (at time.Time, err error) {
+	clock, err := time.ParseInLocation("15:04", strings.TrimSpace(text), day.Location())
+	if err != nil {
+		return at, fmt.Errorf("'%s' is not a time like 09:30", strings.TrimSpace(text))
+	}
+	year, month, date := day.Date()
+	return time.Date(year, month, date, clock.Hour(), clock.Minute(), 0, 0, day.Location()), nil
+}
+
+// SplitChunk cuts chunk at (StartedAt < at < FinishedAt), sharing active time in proportion
+func SplitChunk(chunk store.Chunk, at time.Time) (before, after store.Chunk) {
+	at = at.Round(0)
+	share := float64(at.Sub(chunk.StartedAt)) / float64(chunk.FinishedAt.Sub(chunk.StartedAt))
+	before, after = chunk, chunk
+	before.FinishedAt = at
+	before.StopReason = "" // the run didn't stop at the cut
+	before.ActiveTime = clamp(time.Duration(float64(chunk.ActiveTime)*share), 0, at.Sub(chunk.StartedAt))
+	after.StartedAt = at
+	after.StartReason = ""
+	// rounding must never push active time past the duration, the loader rejects that
+	after.ActiveTime = clamp(chunk.ActiveTime-before.ActiveTime, 0, chunk.FinishedAt.Sub(at))
+	return before, after
+}
+
+func clamp(d, min, max time.Duration) time.Duration {
+	if d < min {
+		return min
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+func earliest(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,309 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"work-tracker/src/pkg/store"
+)
+
+var testDay = time.Date(2026, 1, 23, 0, 0, 0, 0, time.UTC)
+
+// at is a time on testDay
+func at(hour, minute int) time.Time {
+	return testDay.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func workChunk(taskName string, from, to time.Time, active time.Duration) store.Chunk {
+	return store.Chunk{Kind: store.ChunkKindWork, TaskName: taskName, StartedAt: from, FinishedAt: to, ActiveTime: active}
+}
+
+func pauseChunk(from, to time.Time) store.Chunk {
+	return store.Chunk{Kind: store.ChunkKindPause, StartedAt: from, FinishedAt: to}
+}
+
+// chunkSpan is what the tests compare a chunk by
+type chunkSpan struct {
+	Kind       string
+	TaskName   string
+	From, To   time.Time
+	ActiveTime time.Duration
+}
+
+func spans(chunks []store.Chunk) (result []chunkSpan) {
+	for _, chunk := range chunks {
+		result = append(result, chunkSpan{chunk.Kind, chunk.TaskName, chunk.StartedAt, chunk.FinishedAt, chunk.ActiveTime})
+	}
+	return result
+}
+
+func sameSpans(t *testing.T, got, want []store.Chunk) {
+	t.Helper()
+	gotSpans, wantSpans := spans(got), spans(want)
+	if len(gotSpans) != len(wantSpans) {
+		t.Fatalf("got %v chunks, want %v:\n got  %+v\n want %+v", len(gotSpans), len(wantSpans), gotSpans, wantSpans)
+	}
+	for i := range gotSpans {
+		if gotSpans[i] != wantSpans[i] {
+			t.Errorf("chunk %v:\n got  %+v\n want %+v", i, gotSpans[i], wantSpans[i])
+		}
+	}
+}
+
+func TestSplitChunk(t *testing.T) {
+	tests := []struct {
+		name                      string
+		chunk                     store.Chunk
+		at                        time.Time
+		beforeActive, afterActive time.Duration
+	}{
+		{"in proportion", workChunk("Email", at(9, 0), at(9, 10), 5*time.Minute), at(9, 4), 2 * time.Minute, 3 * time.Minute},
+		{"fully active", workChunk("Email", at(9, 0), at(9, 10), 10*time.Minute), at(9, 1), time.Minute, 9 * time.Minute},
+		{"nothing active", workChunk("Email", at(9, 0), at(9, 10), 0), at(9, 5), 0, 0},
+		{"rounding", workChunk("Email", at(9, 0), at(9, 0).Add(3*time.Second), time.Second), at(9, 0).Add(time.Second), 333333333, 666666667},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.chunk.StartReason, test.chunk.StopReason = "manual", "manual"
+			before, after := SplitChunk(test.chunk, test.at)
+			if !before.StartedAt.Equal(test.chunk.StartedAt) || !before.FinishedAt.Equal(test.at) || !after.StartedAt.Equal(test.at) || !after.FinishedAt.Equal(test.chunk.FinishedAt) {
+				t.Fatalf("split at %s: %s–%s and %s–%s", test.at, before.StartedAt, before.FinishedAt, after.StartedAt, after.FinishedAt)
+			}
+			if before.ActiveTime != test.beforeActive || after.ActiveTime != test.afterActive {
+				t.Errorf("active time %s and %s, want %s and %s", before.ActiveTime, after.ActiveTime, test.beforeActive, test.afterActive)
+			}
+			if before.ActiveTime+after.ActiveTime != test.chunk.ActiveTime {
+				t.Errorf("active time %s + %s is not %s", before.ActiveTime, after.ActiveTime, test.chunk.ActiveTime)
+			}
+			if before.StopReason != "" || after.StartReason != "" || before.StartReason != "manual" || after.StopReason != "manual" {
+				t.Errorf("reasons: before %q–%q, after %q–%q", before.StartReason, before.StopReason, after.StartReason, after.StopReason)
+			}
+			for _, chunk := range []store.Chunk{before, after} {
+				if e := chunk.Validate(); e != nil {
+					t.Errorf("invalid chunk %+v: %s", chunk, e)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitChunkClampsActiveTime(t *testing.T) {
+	// more active time than fits in the first half once split
+	chunk := workChunk("Email", at(9, 0), at(9, 10), 10*time.Minute)
+	chunk.FinishedAt = chunk.FinishedAt.Add(time.Nanosecond)
+	before, after := SplitChunk(chunk, at(9, 5))
+	if before.ActiveTime > before.Duration() || after.ActiveTime > after.Duration() {
+		t.Errorf("active time past the duration: %s of %s, %s of %s", before.ActiveTime, before.Duration(), after.ActiveTime, after.Duration())
+	}
+}
+
+func TestParseSplitParts(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []string
+		want    []SplitPart
+		wantErr bool
+	}{
+		{"weights", []string{"Code", " Email = 2 ", "", "Unassigned=0.5"}, []SplitPart{
+			{TaskName: "Code", Weight: 1},
+			{TaskName: "Email", Weight: 2},
+			{TaskName: "", Weight: 0.5},
+		}, false},
+		{"ranges", []string{"Code=09:00-10:30", "Email=10:30 - 11:00"}, []SplitPart{
+			{TaskName: "Code", From: at(9, 0), To: at(10, 30)},
+			{TaskName: "Email", From: at(10, 30), To: at(11, 0)},
+		}, false},
+		{"a range starting after it ends is left to SplitChunks", []string{"Code=10:00-09:00"}, []SplitPart{
+			{TaskName: "Code", From: at(10, 0), To: at(9, 0)},
+		}, false},
+		{"nothing", []string{"", "  "}, nil, true},
+		{"zero weight", []string{"Code=0"}, nil, true},
+		{"negative weight", []string{"Code=-1"}, nil, true},
+		{"not a number", []string{"Code=lots"}, nil, true},
+		{"half a range", []string{"Code=09:00"}, nil, true},
+		{"not a time", []string{"Code=09:00-25:00"}, nil, true},
+		{"weights and ranges mixed", []string{"Code", "Email=09:00-10:00"}, nil, true},
+		{"ranges and weights mixed", []string{"Email=09:00-10:00", "Code=2"}, nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseSplitParts(test.specs, testDay)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("ParseSplitParts(%q) = %+v, want an error", test.specs, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSplitParts(%q): %s", test.specs, err)
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("ParseSplitParts(%q) = %+v, want %+v", test.specs, got, test.want)
+			}
+			for i := range got {
+				if got[i].TaskName != test.want[i].TaskName || got[i].Weight != test.want[i].Weight || !got[i].From.Equal(test.want[i].From) || !got[i].To.Equal(test.want[i].To) {
+					t.Errorf("part %v = %+v, want %+v", i, got[i], test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSplitChunks(t *testing.T) {
+	// an hour half active, a pause, then half an hour fully active
+	chunks := []store.Chunk{
+		workChunk("Debug", at(9, 0), at(10, 0), 30*time.Minute),
+		pauseChunk(at(10, 0), at(10, 30)),
+		workChunk("Debug", at(10, 30), at(11, 0), 30*time.Minute),
+	}
+	second := func(hour, minute, second int) time.Time {
+		return at(hour, minute).Add(time.Duration(second) * time.Second)
+	}
+	tests := []struct {
+		name     string
+		from, to time.Time
+		parts    []SplitPart
+		want     []store.Chunk
+	}{
+		{"equal weights, the pause doesn't count", at(9, 0), at(11, 0), []SplitPart{{TaskName: "Code", Weight: 1}, {TaskName: "Email", Weight: 1}}, []store.Chunk{
+			workChunk("Code", at(9, 0), at(9, 45), 22*time.Minute+30*time.Second),
+			workChunk("Email", at(9, 45), at(10, 0), 7*time.Minute+30*time.Second),
+			chunks[1],
+			workChunk("Email", at(10, 30), at(11, 0), 30*time.Minute),
+		}},
+		{"a cut in a gap falls at the end of the tracked time", at(9, 0), at(11, 0), []SplitPart{{TaskName: "Code", Weight: 2}, {TaskName: "Email", Weight: 1}}, []store.Chunk{
+			workChunk("Code", at(9, 0), at(10, 0), 30*time.Minute),
+			chunks[1],
+			workChunk("Email", at(10, 30), at(11, 0), 30*time.Minute),
+		}},
+		{"untracked time before the first chunk doesn't count", at(8, 0), at(9, 30), []SplitPart{{TaskName: "Code", Weight: 1}, {TaskName: "", Weight: 1}}, []store.Chunk{
+			workChunk("Code", at(9, 0), at(9, 15), 7*time.Minute+30*time.Second),
+			workChunk("", at(9, 15), at(9, 30), 7*time.Minute+30*time.Second),
+			workChunk("Debug", at(9, 30), at(10, 0), 15*time.Minute),
+			chunks[1], chunks[2],
+		}},
+		{"cuts rounded to the second", at(10, 30), second(10, 30, 10), []SplitPart{{TaskName: "A", Weight: 1}, {TaskName: "B", Weight: 1}, {TaskName: "C", Weight: 1}}, []store.Chunk{
+			chunks[0], chunks[1],
+			workChunk("A", at(10, 30), second(10, 30, 3), 3*time.Second),
+			workChunk("B", second(10, 30, 3), second(10, 30, 7), 4*time.Second),
+			workChunk("C", second(10, 30, 7), second(10, 30, 10), 3*time.Second),
+			workChunk("Debug", second(10, 30, 10), at(11, 0), 30*time.Minute-10*time.Second),
+		}},
+		{"sub-ranges, the rest is left alone", time.Time{}, time.Time{}, []SplitPart{{TaskName: "Email", From: at(10, 40), To: at(10, 50)}, {TaskName: "Code", From: at(9, 0), To: at(9, 30)}}, []store.Chunk{
+			workChunk("Code", at(9, 0), at(9, 30), 15*time.Minute),
+			workChunk("Debug", at(9, 30), at(10, 0), 15*time.Minute),
+			chunks[1],
+			workChunk("Debug", at(10, 30), at(10, 40), 10*time.Minute),
+			workChunk("Email", at(10, 40), at(10, 50), 10*time.Minute),
+			workChunk("Debug", at(10, 50), at(11, 0), 10*time.Minute),
+		}},
+		{"a sub-range over the pause only takes the work", at(9, 0), at(11, 0), []SplitPart{{TaskName: "Meeting", From: at(9, 30), To: at(10, 45)}}, []store.Chunk{
+			workChunk("Debug", at(9, 0), at(9, 30), 15*time.Minute),
+			workChunk("Meeting", at(9, 30), at(10, 0), 15*time.Minute),
+			chunks[1],
+			workChunk("Meeting", at(10, 30), at(10, 45), 15*time.Minute),
+			workChunk("Debug", at(10, 45), at(11, 0), 15*time.Minute),
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, e := SplitChunks(chunks, test.from, test.to, test.parts)
+			if e != nil {
+				t.Fatalf("SplitChunks: %s", e.Msg)
+			}
+			sameSpans(t, got, test.want)
+		})
+	}
+}
+
+func TestSplitChunksRejects(t *testing.T) {
+	chunks := []store.Chunk{
+		workChunk("Debug", at(9, 0), at(10, 0), 30*time.Minute),
+		pauseChunk(at(10, 0), at(10, 30)),
+	}
+	tests := []struct {
+		name     string
+		from, to time.Time
+		parts    []SplitPart
+	}{
+		{"no parts", at(9, 0), at(10, 0), nil},
+		{"ends before it starts", at(10, 0), at(9, 0), []SplitPart{{TaskName: "Code", Weight: 1}}},
+		{"empty", at(9, 0), at(9, 0), []SplitPart{{TaskName: "Code", Weight: 1}}},
+		{"nothing tracked", at(10, 0), at(10, 30), []SplitPart{{TaskName: "Code", Weight: 1}}},
+		{"empty sub-range", time.Time{}, time.Time{}, []SplitPart{{TaskName: "Code", From: at(9, 30), To: at(9, 30)}, {TaskName: "Email", From: at(9, 0), To: at(9, 10)}}},
+		{"sub-range ending before it starts", at(9, 0), at(10, 0), []SplitPart{{TaskName: "Code", From: at(9, 40), To: at(9, 20)}}},
+		{"sub-range before the range", at(9, 10), at(10, 0), []SplitPart{{TaskName: "Code", From: at(9, 0), To: at(9, 20)}}},
+		{"sub-range after the range", at(9, 0), at(9, 30), []SplitPart{{TaskName: "Code", From: at(9, 20), To: at(9, 40)}}},
+		{"overlapping sub-ranges", time.Time{}, time.Time{}, []SplitPart{{TaskName: "Email", From: at(9, 20), To: at(9, 40)}, {TaskName: "Code", From: at(9, 0), To: at(9, 30)}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, e := SplitChunks(chunks, test.from, test.to, test.parts)
+			if e == nil {
+				t.Fatalf("SplitChunks = %+v, want an error", spans(got))
+			}
+			sameSpans(t, got, chunks)
+		})
+	}
+}
+
+func TestSplitChunksKeepsActiveTime(t *testing.T) {
+	// active times that don't divide evenly
+	chunks := []store.Chunk{
+		workChunk("Debug", at(9, 0), at(9, 47), 17*time.Minute+13*time.Second+7),
+		pauseChunk(at(9, 47), at(10, 3)),
+		workChunk("Debug", at(10, 3), at(10, 59), 41*time.Minute+59*time.Second+999),
+		workChunk("Review", at(10, 59), at(11, 7), 8*time.Minute),
+	}
+	var totalActive time.Duration
+	for _, chunk := range chunks {
+		totalActive += chunk.ActiveTime
+	}
+	splits := []struct {
+		name     string
+		from, to time.Time
+		parts    []SplitPart
+	}{
+		{"weights", at(9, 13), at(11, 1), []SplitPart{{TaskName: "A", Weight: 1}, {TaskName: "B", Weight: 2}, {TaskName: "C", Weight: 3}}},
+		{"odd weights", at(9, 0), at(11, 7), []SplitPart{{TaskName: "A", Weight: 0.3}, {TaskName: "B", Weight: 7}}},
+		{"sub-ranges", time.Time{}, time.Time{}, []SplitPart{{TaskName: "A", From: at(9, 1), To: at(9, 2)}, {TaskName: "B", From: at(10, 17), To: at(11, 3)}}},
+	}
+	for _, split := range splits {
+		t.Run(split.name, func(t *testing.T) {
+			got, e := SplitChunks(chunks, split.from, split.to, split.parts)
+			if e != nil {
+				t.Fatalf("SplitChunks: %s", e.Msg)
+			}
+			var active time.Duration
+			for _, chunk := range got {
+				active += chunk.ActiveTime
+				if err := chunk.Validate(); err != nil {
+					t.Errorf("invalid chunk %+v: %s", chunk, err)
+				}
+			}
+			if active != totalActive {
+				t.Errorf("active time %s after the split, %s before", active, totalActive)
+			}
+			if before, after := TrackedBetween(chunks, at(0, 0), at(23, 59)), TrackedBetween(got, at(0, 0), at(23, 59)); before != after {
+				t.Errorf("tracked %s after the split, %s before", after, before)
+			}
+		})
+	}
+}
+
+func TestTimeByTaskBetween(t *testing.T) {
+	chunks := []store.Chunk{
+		workChunk("Code", at(9, 0), at(10, 0), 0),
+		pauseChunk(at(10, 0), at(10, 30)),
+		workChunk("", at(10, 30), at(11, 0), 0),
+		workChunk("Code", at(11, 0), at(11, 20), 0),
+	}
+	got := TimeByTaskBetween(chunks, at(9, 45), at(11, 10))
+	want := map[string]time.Duration{"Code": 25 * time.Minute, "": 30 * time.Minute}
+	if len(got) != len(want) || got["Code"] != want["Code"] || got[""] != want[""] {
+		t.Errorf("TimeByTaskBetween = %v, want %v", got, want)
+	}
+	if tracked := TrackedBetween(chunks, at(9, 45), at(11, 10)); tracked != 55*time.Minute {
+		t.Errorf("TrackedBetween = %s, want 55m", tracked)
+	}
+}
@@ -0,0 +1,43 @@
+package locale
+
+import (
+	"slices"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+/*
+Regional conventions that aren't text, after CLDR's supplemental data.
+A tag without a region uses its most likely one ("en" => US, "es" => ES).
+Everything not listed uses a 24h clock and weeks starting on Monday.
+*/
+
+// regions where weeks start on Sunday
+var sundayFirstRegions = []string{
+	"AG", "AS", "BD", "BR", "BS", "BT", "BW", "BZ", "CA", "CN", "CO", "DM", "DO", "ET", "GT", "GU",
+	"HK", "HN", "ID", "IL", "IN", "JM", "JP", "KE", "KH", "KR", "LA", "MH", "MM", "MO", "MT", "MX",
+	"MZ", "NI", "NP", "PA", "PE", "PH", "PK", "PR", "PT", "PY", "SA", "SG", "SV", "TH", "TT", "TW",
+	"UM", "US", "VE", "VI", "WS", "YE", "ZA", "ZW",
+}
+
+// regions that read clock times as 3:04 PM
+var twelveHourRegions = []string{
+	"AU", "BD", "CA", "CO", "EG", "IN", "JO", "MX", "MY", "NZ", "PH", "PK", "SA", "US",
+}
+
+func firstWeekdayOf(tag language.Tag) time.Weekday {
+	if slices.Contains(sundayFirstRegions, regionOf(tag)) {
+		return time.Sunday
+	}
+	return time.Monday
+}
+
+func usesTwelveHour(tag language.Tag) bool {
+	return slices.Contains(twelveHourRegions, regionOf(tag))
+}
+
+func regionOf(tag language.Tag) string {
+	region, _ := tag.Region() // guessed when the tag has none
+	return region.String()
+}
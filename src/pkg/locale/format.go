@@ -0,0 +1,159 @@
+package locale
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Month is the month's full name ("January").
+func (l *Locale) Month(month time.Month) string {
+	return l.T(fmt.Sprintf("Month%d", month))
+}
+
+// MonthShort is the month's abbreviated name ("Jan").
+func (l *Locale) MonthShort(month time.Month) string {
+	return l.T(fmt.Sprintf("MonthShort%d", month))
+}
+
+// Weekday is the day's full name ("Monday").
+func (l *Locale) Weekday(weekday time.Weekday) string {
+	return l.T(fmt.Sprintf("Weekday%d", weekday))
+}
+
+// WeekdayShort is the day's abbreviated name ("Mon").
+func (l *Locale) WeekdayShort(weekday time.Weekday) string {
+	return l.T(fmt.Sprintf("WeekdayShort%d", weekday))
+}
+
+// every date message gets the same fields, each catalog picks the ones it needs and their order
+func (l *Locale) dateFields(t time.Time) []any {
+	return []any{
+		"Day", t.Format("02"),
+		"DayNumber", t.Day(),
+		"Month", l.Month(t.Month()),
+		"MonthShort", l.MonthShort(t.Month()),
+		"Weekday", l.Weekday(t.Weekday()),
+		"WeekdayShort", l.WeekdayShort(t.Weekday()),
+		"Year", t.Year(),
+	}
+}
+
+// Date is a day with its year ("02 Jan 2006").
+func (l *Locale) Date(t time.Time) string { return l.T("DateMedium", l.dateFields(t)...) }
+
+// DateLong is a day with its weekday and no year ("Monday, January 02").
+func (l *Locale) DateLong(t time.Time) string { return l.T("DateLong", l.dateFields(t)...) }
+
+// DayMonth is a day without its year ("02 Jan").
+func (l *Locale) DayMonth(t time.Time) string { return l.T("DayMonth", l.dateFields(t)...) }
+
+// Day is the day of the month alone, as it starts a range within one month ("02").
+func (l *Locale) Day(t time.Time) string { return l.T("DayOnly", l.dateFields(t)...) }
+
+// MonthYear is a month with its year ("Jan 2006").
+func (l *Locale) MonthYear(t time.Time) string { return l.T("MonthYear", l.dateFields(t)...) }
+
+/*
+DateRange is start–end with the shared month and year written once:
+"25 – 31 Oct 2025", "25 Oct – 02 Nov 2025", "28 Dec 2025 – 03 Jan 2026".
+*/
+func (l *Locale) DateRange(start, end time.Time) string {
+	switch {
+	case start.Year() == end.Year() && start.Month() == end.Month() && start.Day() == end.Day():
+		return l.Date(start)
+	case start.Year() == end.Year() && start.Month() == end.Month():
+		return l.T("DateRange", "Start", l.Day(start), "End", l.Date(end))
+	case start.Year() == end.Year():
+		return l.T("DateRange", "Start", l.DayMonth(start), "End", l.Date(end))
+	default:
+		return l.T("DateRange", "Start", l.Date(start), "End", l.Date(end))
+	}
+}
+
+// Clock is a time of day to the minute ("15:04" or "3:04 PM").
+func (l *Locale) Clock(t time.Time) string {
+	if !l.TwelveHour {
+		return t.Format("15:04")
+	}
+	return t.Format("3:04") + " " + l.dayHalf(t)
+}
+
+// ClockSeconds is a time of day to the second ("15:04:05" or "3:04:05 PM").
+func (l *Locale) ClockSeconds(t time.Time) string {
+	if !l.TwelveHour {
+		return t.Format("15:04:05")
+	}
+	return t.Format("3:04:05") + " " + l.dayHalf(t)
+}
+
+func (l *Locale) dayHalf(t time.Time) string {
+	if t.Hour() < 12 {
+		return l.T("ClockAM")
+	}
+	return l.T("ClockPM")
+}
+
+/*
+Duration is an amount of time for reports: "1h 2m", "45m 10s", "0s",
+or decimal hours ("1.03 h") with the decimal duration format.
+*/
+func (l *Locale) Duration(d time.Duration) string {
+	if d <= 0 {
+		if l.Decimal {
+			return l.decimalHours(0)
+		}
+		return "0s"
+	}
+	if l.Decimal {
+		return l.decimalHours(d)
+	}
+	secs := int64(d.Seconds() + 0.5) // round
+	h := secs / 3600
+	m := (secs % 3600) / 60
+	s := secs % 60
+	out := &strings.Builder{}
+	if h > 0 {
+		fmt.Fprintf(out, "%dh ", h)
+	}
+	if m > 0 {
+		fmt.Fprintf(out, "%dm ", m)
+	}
+	if s > 0 && h == 0 {
+		fmt.Fprintf(out, "%ds", s)
+	}
+	return strings.TrimSpace(out.String())
+}
+
+// DurationMinutes is an amount of time to the minute for labels: "1h 05m", or "1.08 h".
+func (l *Locale) DurationMinutes(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	if l.Decimal {
+		return l.decimalHours(d)
+	}
+	minutes := int(d.Minutes())
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
+
+func (l *Locale) decimalHours(d time.Duration) string {
+	return l.printer.Sprintf("%.2f h", d.Hours())
+}
+
+// Hours is a chart label in hours with one decimal ("7.5h"), whatever the duration format.
+func (l *Locale) Hours(d time.Duration) string {
+	return l.printer.Sprintf("%.1fh", d.Hours())
+}
+
+// Percent is a percentage with the locale's decimal separator ("87.5%"), decimals as in "%.*f".
+func (l *Locale) Percent(p float64, decimals int) string {
+	return l.printer.Sprintf("%.*f%%", decimals, p)
+}
+
+// WeekStart is 00:00 on the first day of day's week.
+func (l *Locale) WeekStart(day time.Time) time.Time {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	back := (int(day.Weekday()) - int(l.FirstWeekday) + 7) % 7
+	return day.AddDate(0, 0, -back)
+}
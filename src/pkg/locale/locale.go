@@ -0,0 +1,210 @@
+/*
+Package locale translates the tracker's and the reports' text and formats
+dates, clock times and durations the way the chosen language and region do.
+
+Messages live in embedded go-i18n catalogs (catalogs/<language>.json); a message
+missing from a catalog falls back to English. Conventions that aren't text
+(12/24h clock, first day of the week) come from the region, see conventions.go,
+and can be overridden in the settings file.
+*/
+package locale
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+)
+
+//go:embed catalogs/*.json
+var catalogs embed.FS
+
+const (
+	TimeFormat12h = "12h"
+	TimeFormat24h = "24h"
+
+	DurationFormatHM      = "hm"      // "1h 05m"
+	DurationFormatDecimal = "decimal" // "1.08 h"
+)
+
+var (
+	TimeFormats     = []string{"", TimeFormat12h, TimeFormat24h}
+	DurationFormats = []string{DurationFormatHM, DurationFormatDecimal}
+)
+
+// Options select a locale, they are saved in the settings file. Empty values follow the language and region.
+type Options struct {
+	Language       string `json:"language"`        // BCP 47 tag ("es", "de-AT", "en-GB"), empty => LC_ALL, LC_MESSAGES or LANG
+	TimeFormat     string `json:"time_format"`     // one of TimeFormats
+	DurationFormat string `json:"duration_format"` // one of DurationFormats, empty => hm
+	FirstWeekday   string `json:"first_weekday"`   // "monday", "sunday", ..., empty => the region's
+}
+
+// Locale formats and translates for one language and region. Safe for concurrent use.
+type Locale struct {
+	Tag          language.Tag // as asked for, region included; messages come from the closest catalog
+	TwelveHour   bool
+	Decimal      bool // durations as decimal hours
+	FirstWeekday time.Weekday
+
+	localizer *i18n.Localizer
+	printer   *message.Printer
+}
+
+// bundle holds every embedded catalog, English is the fallback for missing messages
+var bundle = loadBundle()
+
+func loadBundle() *i18n.Bundle {
+	b := i18n.NewBundle(language.English)
+	b.RegisterUnmarshalFunc("json", json.Unmarshal)
+	files, _ := fs.Glob(catalogs, "catalogs/*.json")
+	for _, file := range files {
+		_, err := b.LoadMessageFileFS(catalogs, file)
+		if err != nil {
+			// the catalogs are embedded, a broken one is a build mistake that TestCatalogs catches
+			panic(fmt.Sprintf("locale: bad catalog '%s': %v", file, err))
+		}
+	}
+	return b
+}
+
+// Languages lists the languages there are catalogs for ("de", "en", ...).
+func Languages() (names []string) {
+	for _, tag := range bundle.LanguageTags() {
+		names = append(names, tag.String())
+	}
+	slices.Sort(names)
+	return names
+}
+
+// New builds the locale options describe. Problems must be empty.
+func New(options Options) (l *Locale, e *xerr.Error) {
+	if problems := options.Problems(); len(problems) > 0 {
+		return nil, xerr.NewErrorECML(errors.New("invalid locale"), "Unable to set up the locale", "problems", strings.Join(problems, "\n"))
+	}
+
+	languageName := options.Language
+	if languageName == "" {
+		languageName = systemLanguage()
+	}
+	tag, err := language.Parse(languageName)
+	if err != nil {
+		tag = language.English
+	}
+
+	l = &Locale{
+		Tag:          tag,
+		TwelveHour:   usesTwelveHour(tag),
+		Decimal:      options.DurationFormat == DurationFormatDecimal,
+		FirstWeekday: firstWeekdayOf(tag),
+		localizer:    i18n.NewLocalizer(bundle, tag.String()),
+		printer:      message.NewPrinter(tag),
+	}
+	switch options.TimeFormat {
+	case TimeFormat12h:
+		l.TwelveHour = true
+	case TimeFormat24h:
+		l.TwelveHour = false
+	}
+	if options.FirstWeekday != "" {
+		l.FirstWeekday, _ = parseWeekday(options.FirstWeekday)
+	}
+
+	tl.Log(tl.Info1, palette.Green, "%s '%s'. 12h clock: %v, decimal durations: %v, weeks start on %s",
+		"Using locale", tag.String(), l.TwelveHour, l.Decimal, l.FirstWeekday,
+	)
+	return l, nil
+}
+
+// English is the locale used when nothing else was set up, with 24h clock and Monday weeks.
+func English() *Locale {
+	return &Locale{
+		Tag:          language.English,
+		FirstWeekday: time.Monday,
+		localizer:    i18n.NewLocalizer(bundle, language.English.String()),
+		printer:      message.NewPrinter(language.English),
+	}
+}
+
+// Problems returns one human readable line per invalid option.
+func (o Options) Problems() (problems []string) {
+	if o.Language != "" {
+		_, err := language.Parse(o.Language)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("locale.language '%s' is not a language tag like 'es' or 'en-GB'", o.Language))
+		}
+	}
+	if !slices.Contains(TimeFormats, o.TimeFormat) {
+		problems = append(problems, fmt.Sprintf("locale.time_format '%s' is not one of %q", o.TimeFormat, TimeFormats))
+	}
+	if o.DurationFormat != "" && !slices.Contains(DurationFormats, o.DurationFormat) {
+		problems = append(problems, fmt.Sprintf("locale.duration_format '%s' is not one of %v", o.DurationFormat, DurationFormats))
+	}
+	if _, ok := parseWeekday(o.FirstWeekday); o.FirstWeekday != "" && !ok {
+		problems = append(problems, fmt.Sprintf("locale.first_weekday '%s' is not a weekday like 'monday'", o.FirstWeekday))
+	}
+	return problems
+}
+
+/*
+T returns the message id in the locale's language, filled in from pairs of
+template keys and values: T("TrayToday", "Duration", "1h 05m").
+An id that no catalog has is returned as is, so a typo shows up on screen.
+*/
+func (l *Locale) T(id string, pairs ...any) string {
+	var data map[string]any
+	if len(pairs) > 0 {
+		data = make(map[string]any, len(pairs)/2)
+		for i := 0; i+1 < len(pairs); i += 2 {
+			data[fmt.Sprint(pairs[i])] = pairs[i+1]
+		}
+	}
+	text, err := l.localizer.Localize(&i18n.LocalizeConfig{MessageID: id, TemplateData: data})
+	if text == "" && err != nil {
+		return id
+	}
+	return text // err is set when the English fallback was used, the text is still right
+}
+
+// Has tells whether some catalog has the message id.
+func (l *Locale) Has(id string) bool {
+	text, _ := l.localizer.Localize(&i18n.LocalizeConfig{MessageID: id})
+	return text != ""
+}
+
+// the part of the environment that names the language, "es_CO.UTF-8" => "es-CO"
+func systemLanguage() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		value, _, _ = strings.Cut(value, ".") // charset
+		value, _, _ = strings.Cut(value, "@") // modifier
+		if value == "" || value == "C" || value == "POSIX" {
+			continue
+		}
+		return strings.ReplaceAll(value, "_", "-")
+	}
+	return "en"
+}
+
+// parseWeekday reads an English weekday name, case-insensitive
+func parseWeekday(name string) (weekday time.Weekday, ok bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(name, day.String()) {
+			return day, true
+		}
+	}
+	return time.Monday, false
+}
@@ -0,0 +1,210 @@
+package locale
+
+import (
+	"encoding/json"
+	"io/fs"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+func newTestLocale(t *testing.T, options Options) *Locale {
+	t.Helper()
+	l, e := New(options)
+	if e != nil {
+		t.Fatalf("New(%+v): %s", options, e.Msg)
+	}
+	return l
+}
+
+func TestRegionalConventions(t *testing.T) {
+	tests := []struct {
+		name         string
+		options      Options
+		twelveHour   bool
+		firstWeekday time.Weekday
+	}{
+		{"English is en-US", Options{Language: "en"}, true, time.Sunday},
+		{"en-GB", Options{Language: "en-GB"}, false, time.Monday},
+		{"Spanish is es-ES", Options{Language: "es"}, false, time.Monday},
+		{"es-MX", Options{Language: "es-MX"}, true, time.Sunday},
+		{"pt-BR, Sunday weeks on a 24h clock", Options{Language: "pt-BR"}, false, time.Sunday},
+		{"de-AT", Options{Language: "de-AT"}, false, time.Monday},
+		{"no catalog, the region still counts", Options{Language: "fr-CA"}, true, time.Sunday},
+		{"24h set", Options{Language: "en-US", TimeFormat: TimeFormat24h}, false, time.Sunday},
+		{"12h set", Options{Language: "de", TimeFormat: TimeFormat12h}, true, time.Monday},
+		{"first weekday set", Options{Language: "en-US", FirstWeekday: "Monday"}, true, time.Monday},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := newTestLocale(t, test.options)
+			if l.TwelveHour != test.twelveHour {
+				t.Errorf("TwelveHour = %v, want %v", l.TwelveHour, test.twelveHour)
+			}
+			if l.FirstWeekday != test.firstWeekday {
+				t.Errorf("FirstWeekday = %v, want %v", l.FirstWeekday, test.firstWeekday)
+			}
+		})
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	day := func(date int) time.Time { return time.Date(2026, 1, date, 15, 30, 0, 0, time.UTC) }
+	tests := []struct {
+		name         string
+		firstWeekday string
+		day          time.Time
+		want         time.Time
+	}{
+		{"Sunday weeks, midweek", "sunday", day(21), time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"Sunday weeks, on Sunday", "sunday", day(18), time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"Sunday weeks, on Saturday", "sunday", day(24), time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"Monday weeks, midweek", "monday", day(21), time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"Monday weeks, on Sunday", "monday", day(18), time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := newTestLocale(t, Options{Language: "en", FirstWeekday: test.firstWeekday})
+			if got := l.WeekStart(test.day); !got.Equal(test.want) {
+				t.Errorf("WeekStart(%s) = %s, want %s", test.day.Format(time.DateOnly), got, test.want)
+			}
+		})
+	}
+}
+
+func TestClock(t *testing.T) {
+	tests := []struct {
+		language string
+		at       time.Time
+		want     string
+		seconds  string
+	}{
+		{"en-US", time.Date(2026, 1, 23, 15, 4, 5, 0, time.UTC), "3:04 PM", "3:04:05 PM"},
+		{"en-US", time.Date(2026, 1, 23, 0, 30, 0, 0, time.UTC), "12:30 AM", "12:30:00 AM"},
+		{"en-US", time.Date(2026, 1, 23, 12, 0, 0, 0, time.UTC), "12:00 PM", "12:00:00 PM"},
+		{"es-MX", time.Date(2026, 1, 23, 9, 15, 0, 0, time.UTC), "9:15 a. m.", "9:15:00 a. m."},
+		{"en-GB", time.Date(2026, 1, 23, 15, 4, 5, 0, time.UTC), "15:04", "15:04:05"},
+		{"de", time.Date(2026, 1, 23, 9, 5, 0, 0, time.UTC), "09:05", "09:05:00"},
+	}
+	for _, test := range tests {
+		t.Run(test.language+" "+test.want, func(t *testing.T) {
+			l := newTestLocale(t, Options{Language: test.language})
+			if got := l.Clock(test.at); got != test.want {
+				t.Errorf("Clock = %q, want %q", got, test.want)
+			}
+			if got := l.ClockSeconds(test.at); got != test.seconds {
+				t.Errorf("ClockSeconds = %q, want %q", got, test.seconds)
+			}
+		})
+	}
+}
+
+func TestDurationMinutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		d       time.Duration
+		want    string
+	}{
+		{"zero", Options{Language: "en"}, 0, "0h 00m"},
+		{"negative", Options{Language: "en"}, -time.Minute, "0h 00m"},
+		{"seconds are dropped", Options{Language: "en"}, 59*time.Minute + 59*time.Second, "0h 59m"},
+		{"over an hour", Options{Language: "en"}, 65 * time.Minute, "1h 05m"},
+		{"over a day", Options{Language: "en"}, 25 * time.Hour, "25h 00m"},
+		{"decimal", Options{Language: "en", DurationFormat: DurationFormatDecimal}, 65 * time.Minute, "1.08 h"},
+		{"decimal comma", Options{Language: "de", DurationFormat: DurationFormatDecimal}, 90 * time.Minute, "1,50 h"},
+		{"decimal, negative", Options{Language: "en", DurationFormat: DurationFormatDecimal}, -time.Hour, "0.00 h"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := newTestLocale(t, test.options)
+			if got := l.DurationMinutes(test.d); got != test.want {
+				t.Errorf("DurationMinutes(%s) = %q, want %q", test.d, got, test.want)
+			}
+		})
+	}
+}
+
+func TestFallback(t *testing.T) {
+	english := English()
+	tests := []struct {
+		language string
+		id       string
+		want     string
+	}{
+		{"es", "Start", "Iniciar"},
+		{"es-CO", "Start", "Iniciar"},
+		{"fr", "Start", english.T("Start")},
+		{"ja-JP", "Start", english.T("Start")},
+		{"es", "NoSuchMessage", "NoSuchMessage"},
+	}
+	for _, test := range tests {
+		t.Run(test.language+" "+test.id, func(t *testing.T) {
+			l := newTestLocale(t, Options{Language: test.language})
+			if got := l.T(test.id); got != test.want {
+				t.Errorf("T(%q) = %q, want %q", test.id, got, test.want)
+			}
+		})
+	}
+
+	// a message one catalog lacks comes from English, with its fields filled in
+	b := i18n.NewBundle(language.English)
+	b.MustAddMessages(language.English, &i18n.Message{ID: "Hello", Other: "Hello"}, &i18n.Message{ID: "Today", Other: "Today: {{.Duration}}"})
+	b.MustAddMessages(language.Spanish, &i18n.Message{ID: "Hello", Other: "Hola"})
+	l := &Locale{Tag: language.Spanish, localizer: i18n.NewLocalizer(b, "es")}
+	if got := l.T("Hello"); got != "Hola" {
+		t.Errorf("T(Hello) = %q, want Hola", got)
+	}
+	if got := l.T("Today", "Duration", "1h 05m"); got != "Today: 1h 05m" {
+		t.Errorf("T(Today) = %q, want the English text", got)
+	}
+	if !l.Has("Today") || l.Has("Tomorrow") {
+		t.Errorf("Has(Today) = %v, Has(Tomorrow) = %v; want true, false", l.Has("Today"), l.Has("Tomorrow"))
+	}
+}
+
+// every embedded catalog loads and has the messages English has, no more and no fewer
+func TestCatalogs(t *testing.T) {
+	idsOf := func(t *testing.T, file string) []string {
+		t.Helper()
+		content, err := fs.ReadFile(catalogs, file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = i18n.NewBundle(language.English).ParseMessageFileBytes(content, file)
+		if err != nil {
+			t.Fatalf("go-i18n can't load %s: %s", file, err)
+		}
+		var messages map[string]string
+		err = json.Unmarshal(content, &messages)
+		if err != nil {
+			t.Fatalf("%s: %s", file, err)
+		}
+		return slices.Sorted(maps.Keys(messages))
+	}
+
+	files, err := fs.Glob(catalogs, "catalogs/*.json")
+	if err != nil || len(files) < 2 {
+		t.Fatalf("catalogs %v, %v; want English and others", files, err)
+	}
+	english := idsOf(t, "catalogs/en.json")
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			ids := idsOf(t, file)
+			for _, id := range english {
+				if !slices.Contains(ids, id) {
+					t.Errorf("missing %s", id)
+				}
+			}
+			for _, id := range ids {
+				if !slices.Contains(english, id) {
+					t.Errorf("%s isn't in English", id)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/tuumbleweed/xerr"
+)
+
+const (
+	dbusDestination = "org.freedesktop.Notifications"
+	dbusPath        = "/org/freedesktop/Notifications"
+	dbusNotify      = "org.freedesktop.Notifications.Notify"
+)
+
+// DBus talks to the freedesktop Notifications service on the session bus.
+type DBus struct {
+	appName string
+	conn    *dbus.Conn
+
+	mutex  sync.Mutex
+	idsKey map[string]uint32 // last notification id per Key, so updates replace it
+}
+
+// NewDBus connects to the session bus and checks that a notification service is running.
+func NewDBus(appName string) (d *DBus, e *xerr.Error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, xerr.NewErrorECOL(err, "Unable to connect to the D-Bus session bus", "app name", appName)
+	}
+
+	var hasOwner bool
+	err = conn.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, dbusDestination).Store(&hasOwner)
+	if err == nil && !hasOwner {
+		err = errors.New("no owner")
+	}
+	if err != nil {
+		conn.Close()
+		return nil, xerr.NewErrorECOL(err, "No notification service on the session bus", "name", dbusDestination)
+	}
+
+	return &DBus{appName: appName, conn: conn, idsKey: make(map[string]uint32)}, nil
+}
+
+func (d *DBus) Notify(n Notification) (e *xerr.Error) {
+	d.mutex.Lock()
+	replacesID := d.idsKey[n.Key]
+	d.mutex.Unlock()
+
+	hints := map[string]dbus.Variant{"urgency": dbus.MakeVariant(byte(n.Urgency))}
+	var id uint32
+	err := d.conn.Object(dbusDestination, dbusPath).Call(dbusNotify, 0,
+		d.appName, replacesID, "", n.Title, n.Body, []string{}, hints, int32(-1),
+	).Store(&id)
+	if err != nil {
+		return xerr.NewErrorECOL(err, "Unable to send notification over D-Bus", "title", n.Title)
+	}
+
+	if n.Key != "" {
+		d.mutex.Lock()
+		d.idsKey[n.Key] = id
+		d.mutex.Unlock()
+	}
+	return nil
+}
+
+// Close disconnects from the session bus.
+func (d *DBus) Close() {
+	d.conn.Close()
+}
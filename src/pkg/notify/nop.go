@@ -0,0 +1,32 @@
+package notify
+
+import (
+	"sync"
+
+	"github.com/tuumbleweed/xerr"
+)
+
+// Nop drops every notification.
+type Nop struct{}
+
+func (Nop) Notify(Notification) (e *xerr.Error) { return nil }
+
+// Fake keeps every notification in memory instead of showing it.
+type Fake struct {
+	mutex sync.Mutex
+	sent  []Notification
+}
+
+func (f *Fake) Notify(n Notification) (e *xerr.Error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.sent = append(f.sent, n)
+	return nil
+}
+
+// Sent returns a copy of what was sent so far, oldest first.
+func (f *Fake) Sent() []Notification {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]Notification(nil), f.sent...)
+}
@@ -0,0 +1,21 @@
+package notify
+
+import (
+	"os/exec"
+
+	"github.com/tuumbleweed/xerr"
+)
+
+// NotifySend shows notifications with the notify-send command (libnotify-bin).
+type NotifySend struct {
+	AppName string
+}
+
+func (s NotifySend) Notify(n Notification) (e *xerr.Error) {
+	urgency := map[Urgency]string{UrgencyLow: "low", UrgencyNormal: "normal", UrgencyCritical: "critical"}[n.Urgency]
+	err := exec.Command("notify-send", "--app-name", s.AppName, "--urgency", urgency, n.Title, n.Body).Run()
+	if err != nil {
+		return xerr.NewErrorECOL(err, "Unable to run notify-send", "title", n.Title)
+	}
+	return nil
+}
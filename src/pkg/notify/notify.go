@@ -0,0 +1,57 @@
+/*
+Package notify sends desktop notifications.
+
+New picks the best available backend: the freedesktop Notifications service
+over D-Bus, then the notify-send command, then Nop. Fake records notifications
+instead of showing them.
+*/
+package notify
+
+import (
+	"os/exec"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+)
+
+type Urgency byte
+
+// freedesktop urgency levels
+const (
+	UrgencyLow      Urgency = 0
+	UrgencyNormal   Urgency = 1
+	UrgencyCritical Urgency = 2
+)
+
+type Notification struct {
+	// Key identifies what the notification is about. A newer notification
+	// with the same key replaces the old one instead of stacking up (when the backend can).
+	Key     string
+	Title   string
+	Body    string
+	Urgency Urgency
+}
+
+type Notifier interface {
+	Notify(n Notification) (e *xerr.Error)
+}
+
+// New returns the D-Bus notifier, or notify-send, or Nop when neither is available.
+func New(appName string) Notifier {
+	dbusNotifier, e := NewDBus(appName)
+	if e == nil {
+		tl.Log(tl.Info, palette.Green, "%s desktop notifications over %s", "Sending", "D-Bus")
+		return dbusNotifier
+	}
+	tl.Log(tl.Info, palette.Yellow, "%s: %s", "D-Bus notifications unavailable", e.Msg)
+
+	_, err := exec.LookPath("notify-send")
+	if err == nil {
+		tl.Log(tl.Info, palette.Green, "%s desktop notifications with %s", "Sending", "notify-send")
+		return NotifySend{AppName: appName}
+	}
+
+	tl.Log(tl.Warning, palette.Yellow, "%s. %s", "No way to show desktop notifications", "Install libnotify-bin for notify-send")
+	return Nop{}
+}
@@ -0,0 +1,42 @@
+package notify
+
+import "time"
+
+/*
+Trigger decides when a condition-based reminder is due.
+
+The condition has to hold for `after` before the first reminder; then it
+repeats every `repeat` (never, when repeat is 0) while the condition keeps
+holding. When the condition stops, the next occurrence starts from scratch.
+*/
+type Trigger struct {
+	since     time.Time // when the condition started holding, zero when it doesn't
+	lastFired time.Time
+}
+
+/*
+Due reports whether a reminder should go out now. since is when the
+condition started (pass now if unknown); it's only read when holding.
+Call Fired once the reminder was actually sent.
+*/
+func (tr *Trigger) Due(holding bool, since, now time.Time, after, repeat time.Duration) bool {
+	if !holding {
+		tr.since = time.Time{}
+		return false
+	}
+	if tr.since.IsZero() || since.Before(tr.since) {
+		tr.since = since
+	}
+	if now.Sub(tr.since) < after {
+		return false
+	}
+	if tr.lastFired.Before(tr.since) {
+		return true // not reminded about this occurrence yet
+	}
+	return repeat > 0 && now.Sub(tr.lastFired) >= repeat
+}
+
+// Fired records that the reminder went out at now.
+func (tr *Trigger) Fired(now time.Time) {
+	tr.lastFired = now
+}
@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrigger(t *testing.T) {
+	start := time.Date(2026, 1, 23, 9, 0, 0, 0, time.UTC)
+	minute := func(m int) time.Time { return start.Add(time.Duration(m) * time.Minute) }
+
+	// one tick: whether the condition holds at now, since when, and whether a reminder is expected
+	type tick struct {
+		now     time.Time
+		holding bool
+		since   time.Time
+		due     bool
+	}
+	tests := []struct {
+		name          string
+		after, repeat time.Duration
+		ticks         []tick
+	}{
+		{"due once the condition held for after", 10 * time.Minute, 0, []tick{
+			{minute(0), true, minute(0), false},
+			{minute(9), true, minute(0), false},
+			{minute(10), true, minute(0), true},
+			{minute(30), true, minute(0), false}, // repeat 0 reminds once
+		}},
+		{"re-fires every repeat", 10 * time.Minute, 5 * time.Minute, []tick{
+			{minute(10), true, minute(0), true},
+			{minute(14), true, minute(0), false},
+			{minute(15), true, minute(0), true},
+			{minute(20), true, minute(0), true},
+		}},
+		{"a new occurrence starts from scratch", 10 * time.Minute, 0, []tick{
+			{minute(10), true, minute(0), true},
+			{minute(11), false, minute(11), false},
+			{minute(12), true, minute(12), false},
+			{minute(21), true, minute(12), false},
+			{minute(22), true, minute(12), true},
+		}},
+		{"an earlier since wins", 10 * time.Minute, 0, []tick{
+			{minute(5), true, minute(5), false},
+			{minute(10), true, minute(0), true},
+		}},
+		{"after 0 is due at once", 0, 0, []tick{
+			{minute(0), true, minute(0), true},
+			{minute(1), true, minute(0), false},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var trigger Trigger
+			for i, tick := range test.ticks {
+				due := trigger.Due(tick.holding, tick.since, tick.now, test.after, test.repeat)
+				if due != tick.due {
+					t.Fatalf("tick %v at %s: due %v, want %v", i, tick.now.Format(time.TimeOnly), due, tick.due)
+				}
+				if due {
+					trigger.Fired(tick.now)
+				}
+			}
+		})
+	}
+}
+
+func TestTriggerNotFiredStaysDue(t *testing.T) {
+	// a reminder held back (quiet hours) goes out on a later tick
+	start := time.Date(2026, 1, 23, 9, 0, 0, 0, time.UTC)
+	var trigger Trigger
+	for _, m := range []int{10, 20, 30} {
+		if !trigger.Due(true, start, start.Add(time.Duration(m)*time.Minute), 10*time.Minute, 0) {
+			t.Fatalf("not due at minute %v although it never fired", m)
+		}
+	}
+}
+
+func TestFake(t *testing.T) {
+	var fake Fake
+	fake.Notify(Notification{Key: "a"})
+	sent := fake.Sent()
+	fake.Notify(Notification{Key: "b"})
+	if len(sent) != 1 || len(fake.Sent()) != 2 || fake.Sent()[1].Key != "b" {
+		t.Errorf("sent %+v, then %+v", sent, fake.Sent())
+	}
+}
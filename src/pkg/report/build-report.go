@@ -2,43 +2,75 @@ package report
 
 import (
 	"bytes"
-	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
-	"strings"
 	"time"
 
 	tl "github.com/tuumbleweed/tintlog/logger"
 	"github.com/tuumbleweed/tintlog/palette"
 	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/locale"
+	"work-tracker/src/pkg/store"
 )
 
 /*
 Build the report: read files, aggregate, render HTML, write to disk.
+Text, dates and durations follow l, a nil locale is English.
+
+Day files are found under inputDir as laid out by layout.
+*/
+func BuildReport(inputDir string, layout *store.Layout, startDate, endDate time.Time, outPath string, barRef time.Duration, smooth float64, l *locale.Locale) (e *xerr.Error) {
+	l = orEnglish(l)
+	daySummaries, totals, e := SummarizeRange(inputDir, layout, startDate, endDate, smooth)
+	if e != nil {
+		return e
+	}
+
+	var buf bytes.Buffer
+	e = renderHTMLReport(&buf, daySummaries, totals, barRef, 200, startDate, endDate, l)
+	if e != nil {
+		return e
+	}
 
-Input layout (new):
-  <inputDir>/<YEAR>/<monthname>/<D>_<monthname>_<YEAR>.jsonl
-Example:
-  out/2026/january/23_january_2026.jsonl
+	// Ensure output directory exists (range can span years/months; outPath can be anywhere)
+	outDir := filepath.Dir(outPath)
+	if mkErr := os.MkdirAll(outDir, 0o755); mkErr != nil {
+		return xerr.NewErrorECOL(mkErr, "failed to create report output directory", "dir", outDir)
+	}
+
+	if err := os.WriteFile(outPath, buf.Bytes(), 0o644); err != nil {
+		return xerr.NewErrorECOL(err, "failed to write HTML report", "path", outPath)
+	}
+
+	tl.Log(tl.Notice, palette.Green, "%s report to '%s' (%s, %s days)",
+		"Wrote", outPath, l.Duration(totals.TotalWorked), len(daySummaries),
+	)
+	return nil
+}
+
+/*
+SummarizeRange reads day files for [startDate, endDate] and aggregates them
+without rendering anything. Used by BuildReport and by the tracker's report preview.
 */
-func BuildReport(inputDir string, startDate, endDate time.Time, outPath string, barRef time.Duration, smooth float64) (e *xerr.Error) {
+func SummarizeRange(inputDir string, layout *store.Layout, startDate, endDate time.Time, smooth float64) (daySummaries []DaySummary, totals ReportTotals, e *xerr.Error) {
 	tl.Log(tl.Notice, palette.Blue, "%s files from '%s' for '%s'..'%s'",
 		"Reading", inputDir, startDate.Format("02-01-2006"), endDate.Format("02-01-2006"),
 	)
 
-	dates := enumerateDates(startDate, endDate)
-	daySummaries := make([]DaySummary, 0, len(dates))
-	totals := ReportTotals{
-		PerTaskTotals: make(map[string]time.Duration),
+	totals = ReportTotals{
+		PerTaskTotals:   make(map[string]time.Duration),
+		PerReasonTotals: make(map[string]time.Duration),
+		PerReasonCounts: make(map[string]int),
+		PerTaskFocus:    make(map[string]FocusStats),
 	}
 
-	for _, d := range dates {
-		fp := dayFilePathYM(inputDir, d) // <-- updated path scheme (year/month)
-		sum, rerr := readDayFile(fp, d, smooth)
-		if rerr != nil {
-			return rerr
+	for day, e := range store.NewReader(inputDir, layout, store.Lenient).Days(startDate, endDate) {
+		if e != nil {
+			return daySummaries, totals, e
 		}
+		sum := summarizeDay(day, smooth)
 		daySummaries = append(daySummaries, sum)
 
 		totals.TotalWorked += sum.TotalDuration
@@ -46,6 +78,17 @@ func BuildReport(inputDir string, startDate, endDate time.Time, outPath string,
 		for k, v := range sum.TaskDurations {
 			totals.PerTaskTotals[k] += v
 		}
+
+		totals.TotalPaused += sum.TotalPaused
+		for reason, v := range sum.PauseDurations {
+			totals.PerReasonTotals[reason] += v
+			totals.PerReasonCounts[reason] += sum.PauseCounts[reason]
+		}
+		totals.WorkSessions += sum.WorkSessions
+		totals.LongestSession = max(totals.LongestSession, sum.LongestSession)
+		for task, stats := range sum.FocusByTask {
+			totals.PerTaskFocus[task] = totals.PerTaskFocus[task].add(stats)
+		}
 	}
 
 	for k := range totals.PerTaskTotals {
@@ -60,39 +103,38 @@ func BuildReport(inputDir string, startDate, endDate time.Time, outPath string,
 		return di > dj
 	})
 
-	var buf bytes.Buffer
-	renderHTMLReport(&buf, daySummaries, totals, barRef, 200, startDate, endDate)
-
-	// Ensure output directory exists (range can span years/months; outPath can be anywhere)
-	outDir := filepath.Dir(outPath)
-	if mkErr := os.MkdirAll(outDir, 0o755); mkErr != nil {
-		return xerr.NewErrorECOL(mkErr, "failed to create report output directory", "dir", outDir)
+	for reason := range totals.PerReasonTotals {
+		totals.ReasonOrder = append(totals.ReasonOrder, reason)
 	}
+	sort.Slice(totals.ReasonOrder, func(i, j int) bool {
+		di := totals.PerReasonTotals[totals.ReasonOrder[i]]
+		dj := totals.PerReasonTotals[totals.ReasonOrder[j]]
+		if di == dj {
+			return totals.ReasonOrder[i] < totals.ReasonOrder[j]
+		}
+		return di > dj
+	})
 
-	if err := os.WriteFile(outPath, buf.Bytes(), 0o644); err != nil {
-		return xerr.NewErrorECOL(err, "failed to write HTML report", "path", outPath)
+	for task := range totals.PerTaskFocus {
+		totals.FocusOrder = append(totals.FocusOrder, task)
 	}
+	sort.Slice(totals.FocusOrder, func(i, j int) bool {
+		si := totals.PerTaskFocus[totals.FocusOrder[i]]
+		sj := totals.PerTaskFocus[totals.FocusOrder[j]]
+		ci, cj := si.Pomodoros+si.Timeboxes, sj.Pomodoros+sj.Timeboxes
+		if ci == cj {
+			return totals.FocusOrder[i] < totals.FocusOrder[j]
+		}
+		return ci > cj
+	})
 
-	tl.Log(tl.Notice, palette.Green, "%s report to '%s' (%s, %s days)",
-		"Wrote", outPath, formatDuration(totals.TotalWorked), len(daySummaries),
-	)
-	return nil
-}
-
-// dayFilePathYM builds the per-day filepath for the new year/month layout.
-// Layout:
-//   <root>/<YYYY>/<monthname>/<D>_<monthname>_<YYYY>.jsonl
-func dayFilePathYM(root string, d time.Time) string {
-	year := d.Format("2006")
-	month := strings.ToLower(d.Format("January")) // "january"
-	day := d.Format("02")
-	fname := fmt.Sprintf("%s_%s_%s.jsonl", day, month, year)
-	return filepath.Join(root, year, month, fname)
+	return daySummaries, totals, nil
 }
 
-// resolveRange loads the TZ and determines [startDate, endDate] from flags.
+// ResolveRange loads the TZ and determines [startDate, endDate] from flags.
+// Without dates it is the current week, starting on firstWeekday.
 // Returns (*time.Location, startDate, endDate, *xerr.Error).
-func ResolveRange(flagTZ, flagStart, flagEnd string) (*time.Location, time.Time, time.Time, *xerr.Error) {
+func ResolveRange(flagTZ, flagStart, flagEnd string, firstWeekday time.Weekday) (*time.Location, time.Time, time.Time, *xerr.Error) {
 	loc, tzErr := time.LoadLocation(flagTZ)
 	if tzErr != nil {
 		return nil, time.Time{}, time.Time{}, xerr.NewErrorECOL(tzErr, "failed to load timezone", "tz", flagTZ)
@@ -106,7 +148,7 @@ func ResolveRange(flagTZ, flagStart, flagEnd string) (*time.Location, time.Time,
 
 	switch {
 	case flagStart == "" && flagEnd == "":
-		startDate, endDate = currentWeekRange(loc)
+		startDate, endDate = currentWeekRange(loc, firstWeekday)
 
 	case flagStart != "" && flagEnd == "":
 		startDate, err = parseDMY(flagStart, loc)
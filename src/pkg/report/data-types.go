@@ -1,19 +1,12 @@
 package report
 
 import (
-	"encoding/json"
-	"strings"
 	"time"
 )
 
 
-
-type Chunk struct {
-	TaskName   string       `json:"task_name"`
-	StartedAt  time.Time    `json:"started_at"`
-	FinishedAt time.Time    `json:"finished_at"`
-	ActiveTime JsonDuration `json:"active_time"`
-}
+// UnassignedTime is the task key for time tracked without a task, reports show it translated.
+const UnassignedTime = "Unassigned Time"
 
 /*
 Per-day aggregation used for charts.
@@ -24,6 +17,44 @@ type DaySummary struct {
 	TotalActive        time.Duration            `json:"total_active"`
 	TaskDurations      map[string]time.Duration `json:"task_durations"`
 	SmoothedActiveTime time.Duration            `json:"smoothed_active_time"` // Σ (duration * smooth(active_ratio))
+
+	// breaks and fragmentation
+	TotalPaused    time.Duration            `json:"total_paused"`
+	PauseDurations map[string]time.Duration `json:"pause_durations"` // by reason
+	PauseCounts    map[string]int           `json:"pause_counts"`    // by reason
+	WorkSessions   int                      `json:"work_sessions"`   // uninterrupted stretches of work
+	LongestSession time.Duration            `json:"longest_session"`
+
+	Notes []NoteBlock `json:"notes"` // in time order
+
+	FocusByTask map[string]FocusStats `json:"focus_by_task"`
+}
+
+// FocusStats counts the focus sessions' work intervals on one task.
+type FocusStats struct {
+	Pomodoros     int `json:"pomodoros"`     // completed
+	Timeboxes     int `json:"timeboxes"`     // completed
+	EndedEarly    int `json:"ended_early"`   // either mode, stopped before the time was up
+	Interruptions int `json:"interruptions"` // pauses and switches during them
+}
+
+func (s FocusStats) add(other FocusStats) FocusStats {
+	s.Pomodoros += other.Pomodoros
+	s.Timeboxes += other.Timeboxes
+	s.EndedEarly += other.EndedEarly
+	s.Interruptions += other.Interruptions
+	return s
+}
+
+/*
+NoteBlock is a stretch of work on one task with one note.
+Back-to-back chunks with the same task and note are merged into one block.
+*/
+type NoteBlock struct {
+	Task  string    `json:"task"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Note  string    `json:"note"`
 }
 
 /*
@@ -34,51 +65,15 @@ type ReportTotals struct {
 	TotalActive   time.Duration
 	PerTaskTotals map[string]time.Duration
 	TaskOrder     []string
-}
 
+	TotalPaused     time.Duration
+	PerReasonTotals map[string]time.Duration
+	PerReasonCounts map[string]int
+	ReasonOrder     []string // most paused first
+	WorkSessions    int
+	LongestSession  time.Duration
 
-/*
-JSONL input line from work-tracker.
-
-We allow ActiveTime to come either as a JSON number (nanoseconds) or as a string
-(parseable by time.ParseDuration, e.g. "999ms", "1.23s").
-*/
-type JsonDuration struct{ time.Duration }
-
-func (d *JsonDuration) UnmarshalJSON(b []byte) error {
-	s := strings.TrimSpace(string(b))
-	// number => nanoseconds
-	if len(s) > 0 && (s[0] == '-' || (s[0] >= '0' && s[0] <= '9')) && !strings.ContainsAny(s, `"abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ`) {
-		var n int64
-		dec := json.NewDecoder(strings.NewReader(s))
-		err := dec.Decode(&n)
-		if err != nil {
-			return err
-		}
-		d.Duration = time.Duration(n)
-		return nil
-	}
-	// string => parse duration
-	var str string
-	err := json.Unmarshal(b, &str)
-	if err != nil {
-		return err
-	}
-	parsed, perr := time.ParseDuration(str)
-	if perr != nil {
-		// try to parse bare integer string as nanoseconds
-		strTrim := strings.TrimSpace(str)
-		if strTrim != "" && strTrim[0] >= '0' && strTrim[0] <= '9' {
-			var n2 int64
-			dec := json.NewDecoder(strings.NewReader(strTrim))
-			e2 := dec.Decode(&n2)
-			if e2 == nil {
-				d.Duration = time.Duration(n2)
-				return nil
-			}
-		}
-		return perr
-	}
-	d.Duration = parsed
-	return nil
+	PerTaskFocus map[string]FocusStats
+	FocusOrder   []string // most completed first
 }
+
@@ -0,0 +1,23 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"work-tracker/src/pkg/locale"
+)
+
+/*
+EmailSubject builds the subject line used when sending a report, e.g.
+"Work Tracker · Weekly Report — 25 – 31 Oct 2025 · 2025-11-02 (Sun) 10:00:00".
+
+Non-breaking spaces keep mail clients from collapsing the separators.
+The date stays ISO so subjects sort, the weekday and clock follow the locale (nil is English).
+*/
+func EmailSubject(reportTitle string, sentAt time.Time, l *locale.Locale) string {
+	l = orEnglish(l)
+	return fmt.Sprintf(
+		"Work Tracker\u00A0\u00A0\u00A0·\u00A0\u00A0\u00A0%s\u00A0\u00A0\u00A0·\u00A0\u00A0\u00A0%s (%s) %s",
+		reportTitle, sentAt.Format("2006-01-02"), l.WeekdayShort(sentAt.Weekday()), l.ClockSeconds(sentAt),
+	)
+}
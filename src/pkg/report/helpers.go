@@ -58,30 +58,6 @@ func colorToHex(c color.Color) string {
 }
 
 
-/*
-Human-friendly duration like "1h 2m 3s".
-*/
-func formatDuration(d time.Duration) string {
-	if d <= 0 {
-		return "0s"
-	}
-	secs := int64(d.Seconds() + 0.5) // round
-	h := secs / 3600
-	m := (secs % 3600) / 60
-	s := secs % 60
-	out := &strings.Builder{}
-	if h > 0 {
-		fmt.Fprintf(out, "%dh ", h)
-	}
-	if m > 0 {
-		fmt.Fprintf(out, "%dm ", m)
-	}
-	if s > 0 && h == 0 {
-		fmt.Fprintf(out, "%ds", s)
-	}
-	return strings.TrimSpace(out.String())
-}
-
 /*
 Parse "DD-MM-YYYY" in a given location (00:00 that day).
 */
@@ -94,36 +70,14 @@ func parseDMY(s string, loc *time.Location) (time.Time, error) {
 }
 
 /*
-Compute Monday..Sunday of the current week in the given location.
+Compute the current week in the given location, starting on firstWeekday.
 */
-func currentWeekRange(loc *time.Location) (time.Time, time.Time) {
+func currentWeekRange(loc *time.Location, firstWeekday time.Weekday) (time.Time, time.Time) {
 	now := time.Now().In(loc)
 	base := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
-	wd := int(base.Weekday())
-	if wd == 0 {
-		wd = 7 // Sunday->7
-	}
-	monday := base.AddDate(0, 0, -(wd-1))
-	sunday := monday.AddDate(0, 0, 6)
-	return monday, sunday
-}
-
-
-
-/*
-Day-of-week short label.
-*/
-func weekdayShort(t time.Time) string { return t.Weekday().String()[:3] }
-
-/*
-Enumerate dates.
-*/
-func enumerateDates(start, end time.Time) []time.Time {
-	var dates []time.Time
-	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
-		dates = append(dates, d)
-	}
-	return dates
+	first := weekStartOf(base, firstWeekday)
+	last := first.AddDate(0, 0, 6)
+	return first, last
 }
 
 // Gmail-safe "10 squares" indicator.
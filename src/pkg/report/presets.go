@@ -0,0 +1,90 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"work-tracker/src/pkg/locale"
+)
+
+// Preset is a named report period, resolved relative to "now".
+type Preset string
+
+const (
+	PresetToday       Preset = "today"
+	PresetYesterday   Preset = "yesterday"
+	PresetThisWeek    Preset = "this-week"
+	PresetLastWeek    Preset = "last-week"
+	PresetThisMonth   Preset = "this-month"
+	PresetLastMonth   Preset = "last-month"
+	PresetThisQuarter Preset = "this-quarter"
+	PresetThisYear    Preset = "this-year"
+	PresetLast7Days   Preset = "last-7-days"
+	PresetLast30Days  Preset = "last-30-days"
+)
+
+// AllPresets is the order presets are offered in (tracker's report dialog).
+var AllPresets = []Preset{
+	PresetToday, PresetYesterday, PresetThisWeek, PresetLastWeek, PresetThisMonth,
+	PresetLastMonth, PresetThisQuarter, PresetThisYear, PresetLast7Days, PresetLast30Days,
+}
+
+/*
+PresetRange returns [startDate, endDate] (both 00:00, inclusive) for a preset.
+Weeks start on firstWeekday (the locale's), same as currentWeekRange.
+*/
+func PresetRange(preset Preset, now time.Time, firstWeekday time.Weekday) (startDate, endDate time.Time, err error) {
+	loc := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	switch preset {
+	case PresetToday:
+		return today, today, nil
+	case PresetYesterday:
+		yesterday := today.AddDate(0, 0, -1)
+		return yesterday, yesterday, nil
+	case PresetThisWeek:
+		first := weekStartOf(today, firstWeekday)
+		return first, first.AddDate(0, 0, 6), nil
+	case PresetLastWeek:
+		first := weekStartOf(today, firstWeekday).AddDate(0, 0, -7)
+		return first, first.AddDate(0, 0, 6), nil
+	case PresetThisMonth:
+		first := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, loc)
+		return first, first.AddDate(0, 1, -1), nil
+	case PresetLastMonth:
+		first := time.Date(today.Year(), today.Month()-1, 1, 0, 0, 0, 0, loc)
+		return first, first.AddDate(0, 1, -1), nil
+	case PresetThisQuarter:
+		first, _ := quarterOf(today)
+		return first, first.AddDate(0, 3, -1), nil
+	case PresetThisYear:
+		first := time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, loc)
+		return first, first.AddDate(1, 0, -1), nil
+	case PresetLast7Days:
+		return today.AddDate(0, 0, -6), today, nil
+	case PresetLast30Days:
+		return today.AddDate(0, 0, -29), today, nil
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("unknown report preset: '%s'", preset)
+	}
+}
+
+// Title for a given range, same one that ends up in the HTML <title>. A nil locale is English.
+func Title(startDate, endDate time.Time, l *locale.Locale) string {
+	return reportTitle(startDate, endDate, orEnglish(l))
+}
+
+// weekStartOf goes back from day to the nearest firstWeekday
+func weekStartOf(day time.Time, firstWeekday time.Weekday) time.Time {
+	back := (int(day.Weekday()) - int(firstWeekday) + 7) % 7
+	return day.AddDate(0, 0, -back)
+}
+
+// orEnglish lets callers pass a nil locale
+func orEnglish(l *locale.Locale) *locale.Locale {
+	if l == nil {
+		return locale.English()
+	}
+	return l
+}
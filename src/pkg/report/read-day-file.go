@@ -1,85 +1,70 @@
 package report
 
 import (
-	"bufio"
-	"encoding/json"
 	"math"
-	"os"
+	"sort"
 	"strings"
 	"time"
 
 	tl "github.com/tuumbleweed/tintlog/logger"
 	"github.com/tuumbleweed/tintlog/palette"
-	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/store"
 )
 
 /*
-Read a single day file into a DaySummary. Missing file => empty summary (no error).
+summarizeDay aggregates one day file's chunks, a missing file is an empty day.
+The store has already left out the lines that aren't valid chunks.
 */
-func readDayFile(filePath string, date time.Time, smooth float64) (sum DaySummary, e *xerr.Error) {
+func summarizeDay(day store.Day, smooth float64) (sum DaySummary) {
 	sum = DaySummary{
-		Date:               date,
+		Date:               day.Date,
 		TaskDurations:      make(map[string]time.Duration),
 		TotalDuration:      0,
 		TotalActive:        0,
 		SmoothedActiveTime: 0,
+		PauseDurations:     make(map[string]time.Duration),
+		PauseCounts:        make(map[string]int),
+		FocusByTask:        make(map[string]FocusStats),
 	}
 
-	_, statErr := os.Stat(filePath)
-	if statErr != nil {
-		if os.IsNotExist(statErr) {
-			tl.Log(tl.Notice, palette.Cyan, "%s missing day file '%s' (treated as 0)", "Skipping", filePath)
-			return sum, nil
-		}
-		e = xerr.NewErrorECOL(statErr, "unable to stat day file", "path", filePath)
-		return sum, e
-	}
-
-	f, openErr := os.Open(filePath)
-	if openErr != nil {
-		e = xerr.NewErrorECOL(openErr, "unable to open day file", "path", filePath)
-		return sum, e
-	}
-	defer f.Close()
-
-	sc := bufio.NewScanner(f)
-	buf := make([]byte, 0, 64*1024)
-	sc.Buffer(buf, 2*1024*1024)
-
-	lineNumber := 0
-	sum.TaskDurations["Unassigned Time"] = 1 * time.Nanosecond // add this to have it take first (gray) color always, even if not present
-	for sc.Scan() {
-		line := strings.TrimSpace(sc.Text())
-		lineNumber++
-		if line == "" {
+	var workIntervals []interval
+	sum.TaskDurations[UnassignedTime] = 1 * time.Nanosecond // add this to have it take first (gray) color always, even if not present
+	for i, ch := range day.Chunks {
+		dur := ch.Duration()
+		if ch.Kind == store.ChunkKindPause {
+			reason := ch.PauseReason
+			if strings.TrimSpace(reason) == "" {
+				reason = "break"
+			}
+			sum.TotalPaused += dur
+			sum.PauseDurations[reason] += dur
+			sum.PauseCounts[reason]++
 			continue
 		}
-		var ch Chunk
-		uErr := json.Unmarshal([]byte(line), &ch)
-		if uErr != nil {
-			tl.Log(tl.Notice, palette.Purple, "%s malformed JSON in '%s' line %d", "Skipping", filePath, lineNumber)
+		if ch.Kind == store.ChunkKindFocus {
+			task := ch.TaskName
+			if strings.TrimSpace(task) == "" {
+				task = UnassignedTime
+			}
+			sum.FocusByTask[task] = sum.FocusByTask[task].add(focusStats(*ch.Focus))
 			continue
 		}
-		if !ch.FinishedAt.After(ch.StartedAt) {
-			tl.Log(tl.Notice, palette.Purple, "%s bad chunk time window in '%s' line %d", "Skipping", filePath, lineNumber)
+		if ch.Kind != store.ChunkKindWork {
+			tl.Log(tl.Notice, palette.Purple, "%s unknown chunk kind '%s' in '%s', chunk %d", "Skipping", ch.Kind, day.Path, i+1)
 			continue
 		}
-		dur := ch.FinishedAt.Sub(ch.StartedAt)
-		active := ch.ActiveTime.Duration
-		if active < 0 {
-			active = 0
-		}
-		if active > dur {
-			active = dur
-		}
+		workIntervals = append(workIntervals, interval{start: ch.StartedAt, end: ch.FinishedAt})
+		active := ch.ActiveTime // validated to be within the chunk
 		sum.TotalDuration += dur
 		sum.TotalActive += active
 
 		task := ch.TaskName
 		if strings.TrimSpace(task) == "" {
-			task = "Unassigned Time"
+			task = UnassignedTime
 		}
 		sum.TaskDurations[task] += dur
+		sum.Notes = addNote(sum.Notes, task, ch)
 
 		ratio := 0.0
 		if dur > 0 {
@@ -88,13 +73,70 @@ func readDayFile(filePath string, date time.Time, smooth float64) (sum DaySummar
 		sm := smoothFactor(ratio, smooth)
 		sum.SmoothedActiveTime += time.Duration(float64(dur) * sm)
 	}
-	sErr := sc.Err()
-	if sErr != nil {
-		e = xerr.NewErrorECML(sErr, "scanner error while reading day file", "line",
-			map[string]any{"path": filePath, "last_line": lineNumber})
-		return sum, e
+	sum.WorkSessions, sum.LongestSession = workSessions(workIntervals)
+	sort.SliceStable(sum.Notes, func(i, j int) bool { return sum.Notes[i].Start.Before(sum.Notes[j].Start) })
+	return sum
+}
+
+// addNote appends ch's note as a block, or extends the last block when ch continues it
+func addNote(notes []NoteBlock, task string, ch store.Chunk) []NoteBlock {
+	note := strings.TrimSpace(ch.Note)
+	if note == "" {
+		return notes
+	}
+	const maxGap = time.Second // same tolerance as workSessions
+	if n := len(notes); n > 0 {
+		last := &notes[n-1]
+		if last.Task == task && last.Note == note && ch.StartedAt.Sub(last.End) <= maxGap && !ch.StartedAt.Before(last.Start) {
+			last.End = ch.FinishedAt
+			return notes
+		}
+	}
+	return append(notes, NoteBlock{Task: task, Start: ch.StartedAt, End: ch.FinishedAt, Note: note})
+}
+
+// focusStats counts a single focus record
+func focusStats(record store.FocusRecord) (stats FocusStats) {
+	switch {
+	case !record.Completed:
+		stats.EndedEarly = 1
+	case record.Mode == store.FocusModeTimebox:
+		stats.Timeboxes = 1
+	default:
+		stats.Pomodoros = 1
+	}
+	stats.Interruptions = record.Interruptions
+	return stats
+}
+
+type interval struct{ start, end time.Time }
+
+/*
+workSessions merges touching work chunks into sessions (the tracker writes a
+running session as back-to-back chunks) and returns how many there are and the longest one.
+*/
+func workSessions(intervals []interval) (count int, longest time.Duration) {
+	if len(intervals) == 0 {
+		return 0, 0
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].start.Before(intervals[j].start) })
+
+	const maxGap = time.Second // flush timestamps are rounded, allow tiny gaps
+	current := intervals[0]
+	count = 1
+	for _, next := range intervals[1:] {
+		if next.start.Sub(current.end) <= maxGap {
+			if next.end.After(current.end) {
+				current.end = next.end
+			}
+			continue
+		}
+		longest = max(longest, current.end.Sub(current.start))
+		current = next
+		count++
 	}
-	return sum, nil
+	longest = max(longest, current.end.Sub(current.start))
+	return count, longest
 }
 
 /*
@@ -0,0 +1,159 @@
+package report
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"work-tracker/src/pkg/store"
+)
+
+var testDay = time.Date(2026, 1, 23, 0, 0, 0, 0, time.UTC)
+
+// at is a time on testDay
+func at(hour, minute, second int) time.Time {
+	return time.Date(testDay.Year(), testDay.Month(), testDay.Day(), hour, minute, second, 0, time.UTC)
+}
+
+func TestWorkSessions(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []interval
+		count     int
+		longest   time.Duration
+	}{
+		{"none", nil, 0, 0},
+		{"one", []interval{{at(9, 0, 0), at(9, 30, 0)}}, 1, 30 * time.Minute},
+		{"back to back", []interval{{at(9, 0, 0), at(9, 10, 0)}, {at(9, 10, 0), at(9, 20, 0)}}, 1, 20 * time.Minute},
+		{"gap equal to the tolerance", []interval{{at(9, 0, 0), at(9, 10, 0)}, {at(9, 10, 1), at(9, 20, 0)}}, 1, 20 * time.Minute},
+		{"gap past the tolerance", []interval{{at(9, 0, 0), at(9, 10, 0)}, {at(9, 10, 2), at(9, 20, 0)}}, 2, 10 * time.Minute},
+		{"out of order", []interval{{at(11, 0, 0), at(11, 5, 0)}, {at(9, 0, 0), at(9, 10, 0)}, {at(9, 10, 0), at(9, 40, 0)}}, 2, 40 * time.Minute},
+		{"overlapping and contained", []interval{{at(9, 0, 0), at(9, 30, 0)}, {at(9, 10, 0), at(9, 20, 0)}, {at(9, 25, 0), at(9, 45, 0)}}, 1, 45 * time.Minute},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			count, longest := workSessions(test.intervals)
+			if count != test.count || longest != test.longest {
+				t.Errorf("workSessions = %v, %s; want %v, %s", count, longest, test.count, test.longest)
+			}
+		})
+	}
+}
+
+func TestSummarizeDayPauses(t *testing.T) {
+	work := func(taskName string, from, to time.Time) store.Chunk {
+		return store.Chunk{TaskName: taskName, StartedAt: from, FinishedAt: to, ActiveTime: to.Sub(from) / 2}
+	}
+	pause := func(reason string, from, to time.Time) store.Chunk {
+		return store.Chunk{Kind: store.ChunkKindPause, PauseReason: reason, StartedAt: from, FinishedAt: to}
+	}
+	tests := []struct {
+		name           string
+		chunks         []store.Chunk
+		total, paused  time.Duration
+		sessions       int
+		longest        time.Duration
+		pauseDurations map[string]time.Duration
+		pauseCounts    map[string]int
+	}{
+		{
+			name: "a pause splits the session",
+			chunks: []store.Chunk{
+				work("Email", at(9, 0, 0), at(9, 30, 0)),
+				pause("lunch", at(9, 30, 0), at(10, 0, 0)),
+				work("Email", at(10, 0, 0), at(10, 10, 0)),
+			},
+			total: 40 * time.Minute, paused: 30 * time.Minute, sessions: 2, longest: 30 * time.Minute,
+			pauseDurations: map[string]time.Duration{"lunch": 30 * time.Minute},
+			pauseCounts:    map[string]int{"lunch": 1},
+		},
+		{
+			name: "a pause without a reason is a break",
+			chunks: []store.Chunk{
+				work("Email", at(9, 0, 0), at(9, 10, 0)),
+				pause("", at(9, 10, 0), at(9, 15, 0)),
+				pause(" ", at(9, 15, 0), at(9, 20, 0)),
+				work("Email", at(9, 20, 0), at(9, 30, 0)),
+			},
+			total: 20 * time.Minute, paused: 10 * time.Minute, sessions: 2, longest: 10 * time.Minute,
+			pauseDurations: map[string]time.Duration{"break": 10 * time.Minute},
+			pauseCounts:    map[string]int{"break": 2},
+		},
+		{
+			name: "unknown kinds are skipped",
+			chunks: []store.Chunk{
+				work("Email", at(9, 0, 0), at(9, 10, 0)),
+				{Kind: "meeting", TaskName: "Email", StartedAt: at(9, 10, 0), FinishedAt: at(9, 40, 0)},
+				work("Review", at(9, 10, 0), at(9, 20, 0)),
+			},
+			total: 20 * time.Minute, sessions: 1, longest: 20 * time.Minute,
+			pauseDurations: map[string]time.Duration{},
+			pauseCounts:    map[string]int{},
+		},
+		{
+			name: "focus records aren't worked time",
+			chunks: []store.Chunk{
+				work("Email", at(9, 0, 0), at(9, 25, 0)),
+				{Kind: store.ChunkKindFocus, TaskName: "Email", StartedAt: at(9, 0, 0), FinishedAt: at(9, 25, 0), Focus: &store.FocusRecord{Mode: store.FocusModePomodoro, Planned: 25 * time.Minute, Completed: true}},
+			},
+			total: 25 * time.Minute, sessions: 1, longest: 25 * time.Minute,
+			pauseDurations: map[string]time.Duration{},
+			pauseCounts:    map[string]int{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sum := summarizeDay(store.Day{Date: testDay, Path: "test.jsonl", Chunks: test.chunks}, 0)
+			if sum.TotalDuration != test.total || sum.TotalPaused != test.paused {
+				t.Errorf("worked %s, paused %s; want %s, %s", sum.TotalDuration, sum.TotalPaused, test.total, test.paused)
+			}
+			if sum.WorkSessions != test.sessions || sum.LongestSession != test.longest {
+				t.Errorf("sessions %v, longest %s; want %v, %s", sum.WorkSessions, sum.LongestSession, test.sessions, test.longest)
+			}
+			if len(sum.PauseDurations) != len(test.pauseDurations) || len(sum.PauseCounts) != len(test.pauseCounts) {
+				t.Fatalf("pauses %v, counts %v; want %v, %v", sum.PauseDurations, sum.PauseCounts, test.pauseDurations, test.pauseCounts)
+			}
+			for reason, duration := range test.pauseDurations {
+				if sum.PauseDurations[reason] != duration || sum.PauseCounts[reason] != test.pauseCounts[reason] {
+					t.Errorf("pause '%s': %s in %v, want %s in %v", reason, sum.PauseDurations[reason], sum.PauseCounts[reason], duration, test.pauseCounts[reason])
+				}
+			}
+		})
+	}
+}
+
+func TestSummarizeRangeClampsActiveTime(t *testing.T) {
+	workDir := t.TempDir()
+	layout := store.MustParseLayout(store.DefaultLayout)
+	dir, filePath := layout.DayFilePath(workDir, testDay)
+	lines := `{"task_name":"Email","started_at":"2026-01-23T09:00:00Z","finished_at":"2026-01-23T09:30:00Z","active_time":"45m"}
+{"task_name":"Email","started_at":"2026-01-23T09:30:00Z","finished_at":"2026-01-23T09:40:00Z","active_time":"-1m"}
+{"task_name":"Review","started_at":"2026-01-23T10:00:00Z","finished_at":"2026-01-23T10:20:00Z","active_time":"10m"}
+{"task_name":"Review","started_at":"2026-01-23T11:00:00Z","finished_at":"2026-01-23T10:50:00Z","active_time":"1m"}
+`
+	err := os.MkdirAll(dir, 0o755)
+	if err == nil {
+		err = os.WriteFile(filePath, []byte(lines), 0o644)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the over-long and negative active times count clamped, the chunk that ends before it starts doesn't
+	daySummaries, totals, e := SummarizeRange(workDir, layout, testDay, testDay, 0)
+	if e != nil {
+		t.Fatalf("SummarizeRange: %s", e.Msg)
+	}
+	if len(daySummaries) != 1 {
+		t.Fatalf("%v days, want 1", len(daySummaries))
+	}
+	if want := time.Hour; totals.TotalWorked != want {
+		t.Errorf("worked %s, want %s", totals.TotalWorked, want)
+	}
+	if want := 40 * time.Minute; totals.TotalActive != want {
+		t.Errorf("active %s, want %s", totals.TotalActive, want)
+	}
+	if want := 40 * time.Minute; totals.PerTaskTotals["Email"] != want {
+		t.Errorf("Email %s, want %s", totals.PerTaskTotals["Email"], want)
+	}
+}
@@ -2,13 +2,21 @@ package report
 
 import (
 	"bytes"
-	"fmt"
 	"html/template"
 	"math"
+	"path/filepath"
 	"sort"
+	"strings"
 	"time"
+
+	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/locale"
 )
 
+// TemplatePath is where renderHTMLReport looks for the HTML template (relative to the project dir).
+var TemplatePath = "./cfg/report-template.html"
+
 type reportTaskVM struct {
 	ColorHex string
 	Name     string
@@ -39,12 +47,48 @@ type reportActivityDayVM struct {
 	DayLabel string
 }
 
+type reportBreakVM struct {
+	Reason   string
+	Duration string
+	Count    int
+	Average  string
+}
+
+type reportBreakDayVM struct {
+	DayLabel string
+	Sessions int
+	Breaks   int
+	Paused   string
+}
+
+type reportFocusVM struct {
+	Task          string
+	Pomodoros     int
+	Timeboxes     int
+	EndedEarly    int
+	Interruptions int
+}
+
+type reportNoteVM struct {
+	TimeRange string // "09:05–10:40"
+	Duration  string
+	Task      string
+	Note      string
+}
+
+type reportNoteDayVM struct {
+	DayLabel string
+	Notes    []reportNoteVM
+}
+
 type reportTemplateVM struct {
+	Lang  string // the locale's language tag, for <html lang>
 	Title string
 
 	TotalWorked string
 
-	AvgActivity float64
+	AvgActivity      float64
+	AvgActivityLabel string // AvgActivity with the locale's decimal separator
 	// NOTE: if you want *zero* HTML generation in Go, convert buildSquares10HTML()
 	// to return data and render it in the template.
 	ActivitySquares template.HTML
@@ -57,6 +101,20 @@ type reportTemplateVM struct {
 
 	BarRefLabel string
 
+	// breaks and fragmentation
+	ShowBreaks     bool
+	TotalPaused    string
+	BreakCount     int
+	WorkSessions   int
+	AvgSession     string
+	LongestSession string
+	Breaks         []reportBreakVM
+	BreakDays      []reportBreakDayVM // weekly mode only
+
+	Focus []reportFocusVM // per task, empty when no focus sessions were recorded
+
+	NoteDays []reportNoteDayVM // weekly mode only, days without notes are left out
+
 	ChartW     int
 	PadPx      int
 	BarWPx     int
@@ -76,8 +134,10 @@ Render the entire HTML report into a buffer.
 
 barRef      -> target duration label (e.g., 12m).
 barHeightPx -> pixel height that corresponds to barRef (used to scale bars).
+l           -> language of the labels and format of dates, durations and numbers.
+               The template translates its own text with {{ t "MessageID" }}.
 */
-func renderHTMLReport(buf *bytes.Buffer, daySummaries []DaySummary, totals ReportTotals, barRef time.Duration, barHeightPx int, startDate, endDate time.Time) {
+func renderHTMLReport(buf *bytes.Buffer, daySummaries []DaySummary, totals ReportTotals, barRef time.Duration, barHeightPx int, startDate, endDate time.Time, l *locale.Locale) (e *xerr.Error) {
 	// ---------- precompute ----------
 	refSeconds := barRef.Seconds()
 	if refSeconds <= 0 {
@@ -135,12 +195,12 @@ func renderHTMLReport(buf *bytes.Buffer, daySummaries []DaySummary, totals Repor
 		taskNames = append(taskNames, k)
 	}
 	sort.Slice(taskNames, func(i, j int) bool {
-		// pin "Unassigned Time" to the top
+		// pin UnassignedTime to the top
 		ai, aj := taskNames[i], taskNames[j]
-		if ai == "Unassigned Time" && aj != "Unassigned Time" {
+		if ai == UnassignedTime && aj != UnassignedTime {
 			return true
 		}
-		if aj == "Unassigned Time" && ai != "Unassigned Time" {
+		if aj == UnassignedTime && ai != UnassignedTime {
 			return false
 		}
 
@@ -215,8 +275,8 @@ func renderHTMLReport(buf *bytes.Buffer, daySummaries []DaySummary, totals Repor
 		dur := totals.PerTaskTotals[name]
 		tasksVM = append(tasksVM, reportTaskVM{
 			ColorHex: taskColorHex(i, name),
-			Name:     name,
-			Duration: formatDuration(dur),
+			Name:     taskLabel(name, l),
+			Duration: l.Duration(dur),
 		})
 	}
 
@@ -255,11 +315,11 @@ func renderHTMLReport(buf *bytes.Buffer, daySummaries []DaySummary, totals Repor
 
 		label := ""
 		if weeklyMode {
-			label = weekdayShort(dsum.Date)
+			label = l.WeekdayShort(dsum.Date.Weekday())
 		} else {
 			// keep your existing logic: labelStep currently 0 -> blanks
 			if labelStep != 0 && (dayIdx%labelStep == 0) {
-				label = weekdayShort(dsum.Date)
+				label = l.WeekdayShort(dsum.Date.Weekday())
 			}
 		}
 
@@ -267,7 +327,7 @@ func renderHTMLReport(buf *bytes.Buffer, daySummaries []DaySummary, totals Repor
 			ContainerHeightPx: containerH,
 			TopSpacerPx:       topSpacer,
 			Segments:          segs,
-			HoursLabel:        fmt.Sprintf("%.1fh", dsum.TotalDuration.Hours()),
+			HoursLabel:        l.Hours(dsum.TotalDuration),
 			DayLabel:          label,
 		})
 	}
@@ -292,10 +352,10 @@ func renderHTMLReport(buf *bytes.Buffer, daySummaries []DaySummary, totals Repor
 
 		label := ""
 		if weeklyMode {
-			label = weekdayShort(dsum.Date)
+			label = l.WeekdayShort(dsum.Date.Weekday())
 		} else {
 			if labelStep != 0 && (dayIdx%labelStep == 0) {
-				label = weekdayShort(dsum.Date)
+				label = l.WeekdayShort(dsum.Date.Weekday())
 			}
 		}
 
@@ -304,23 +364,105 @@ func renderHTMLReport(buf *bytes.Buffer, daySummaries []DaySummary, totals Repor
 			TopSpacerPx:       top,
 			BarHeightPx:       h,
 			BarColorHex:       hex,
-			PctLabel:          fmt.Sprintf("%.0f%%", dayPct),
+			PctLabel:          l.Percent(dayPct, 0),
 			DayLabel:          label,
 		})
 	}
 
+	breaksVM := make([]reportBreakVM, 0, len(totals.ReasonOrder))
+	breakCount := 0
+	for _, reason := range totals.ReasonOrder {
+		count := totals.PerReasonCounts[reason]
+		breakCount += count
+		average := time.Duration(0)
+		if count > 0 {
+			average = totals.PerReasonTotals[reason] / time.Duration(count)
+		}
+		breaksVM = append(breaksVM, reportBreakVM{
+			Reason:   pauseReasonLabel(reason, l),
+			Duration: l.Duration(totals.PerReasonTotals[reason]),
+			Count:    count,
+			Average:  l.Duration(average),
+		})
+	}
+	avgSession := time.Duration(0)
+	if totals.WorkSessions > 0 {
+		avgSession = totals.TotalWorked / time.Duration(totals.WorkSessions)
+	}
+	var breakDaysVM []reportBreakDayVM
+	if weeklyMode {
+		for _, dsum := range daySummaries {
+			dayBreaks := 0
+			for _, count := range dsum.PauseCounts {
+				dayBreaks += count
+			}
+			breakDaysVM = append(breakDaysVM, reportBreakDayVM{
+				DayLabel: l.WeekdayShort(dsum.Date.Weekday()),
+				Sessions: dsum.WorkSessions,
+				Breaks:   dayBreaks,
+				Paused:   l.Duration(dsum.TotalPaused),
+			})
+		}
+	}
+
+	focusVM := make([]reportFocusVM, 0, len(totals.FocusOrder))
+	for _, task := range totals.FocusOrder {
+		stats := totals.PerTaskFocus[task]
+		focusVM = append(focusVM, reportFocusVM{
+			Task:          taskLabel(task, l),
+			Pomodoros:     stats.Pomodoros,
+			Timeboxes:     stats.Timeboxes,
+			EndedEarly:    stats.EndedEarly,
+			Interruptions: stats.Interruptions,
+		})
+	}
+
+	var noteDaysVM []reportNoteDayVM
+	if weeklyMode {
+		for _, dsum := range daySummaries {
+			if len(dsum.Notes) == 0 {
+				continue
+			}
+			noteDay := reportNoteDayVM{DayLabel: l.DateLong(dsum.Date)}
+			for _, block := range dsum.Notes {
+				noteDay.Notes = append(noteDay.Notes, reportNoteVM{
+					TimeRange: l.Clock(block.Start.In(dsum.Date.Location())) + "–" + l.Clock(block.End.In(dsum.Date.Location())),
+					Duration:  l.Duration(block.End.Sub(block.Start)),
+					Task:      taskLabel(block.Task, l),
+					Note:      block.Note,
+				})
+			}
+			noteDaysVM = append(noteDaysVM, noteDay)
+		}
+	}
+
 	vm := reportTemplateVM{
-		Title: reportTitle(startDate, endDate),
+		Lang:  l.Tag.String(),
+		Title: reportTitle(startDate, endDate, l),
 
-		TotalWorked: formatDuration(totals.TotalWorked),
+		TotalWorked: l.Duration(totals.TotalWorked),
 
 		AvgActivity:      avgActivity,
+		AvgActivityLabel: l.Percent(avgActivity, 1),
 		ActivitySquares:  template.HTML(buildSquares10HTML(avgActivity, activityHex)),
 		Tasks:            tasksVM,
 		TimeByDayDays:    timeDaysVM,
 		ActivityByTimeDays: activityDaysVM,
 
-		BarRefLabel: formatDuration(barRef),
+		BarRefLabel: l.Duration(barRef),
+
+		ShowBreaks:     totals.TotalPaused > 0 || totals.WorkSessions > 0,
+		TotalPaused:    l.Duration(totals.TotalPaused),
+		BreakCount:     breakCount,
+		WorkSessions:   totals.WorkSessions,
+		AvgSession:     l.Duration(avgSession),
+		LongestSession: l.Duration(totals.LongestSession),
+		Breaks:         breaksVM,
+		BreakDays:      breakDaysVM,
+
+		Focus: focusVM,
+
+		NoteDays: noteDaysVM,
 
 		ChartW:     chartW,
 		PadPx:      pad,
@@ -336,12 +478,34 @@ func renderHTMLReport(buf *bytes.Buffer, daySummaries []DaySummary, totals Repor
 		Hex100: hex100,
 	}
 
-	tpl, err := template.ParseFiles("./cfg/report-template.html")
+	// return errors instead of panicking: the tracker renders reports in-process now
+	funcs := template.FuncMap{"t": l.T}
+	tpl, err := template.New(filepath.Base(TemplatePath)).Funcs(funcs).ParseFiles(TemplatePath)
 	if err != nil {
-		// match old behavior style: fail hard rather than silently changing output
-		panic(err)
+		return xerr.NewErrorECOL(err, "failed to parse report template", "path", TemplatePath)
 	}
 	if err := tpl.Execute(buf, vm); err != nil {
-		panic(err)
+		return xerr.NewErrorECOL(err, "failed to execute report template", "path", TemplatePath)
+	}
+	return nil
+}
+
+// taskLabel is the task name as shown in the report, UnassignedTime translated
+func taskLabel(name string, l *locale.Locale) string {
+	if name == UnassignedTime {
+		return l.T("UnassignedTime")
+	}
+	return name
+}
+
+// pauseReasonLabel translates the reasons the tracker offers, custom ones are shown as written
+func pauseReasonLabel(reason string, l *locale.Locale) string {
+	if reason == "" {
+		return reason
+	}
+	id := "PauseReason" + strings.ToUpper(reason[:1]) + reason[1:]
+	if !l.Has(id) {
+		return reason
 	}
+	return l.T(id)
 }
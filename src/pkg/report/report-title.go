@@ -1,62 +1,44 @@
 package report
 
 import (
-	"fmt"
 	"time"
+
+	"work-tracker/src/pkg/locale"
 )
 
-// Format adaptive titles like:
+// Format adaptive titles like (English):
 // "Daily Report — 02 Nov 2025"
 // "Weekly Report — 25 – 31 Oct 2025"
 // "Monthly Report — Oct 2025"
 // "Quarterly Report — Q4 2025"
 // "Yearly Report — 2025"
-// "Custom Report — 25 Oct 2025 – 04 Nov 2025"
-func reportTitle(start, end time.Time) string {
+// "Report — 25 Oct – 04 Nov 2025"
+func reportTitle(start, end time.Time, l *locale.Locale) string {
 	start = start.Local()
 	end = end.Local()
 
-	label := periodLabel(start, end)
+	label := periodLabel(start, end, l)
 
+	var period string
 	switch label {
 	case "Daily":
-		return fmt.Sprintf("Daily Report — %s", start.Format("02 Jan 2006"))
-
+		period = l.Date(start)
 	case "Weekly":
-		// same year? print year once
-		if start.Year() == end.Year() && start.Month() == end.Month() {
-			return fmt.Sprintf("Weekly Report — %s – %s %d", start.Format("02"), end.Format("02 Jan"), end.Year())
-		}
-		if start.Year() == end.Year() {
-			return fmt.Sprintf("Weekly Report — %s – %s %d", start.Format("02 Jan"), end.Format("02 Jan"), end.Year())
-		}
-		return fmt.Sprintf("Weekly Report — %s – %s", start.Format("02 Jan 2006"), end.Format("02 Jan 2006"))
-
+		period = l.DateRange(start, end) // shared month and year printed once
 	case "Monthly":
-		return fmt.Sprintf("Monthly Report — %s %d", start.Format("Jan"), start.Year())
-
+		period = l.MonthYear(start)
 	case "Quarterly":
 		_, q := quarterOf(start)
-		return fmt.Sprintf("Quarterly Report — Q%d %d", q, start.Year())
-
+		period = l.T("QuarterYear", "Quarter", q, "Year", start.Year())
 	case "Yearly":
-		return fmt.Sprintf("Yearly Report — %d", start.Year())
-
+		period = start.Format("2006")
 	default: // "Custom"
-		if start.Equal(end) {
-			return fmt.Sprintf("Report — %s", start.Format("02 Jan 2006"))
-		}
-		if start.Year() == end.Year() && start.Month() == end.Month() {
-			return fmt.Sprintf("Report — %s – %s %d", start.Format("02"), end.Format("02 Jan"), end.Year())
-		}
-		if start.Year() == end.Year() {
-			return fmt.Sprintf("Report — %s – %s %d", start.Format("02 Jan"), end.Format("02 Jan"), end.Year())
-		}
-		return fmt.Sprintf("Report — %s – %s", start.Format("02 Jan 2006"), end.Format("02 Jan 2006"))
+		period = l.DateRange(start, end)
 	}
+	return l.T("ReportTitle"+label, "Period", period)
 }
 
-func periodLabel(start, end time.Time) string {
+func periodLabel(start, end time.Time, l *locale.Locale) string {
 	// normalize to date-only
 	sd := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
 	ed := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location())
@@ -87,10 +69,8 @@ func periodLabel(start, end time.Time) string {
 		return "Monthly"
 	}
 
-	// Weekly (same ISO week & year)
-	y1, w1 := sd.ISOWeek()
-	y2, w2 := ed.ISOWeek()
-	if y1 == y2 && w1 == w2 {
+	// Weekly (same week, starting on the locale's first weekday)
+	if l.WeekStart(sd).Equal(l.WeekStart(ed)) {
 		return "Weekly"
 	}
 
@@ -0,0 +1,29 @@
+package report
+
+import (
+	"strings"
+	"time"
+
+	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/store"
+)
+
+/*
+SearchNotes returns the note blocks in [startDate, endDate] whose note or task
+contains query (case-insensitive). An empty query returns every note.
+*/
+func SearchNotes(inputDir string, layout *store.Layout, startDate, endDate time.Time, query string) (matches []NoteBlock, e *xerr.Error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	for day, e := range store.NewReader(inputDir, layout, store.Lenient).Days(startDate, endDate) {
+		if e != nil {
+			return matches, e
+		}
+		for _, block := range summarizeDay(day, 0).Notes {
+			if strings.Contains(strings.ToLower(block.Note), query) || strings.Contains(strings.ToLower(block.Task), query) {
+				matches = append(matches, block)
+			}
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,229 @@
+/*
+Package session reports when the desktop session stops or resumes being used:
+screen lock, user switch and suspend.
+
+It listens to logind (org.freedesktop.login1) on the system bus and to the
+screensaver's ActiveChanged signal on the session bus. Watch takes the
+connections as arguments, so it can run against a private dbus-daemon
+instead of the real buses (see README.md).
+*/
+package session
+
+import (
+	"errors"
+	"os"
+	"slices"
+
+	"github.com/godbus/dbus/v5"
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+)
+
+// why the session stopped (or resumed) being used
+const (
+	ReasonLock       = "lock"        // screen locked or screensaver on
+	ReasonSleep      = "sleep"       // suspend or hibernate
+	ReasonSwitchUser = "switch-user" // another session became the active one
+)
+
+// Event is one change of the session state. Locked is false when the session is back.
+type Event struct {
+	Locked bool
+	Reason string // one of the Reason constants
+}
+
+const (
+	login1Name      = "org.freedesktop.login1"
+	login1Path      = "/org/freedesktop/login1"
+	login1Manager   = "org.freedesktop.login1.Manager"
+	login1Session   = "org.freedesktop.login1.Session"
+	propertiesIface = "org.freedesktop.DBus.Properties"
+)
+
+// desktops announce their screensaver under different names, the signal is the same
+var screenSaverInterfaces = []string{
+	"org.freedesktop.ScreenSaver",
+	"org.gnome.ScreenSaver",
+	"org.cinnamon.ScreenSaver",
+	"org.mate.ScreenSaver",
+}
+
+/*
+Connect opens the system and the session bus. A bus that can't be reached is nil;
+it's only an error when neither can.
+
+The buses come from DBUS_SYSTEM_BUS_ADDRESS and DBUS_SESSION_BUS_ADDRESS when set.
+*/
+func Connect() (system, sessionBus *dbus.Conn, e *xerr.Error) {
+	system, systemErr := dbus.ConnectSystemBus()
+	if systemErr != nil {
+		tl.Log(tl.Warning, palette.Yellow, "%s: %s", "No system bus, lock and sleep from logind won't be seen", systemErr)
+		system = nil
+	}
+	sessionBus, sessionErr := dbus.ConnectSessionBus()
+	if sessionErr != nil {
+		tl.Log(tl.Warning, palette.Yellow, "%s: %s", "No session bus, the screensaver won't be seen", sessionErr)
+		sessionBus = nil
+	}
+	if system == nil && sessionBus == nil {
+		return nil, nil, xerr.NewErrorECOL(errors.Join(systemErr, sessionErr), "Unable to connect to any D-Bus bus", "pid", os.Getpid())
+	}
+	return system, sessionBus, nil
+}
+
+/*
+Watch subscribes to the session signals on the given connections (either may be nil)
+and sends an Event for each. The channel is closed when both connections are closed.
+
+Duplicates are not filtered: locking usually shows up from logind and the screensaver both.
+*/
+func Watch(system, sessionBus *dbus.Conn) (events <-chan Event, e *xerr.Error) {
+	out := make(chan Event, 8)
+	var sources []chan *dbus.Signal
+
+	if system != nil {
+		sessionPath := ownSessionPath(system)
+		e = subscribeLogind(system, sessionPath)
+		if e != nil {
+			return nil, e
+		}
+		signals := make(chan *dbus.Signal, 16)
+		system.Signal(signals)
+		sources = append(sources, signals)
+	}
+	if sessionBus != nil {
+		e = subscribeScreenSaver(sessionBus)
+		if e != nil {
+			return nil, e
+		}
+		signals := make(chan *dbus.Signal, 16)
+		sessionBus.Signal(signals)
+		sources = append(sources, signals)
+	}
+	if len(sources) == 0 {
+		return nil, xerr.NewErrorECOL(errors.New("no connections"), "Nothing to watch session events on", "pid", os.Getpid())
+	}
+
+	remaining := make(chan struct{}, len(sources))
+	for _, signals := range sources {
+		go func() {
+			for signal := range signals {
+				if event, ok := toEvent(signal); ok {
+					out <- event
+				}
+			}
+			remaining <- struct{}{}
+		}()
+	}
+	go func() {
+		for range sources {
+			<-remaining
+		}
+		close(out)
+	}()
+
+	tl.Log(tl.Info, palette.Green, "%s on %v bus(es)", "Watching session lock and sleep", len(sources))
+	return out, nil
+}
+
+/*
+ownSessionPath finds this process's logind session, so other users' locks are ignored.
+Returns "" (listen to every session) when logind can't tell, e.g. on a private bus.
+*/
+func ownSessionPath(system *dbus.Conn) dbus.ObjectPath {
+	manager := system.Object(login1Name, login1Path)
+	var path dbus.ObjectPath
+	err := manager.Call(login1Manager+".GetSessionByPID", 0, uint32(os.Getpid())).Store(&path)
+	if err == nil {
+		return path
+	}
+	if id := os.Getenv("XDG_SESSION_ID"); id != "" {
+		err = manager.Call(login1Manager+".GetSession", 0, id).Store(&path)
+		if err == nil {
+			return path
+		}
+	}
+	tl.Log(tl.Notice, palette.Yellow, "%s: %s", "Own logind session not found, listening to all sessions", err)
+	return ""
+}
+
+func subscribeLogind(system *dbus.Conn, sessionPath dbus.ObjectPath) (e *xerr.Error) {
+	sessionMatch := []dbus.MatchOption{dbus.WithMatchInterface(login1Session)}
+	if sessionPath != "" {
+		sessionMatch = append(sessionMatch, dbus.WithMatchObjectPath(sessionPath))
+	}
+	matches := [][]dbus.MatchOption{
+		slices.Concat(sessionMatch, []dbus.MatchOption{dbus.WithMatchMember("Lock")}),
+		slices.Concat(sessionMatch, []dbus.MatchOption{dbus.WithMatchMember("Unlock")}),
+		{dbus.WithMatchInterface(login1Manager), dbus.WithMatchMember("PrepareForSleep")},
+	}
+	if sessionPath != "" {
+		// the session stops being the active one when another user takes over the seat
+		matches = append(matches, []dbus.MatchOption{
+			dbus.WithMatchObjectPath(sessionPath),
+			dbus.WithMatchInterface(propertiesIface),
+			dbus.WithMatchMember("PropertiesChanged"),
+			dbus.WithMatchArg(0, login1Session),
+		})
+	}
+	for _, match := range matches {
+		err := system.AddMatchSignal(match...)
+		if err != nil {
+			return xerr.NewErrorECOL(err, "Unable to subscribe to logind signals", "session path", string(sessionPath))
+		}
+	}
+	return nil
+}
+
+func subscribeScreenSaver(sessionBus *dbus.Conn) (e *xerr.Error) {
+	for _, iface := range screenSaverInterfaces {
+		err := sessionBus.AddMatchSignal(dbus.WithMatchInterface(iface), dbus.WithMatchMember("ActiveChanged"))
+		if err != nil {
+			return xerr.NewErrorECOL(err, "Unable to subscribe to screensaver signals", "interface", iface)
+		}
+	}
+	return nil
+}
+
+// toEvent maps a signal to an Event, false for signals that aren't session events
+func toEvent(signal *dbus.Signal) (event Event, ok bool) {
+	switch signal.Name {
+	case login1Session + ".Lock":
+		return Event{Locked: true, Reason: ReasonLock}, true
+	case login1Session + ".Unlock":
+		return Event{Locked: false, Reason: ReasonLock}, true
+	case login1Manager + ".PrepareForSleep":
+		sleeping, ok := firstBool(signal.Body)
+		return Event{Locked: sleeping, Reason: ReasonSleep}, ok
+	case propertiesIface + ".PropertiesChanged":
+		if len(signal.Body) < 2 {
+			return event, false
+		}
+		changed, isMap := signal.Body[1].(map[string]dbus.Variant)
+		if !isMap {
+			return event, false
+		}
+		active, found := changed["Active"]
+		if !found {
+			return event, false
+		}
+		isActive, isBool := active.Value().(bool)
+		return Event{Locked: !isActive, Reason: ReasonSwitchUser}, isBool
+	}
+	for _, iface := range screenSaverInterfaces {
+		if signal.Name == iface+".ActiveChanged" {
+			screenSaverOn, ok := firstBool(signal.Body)
+			return Event{Locked: screenSaverOn, Reason: ReasonLock}, ok
+		}
+	}
+	return event, false
+}
+
+func firstBool(body []any) (value bool, ok bool) {
+	if len(body) == 0 {
+		return false, false
+	}
+	value, ok = body[0].(bool)
+	return value, ok
+}
@@ -0,0 +1,155 @@
+package session
+
+import (
+	"bufio"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestToEvent(t *testing.T) {
+	signal := func(name string, body ...any) *dbus.Signal { return &dbus.Signal{Name: name, Body: body} }
+	activeChanged := func(active any) map[string]dbus.Variant {
+		return map[string]dbus.Variant{"Active": dbus.MakeVariant(active)}
+	}
+	tests := []struct {
+		name   string
+		signal *dbus.Signal
+		event  Event
+		ok     bool
+	}{
+		{"logind lock", signal(login1Session + ".Lock"), Event{Locked: true, Reason: ReasonLock}, true},
+		{"logind unlock", signal(login1Session + ".Unlock"), Event{Locked: false, Reason: ReasonLock}, true},
+		{"going to sleep", signal(login1Manager+".PrepareForSleep", true), Event{Locked: true, Reason: ReasonSleep}, true},
+		{"waking up", signal(login1Manager+".PrepareForSleep", false), Event{Locked: false, Reason: ReasonSleep}, true},
+		{"sleep without a body", signal(login1Manager + ".PrepareForSleep"), Event{Reason: ReasonSleep}, false},
+		{"sleep with a string", signal(login1Manager+".PrepareForSleep", "yes"), Event{Reason: ReasonSleep}, false},
+		{"session inactive", signal(propertiesIface+".PropertiesChanged", login1Session, activeChanged(false), []string{}), Event{Locked: true, Reason: ReasonSwitchUser}, true},
+		{"session active", signal(propertiesIface+".PropertiesChanged", login1Session, activeChanged(true), []string{}), Event{Locked: false, Reason: ReasonSwitchUser}, true},
+		{"other property", signal(propertiesIface+".PropertiesChanged", login1Session, map[string]dbus.Variant{"IdleHint": dbus.MakeVariant(true)}, []string{}), Event{}, false},
+		{"active not a bool", signal(propertiesIface+".PropertiesChanged", login1Session, activeChanged("no"), []string{}), Event{Locked: true, Reason: ReasonSwitchUser}, false},
+		{"properties without changes", signal(propertiesIface+".PropertiesChanged", login1Session), Event{}, false},
+		{"freedesktop screensaver on", signal("org.freedesktop.ScreenSaver.ActiveChanged", true), Event{Locked: true, Reason: ReasonLock}, true},
+		{"gnome screensaver off", signal("org.gnome.ScreenSaver.ActiveChanged", false), Event{Locked: false, Reason: ReasonLock}, true},
+		{"unknown screensaver", signal("org.example.ScreenSaver.ActiveChanged", true), Event{}, false},
+		{"unrelated", signal("org.freedesktop.DBus.NameAcquired", ":1.5"), Event{}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			event, ok := toEvent(test.signal)
+			if ok != test.ok || (ok && event != test.event) {
+				t.Errorf("toEvent = %+v, %v; want %+v, %v", event, ok, test.event, test.ok)
+			}
+		})
+	}
+}
+
+// privateBus starts a throwaway dbus-daemon and returns its address, skipping the test without one
+func privateBus(t *testing.T) (address string) {
+	t.Helper()
+	path, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon is not installed")
+	}
+	daemon := exec.Command(path, "--session", "--print-address", "--nofork")
+	stdout, err := daemon.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = daemon.Start()
+	if err != nil {
+		t.Skipf("dbus-daemon didn't start: %s", err)
+	}
+	t.Cleanup(func() {
+		daemon.Process.Kill()
+		daemon.Wait()
+	})
+	address, err = bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Skipf("dbus-daemon printed no address: %s", err)
+	}
+	return strings.TrimSpace(address)
+}
+
+func connect(t *testing.T, address string) *dbus.Conn {
+	t.Helper()
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatalf("connect to '%s': %s", address, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func nextEvent(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event within 5s")
+		return Event{}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	t.Setenv("XDG_SESSION_ID", "") // no logind on the private bus, listen to every session
+	address := privateBus(t)
+	// the same daemon stands in for the system and the session bus
+	system, sessionBus, emitter := connect(t, address), connect(t, address), connect(t, address)
+
+	events, e := Watch(system, sessionBus)
+	if e != nil {
+		t.Fatalf("Watch: %s", e.Msg)
+	}
+
+	emits := []struct {
+		path   dbus.ObjectPath
+		name   string
+		values []any
+		want   Event
+	}{
+		{"/org/freedesktop/login1/session/test", login1Session + ".Lock", nil, Event{Locked: true, Reason: ReasonLock}},
+		{"/org/freedesktop/login1/session/test", login1Session + ".Unlock", nil, Event{Locked: false, Reason: ReasonLock}},
+		{login1Path, login1Manager + ".PrepareForSleep", []any{true}, Event{Locked: true, Reason: ReasonSleep}},
+		{login1Path, login1Manager + ".PrepareForSleep", []any{false}, Event{Locked: false, Reason: ReasonSleep}},
+		{"/org/freedesktop/ScreenSaver", "org.freedesktop.ScreenSaver.ActiveChanged", []any{true}, Event{Locked: true, Reason: ReasonLock}},
+		{"/org/gnome/ScreenSaver", "org.gnome.ScreenSaver.ActiveChanged", []any{false}, Event{Locked: false, Reason: ReasonLock}},
+	}
+	for _, emit := range emits {
+		// a signal nobody subscribed to goes first, it must not come out as an event
+		err := emitter.Emit(emit.path, "org.example.Unrelated.Changed", true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = emitter.Emit(emit.path, emit.name, emit.values...)
+		if err != nil {
+			t.Fatalf("emit %s: %s", emit.name, err)
+		}
+		if event := nextEvent(t, events); event != emit.want {
+			t.Errorf("after %s: %+v, want %+v", emit.name, event, emit.want)
+		}
+	}
+
+	// closing both connections ends the stream
+	system.Close()
+	sessionBus.Close()
+	select {
+	case event, open := <-events:
+		if open {
+			t.Errorf("unexpected event %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("events not closed after both connections were")
+	}
+}
+
+func TestWatchWithoutConnections(t *testing.T) {
+	_, e := Watch(nil, nil)
+	if e == nil {
+		t.Error("Watch(nil, nil) should fail")
+	}
+}
@@ -0,0 +1,41 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+/*
+Duration is a time.Duration that is saved as a human readable string ("1s", "12h0m0s").
+
+Reading also accepts a JSON number (nanoseconds), so hand-written files can use either.
+*/
+type Duration struct{ time.Duration }
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Duration.String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var raw any
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		return err
+	}
+
+	switch value := raw.(type) {
+	case float64:
+		d.Duration = time.Duration(value)
+		return nil
+	case string:
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		d.Duration = parsed
+		return nil
+	default:
+		return fmt.Errorf("invalid duration: %s", string(b))
+	}
+}
@@ -0,0 +1,76 @@
+package settings
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// what happens to the task when a timebox runs out
+const (
+	TimeboxActionStop     = "stop"
+	TimeboxActionSwitch   = "switch"   // to FocusSettings.SwitchTo
+	TimeboxActionContinue = "continue" // only notify
+)
+
+var TimeboxActions = []string{TimeboxActionStop, TimeboxActionSwitch, TimeboxActionContinue}
+
+/*
+FocusSettings configure focus sessions on a task: a pomodoro cycle of work
+intervals and breaks, or a one-off timebox. Breaks are recorded as pauses.
+*/
+type FocusSettings struct {
+	Work           Duration   `json:"work"`             // one pomodoro
+	ShortBreak     Duration   `json:"short_break"`      // after each pomodoro...
+	LongBreak      Duration   `json:"long_break"`       // ...except every LongBreakEvery-th one
+	LongBreakEvery int        `json:"long_break_every"` // pomodoros per long break
+	AutoResume     bool       `json:"auto_resume"`      // start the next pomodoro when a break ends, otherwise wait for Resume
+	Timeboxes      []Duration `json:"timeboxes"`        // lengths offered in the menus
+	TimeboxAction  string     `json:"timebox_action"`   // one of TimeboxActions
+	SwitchTo       string     `json:"switch_to"`        // task for TimeboxActionSwitch, "" is unassigned
+}
+
+func defaultFocus() FocusSettings {
+	return FocusSettings{
+		Work:           Duration{25 * time.Minute},
+		ShortBreak:     Duration{5 * time.Minute},
+		LongBreak:      Duration{15 * time.Minute},
+		LongBreakEvery: 4,
+		AutoResume:     true,
+		Timeboxes:      []Duration{{15 * time.Minute}, {30 * time.Minute}, {45 * time.Minute}, {60 * time.Minute}, {90 * time.Minute}},
+		TimeboxAction:  TimeboxActionStop,
+	}
+}
+
+// BreakAfter is the break that follows the completed-th pomodoro (counting from 1).
+func (f FocusSettings) BreakAfter(completed int) time.Duration {
+	if f.LongBreakEvery > 0 && completed > 0 && completed%f.LongBreakEvery == 0 {
+		return f.LongBreak.Duration
+	}
+	return f.ShortBreak.Duration
+}
+
+// problems returns one line per invalid focus value, see Settings.Problems.
+func (f FocusSettings) problems() (problems []string) {
+	if !within(f.Work.Duration, time.Minute, 4*time.Hour) {
+		problems = append(problems, fmt.Sprintf("focus.work must be between 1m and 4h, got %s", f.Work))
+	}
+	if !within(f.ShortBreak.Duration, time.Minute, 2*time.Hour) {
+		problems = append(problems, fmt.Sprintf("focus.short_break must be between 1m and 2h, got %s", f.ShortBreak))
+	}
+	if !within(f.LongBreak.Duration, time.Minute, 2*time.Hour) {
+		problems = append(problems, fmt.Sprintf("focus.long_break must be between 1m and 2h, got %s", f.LongBreak))
+	}
+	if f.LongBreakEvery < 0 || f.LongBreakEvery > 20 {
+		problems = append(problems, fmt.Sprintf("focus.long_break_every must be between 0 (never) and 20, got %v", f.LongBreakEvery))
+	}
+	for _, timebox := range f.Timeboxes {
+		if !within(timebox.Duration, time.Minute, 12*time.Hour) {
+			problems = append(problems, fmt.Sprintf("focus.timeboxes: %s is not between 1m and 12h", timebox))
+		}
+	}
+	if !slices.Contains(TimeboxActions, f.TimeboxAction) {
+		problems = append(problems, fmt.Sprintf("focus.timebox_action '%s' is not one of %v", f.TimeboxAction, TimeboxActions))
+	}
+	return problems
+}
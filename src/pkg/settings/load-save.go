@@ -0,0 +1,102 @@
+package settings
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+)
+
+/*
+Load reads the settings file at filePath on top of Default().
+
+A missing file is not an error: defaults are returned so a fresh checkout
+works without copying example.settings.json first.
+Keys missing from the file keep their default values.
+*/
+func Load(filePath string) (s Settings, e *xerr.Error) {
+	tl.Log(tl.Notice, palette.Blue, "%s settings '%s'", "Loading", filePath)
+	s = Default()
+
+	byteValue, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			tl.Log(tl.Notice, palette.Purple, "No such file: '%s', %s", filePath, "using default settings")
+			return s, nil
+		}
+		return s, xerr.NewErrorECOL(err, "Unable to read settings file", "file path", filePath)
+	}
+
+	err = json.Unmarshal(byteValue, &s)
+	if err != nil {
+		return s, xerr.NewErrorECOL(err, "Unable to json.Unmarshal settings file", "file path", filePath)
+	}
+
+	tl.Log(tl.Notice1, palette.Green, "%s settings '%s'", "Loaded", filePath)
+	return s, nil
+}
+
+/*
+Save validates s and writes it to filePath.
+
+The file is written to a temporary sibling first and renamed over the
+original, so a crash never leaves a half-written settings file behind.
+*/
+func Save(filePath string, s Settings) (e *xerr.Error) {
+	tl.Log(tl.Notice, palette.Blue, "%s settings to '%s'", "Saving", filePath)
+
+	e = s.Validate()
+	if e != nil {
+		return e
+	}
+
+	byteValue, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return xerr.NewError(err, "Unable to marshal settings", s)
+	}
+
+	err = os.MkdirAll(filepath.Dir(filePath), 0o755)
+	if err != nil {
+		return xerr.NewErrorECOL(err, "Unable to create settings directory", "dir", filepath.Dir(filePath))
+	}
+
+	tmpPath := filePath + ".tmp"
+	err = os.WriteFile(tmpPath, append(byteValue, '\n'), 0o644)
+	if err != nil {
+		return xerr.NewErrorECOL(err, "Unable to write settings file", "file path", tmpPath)
+	}
+	err = os.Rename(tmpPath, filePath)
+	if err != nil {
+		return xerr.NewErrorECOL(err, "Unable to replace settings file", "file path", filePath)
+	}
+
+	tl.Log(tl.Notice1, palette.Green, "%s settings to '%s'", "Saved", filePath)
+	return nil
+}
+
+// updateMutex keeps two updates from reading the same file and losing one's change
+var updateMutex sync.Mutex
+
+/*
+Update loads the settings file at filePath, lets change edit it and saves it back.
+
+For callers that own a few keys (the window state, the task table, the active
+profile): every other key keeps what the file has now, edits made by hand or from
+the settings window since the tracker started included, and command line overrides
+never get written.
+*/
+func Update(filePath string, change func(*Settings)) (e *xerr.Error) {
+	updateMutex.Lock()
+	defer updateMutex.Unlock()
+
+	s, e := Load(filePath)
+	if e != nil {
+		return e
+	}
+	change(&s)
+	return Save(filePath, s)
+}
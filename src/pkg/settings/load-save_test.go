@@ -0,0 +1,67 @@
+package settings
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUpdate(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "settings.json")
+
+	// a missing file starts from the defaults
+	e := Update(filePath, func(s *Settings) { s.WorkDir = "./elsewhere" })
+	if e != nil {
+		t.Fatalf("Update: %s", e.Msg)
+	}
+	saved, e := Load(filePath)
+	if e != nil {
+		t.Fatalf("Load: %s", e.Msg)
+	}
+	if saved.WorkDir != "./elsewhere" || saved.DailyTarget != Default().DailyTarget {
+		t.Errorf("work dir %q, daily target %s after the first update", saved.WorkDir, saved.DailyTarget)
+	}
+
+	// keys the change doesn't touch keep what the file has, even when written since
+	e = Update(filePath, func(s *Settings) { s.ActivityWindow = Duration{15 * time.Minute} })
+	if e != nil {
+		t.Fatalf("Update: %s", e.Msg)
+	}
+	saved, _ = Load(filePath)
+	if saved.WorkDir != "./elsewhere" || saved.ActivityWindow.Duration != 15*time.Minute {
+		t.Errorf("work dir %q, activity window %s after the second update", saved.WorkDir, saved.ActivityWindow)
+	}
+
+	// an invalid result is not written
+	e = Update(filePath, func(s *Settings) { s.ThemeMode = "neon" })
+	if e == nil {
+		t.Error("Update saved an invalid theme mode")
+	}
+	saved, _ = Load(filePath)
+	if saved.ThemeMode == "neon" {
+		t.Error("the invalid theme mode reached the file")
+	}
+}
+
+func TestUpdateConcurrently(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "settings.json")
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		Update(filePath, func(s *Settings) { s.MiniMode = true })
+	}()
+	go func() {
+		defer wg.Done()
+		Update(filePath, func(s *Settings) { s.TaskTable.SortBy = TaskColumnHours })
+	}()
+	wg.Wait()
+	saved, e := Load(filePath)
+	if e != nil {
+		t.Fatalf("Load: %s", e.Msg)
+	}
+	if !saved.MiniMode || saved.TaskTable.SortBy != TaskColumnHours {
+		t.Errorf("mini mode %v, sort by %q: an update was lost", saved.MiniMode, saved.TaskTable.SortBy)
+	}
+}
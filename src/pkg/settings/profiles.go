@@ -0,0 +1,103 @@
+package settings
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/store"
+)
+
+// DefaultProfile names the top-level values, it can't be used as a key of Settings.Profiles.
+const DefaultProfile = "default"
+
+/*
+Profile keeps a separate set of data for one kind of work (employer, freelance, ...).
+Empty values keep the top-level ones, so a profile only lists what differs.
+*/
+type Profile struct {
+	WorkDir       string   `json:"work_dir,omitempty"`
+	DayFileLayout string   `json:"day_file_layout,omitempty"`
+	TasksPath     string   `json:"tasks_path,omitempty"`
+	DailyTarget   Duration `json:"daily_target"` // 0 keeps the top-level target
+	OutputPath    string   `json:"report_output_path,omitempty"`
+	Recipients    []string `json:"report_recipients,omitempty"`
+}
+
+// ProfileNames lists DefaultProfile and then every profile, sorted.
+func (s Settings) ProfileNames() (names []string) {
+	return append([]string{DefaultProfile}, slices.Sorted(maps.Keys(s.Profiles))...)
+}
+
+/*
+WithProfile returns s with the profile's values in place of the top-level ones
+and ActiveProfile set to name. "" and DefaultProfile return the top-level values.
+*/
+func (s Settings) WithProfile(name string) (profiled Settings, e *xerr.Error) {
+	if name == "" || name == DefaultProfile {
+		s.ActiveProfile = ""
+		return s, nil
+	}
+	profile, found := s.Profiles[name]
+	if !found {
+		return s, xerr.NewErrorECOL(fmt.Errorf("unknown profile '%s'", name), "No such profile in the settings file", "known profiles", strings.Join(s.ProfileNames(), ", "))
+	}
+
+	s.ActiveProfile = name
+	if profile.WorkDir != "" {
+		s.WorkDir = profile.WorkDir
+	}
+	if profile.DayFileLayout != "" {
+		s.DayFileLayout = profile.DayFileLayout
+	}
+	if profile.TasksPath != "" {
+		s.TasksPath = profile.TasksPath
+	}
+	if profile.DailyTarget.Duration != 0 {
+		s.DailyTarget = profile.DailyTarget
+	}
+	if profile.OutputPath != "" {
+		s.Report.OutputPath = profile.OutputPath
+	}
+	if len(profile.Recipients) > 0 {
+		s.Report.Recipients = slices.Clone(profile.Recipients)
+	}
+	return s, nil
+}
+
+// ProfileLabel is name as shown in menus and logs, "default" for the top-level values.
+func ProfileLabel(name string) string {
+	if name == "" {
+		return DefaultProfile
+	}
+	return name
+}
+
+// profileProblems returns one line per invalid profile value, see Settings.Problems.
+func profileProblems(profiles map[string]Profile, activeProfile string) (problems []string) {
+	for _, name := range slices.Sorted(maps.Keys(profiles)) {
+		profile := profiles[name]
+		if strings.TrimSpace(name) == "" || name == DefaultProfile {
+			problems = append(problems, fmt.Sprintf("profiles: '%s' is reserved, pick another name", name))
+		}
+		if _, err := store.ParseLayout(profile.DayFileLayout); profile.DayFileLayout != "" && err != nil {
+			problems = append(problems, fmt.Sprintf("profiles.%s.day_file_layout '%s' is not usable: %s", name, profile.DayFileLayout, err))
+		}
+		if profile.DailyTarget.Duration != 0 && !within(profile.DailyTarget.Duration, time.Minute, 24*time.Hour) {
+			problems = append(problems, fmt.Sprintf("profiles.%s.daily_target must be 0 (top-level) or between 1m and 24h, got %s", name, profile.DailyTarget))
+		}
+		for _, recipient := range profile.Recipients {
+			if !strings.Contains(recipient, "@") {
+				problems = append(problems, fmt.Sprintf("profiles.%s.report_recipients: '%s' is not an email address", name, recipient))
+			}
+		}
+	}
+	if _, found := profiles[activeProfile]; activeProfile != "" && activeProfile != DefaultProfile && !found {
+		problems = append(problems, fmt.Sprintf("active_profile '%s' is not one of %v", activeProfile, slices.Sorted(maps.Keys(profiles))))
+	}
+	return problems
+}
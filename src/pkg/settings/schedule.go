@@ -0,0 +1,142 @@
+package settings
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Weekdays are the keys of ScheduleSettings.Week, Monday first.
+var Weekdays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+const holidayLayout = time.DateOnly // "2006-01-02"
+
+/*
+ScheduleSettings describe working hours: windows per weekday plus holidays
+without any. The tracker uses them to stop forgotten timers, to warn about
+tracking out of hours and, optionally, to start tracking on arrival.
+*/
+type ScheduleSettings struct {
+	Enabled      bool                     `json:"enabled"`
+	Week         map[string][]HoursWindow `json:"week"`           // weekday (see Weekdays) to its windows, a missing day has none
+	Holidays     []string                 `json:"holidays"`       // "YYYY-MM-DD", no working hours on these days
+	AutoStop     bool                     `json:"auto_stop"`      // stop a run left going outside working hours...
+	AutoStopIdle Duration                 `json:"auto_stop_idle"` // ...once there was no input for this long
+	WarnOutside  bool                     `json:"warn_outside"`   // notify when starting outside working hours
+	AutoStart    bool                     `json:"auto_start"`     // start the last task on the first activity in a window
+}
+
+// HoursWindow is one span of working hours within a day, "HH:MM" to "HH:MM" (not past midnight).
+type HoursWindow struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// TimeRange is an HoursWindow on a given date.
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+func defaultWeek() map[string][]HoursWindow {
+	week := make(map[string][]HoursWindow)
+	for _, day := range Weekdays[:5] {
+		week[day] = []HoursWindow{{From: "09:00", To: "18:00"}}
+	}
+	return week
+}
+
+// WindowsOn returns the working windows on day's date, none on holidays.
+func (s ScheduleSettings) WindowsOn(day time.Time) (ranges []TimeRange) {
+	if slices.Contains(s.Holidays, day.Format(holidayLayout)) {
+		return nil
+	}
+	year, month, date := day.Date()
+	midnight := time.Date(year, month, date, 0, 0, 0, 0, day.Location())
+	for _, window := range s.Week[strings.ToLower(day.Weekday().String())] {
+		from, fromErr := ParseClock(window.From)
+		to, toErr := ParseClock(window.To)
+		if fromErr != nil || toErr != nil || to <= from {
+			continue // Problems reports it
+		}
+		ranges = append(ranges, TimeRange{Start: midnight.Add(from), End: midnight.Add(to)})
+	}
+	return ranges
+}
+
+// WindowAt returns the window now falls in.
+func (s ScheduleSettings) WindowAt(now time.Time) (window TimeRange, ok bool) {
+	for _, window := range s.WindowsOn(now) {
+		if !now.Before(window.Start) && now.Before(window.End) {
+			return window, true
+		}
+	}
+	return window, false
+}
+
+// LastEndBetween returns the latest window end in (from, to].
+func (s ScheduleSettings) LastEndBetween(from, to time.Time) (end time.Time, ok bool) {
+	year, month, date := from.Date()
+	for day := time.Date(year, month, date, 0, 0, 0, 0, from.Location()); !day.After(to); day = day.AddDate(0, 0, 1) {
+		for _, window := range s.WindowsOn(day) {
+			if window.End.After(from) && !window.End.After(to) && window.End.After(end) {
+				end, ok = window.End, true
+			}
+		}
+	}
+	return end, ok
+}
+
+// FormatWindows writes windows as "09:00-12:00, 13:00-18:00".
+func FormatWindows(windows []HoursWindow) string {
+	parts := make([]string, len(windows))
+	for i, window := range windows {
+		parts[i] = window.From + "-" + window.To
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseWindows reads what FormatWindows writes. An empty text means no windows.
+func ParseWindows(text string) (windows []HoursWindow, err error) {
+	for part := range strings.SplitSeq(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		from, to, found := strings.Cut(part, "-")
+		if !found {
+			return nil, fmt.Errorf("'%s' is not HH:MM-HH:MM", part)
+		}
+		windows = append(windows, HoursWindow{From: strings.TrimSpace(from), To: strings.TrimSpace(to)})
+	}
+	return windows, nil
+}
+
+// problems returns one line per invalid schedule value, see Settings.Problems.
+func (s ScheduleSettings) problems() (problems []string) {
+	for day, windows := range s.Week {
+		if !slices.Contains(Weekdays, day) {
+			problems = append(problems, fmt.Sprintf("schedule.week: '%s' is not one of %v", day, Weekdays))
+			continue
+		}
+		for _, window := range windows {
+			from, fromErr := ParseClock(window.From)
+			to, toErr := ParseClock(window.To)
+			if fromErr != nil || toErr != nil || to <= from {
+				problems = append(problems, fmt.Sprintf("schedule.week.%s: '%s-%s' must be HH:MM-HH:MM, ending after it starts", day, window.From, window.To))
+			}
+		}
+	}
+	for _, holiday := range s.Holidays {
+		_, err := time.Parse(holidayLayout, holiday)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("schedule.holidays: '%s' is not a YYYY-MM-DD date", holiday))
+		}
+	}
+	if s.AutoStop && !within(s.AutoStopIdle.Duration, time.Minute, 12*time.Hour) {
+		problems = append(problems, fmt.Sprintf("schedule.auto_stop_idle must be between 1m and 12h, got %s", s.AutoStopIdle))
+	}
+	slices.Sort(problems) // map order
+	return problems
+}
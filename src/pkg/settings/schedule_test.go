@@ -0,0 +1,101 @@
+package settings
+
+import (
+	"testing"
+	"time"
+)
+
+// testSchedule has a split Monday and Friday, a Saturday evening and 2026-01-26 (a Monday) off
+var testSchedule = ScheduleSettings{
+	Enabled: true,
+	Week: map[string][]HoursWindow{
+		"monday":   {{From: "09:00", To: "12:00"}, {From: "13:00", To: "18:00"}},
+		"friday":   {{From: "09:00", To: "12:00"}, {From: "13:00", To: "17:00"}},
+		"saturday": {{From: "20:00", To: "23:30"}},
+		"sunday":   {{From: "18:00", To: "09:00"}}, // ends before it starts, ignored
+	},
+	Holidays: []string{"2026-01-26"},
+}
+
+// on is a time in January 2026; the 23rd is a Friday
+func on(day, hour, minute int) time.Time {
+	return time.Date(2026, 1, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestWindowsOn(t *testing.T) {
+	tests := []struct {
+		name string
+		day  time.Time
+		want []TimeRange
+	}{
+		{"multiple windows", on(23, 15, 0), []TimeRange{{on(23, 9, 0), on(23, 12, 0)}, {on(23, 13, 0), on(23, 17, 0)}}},
+		{"at midnight", on(24, 0, 0), []TimeRange{{on(24, 20, 0), on(24, 23, 30)}}},
+		{"holiday", on(26, 10, 0), nil},
+		{"a Monday that isn't a holiday", on(19, 10, 0), []TimeRange{{on(19, 9, 0), on(19, 12, 0)}, {on(19, 13, 0), on(19, 18, 0)}}},
+		{"invalid window skipped", on(25, 10, 0), nil},
+		{"no windows", on(22, 10, 0), nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := testSchedule.WindowsOn(test.day)
+			if len(got) != len(test.want) {
+				t.Fatalf("WindowsOn(%s) = %v, want %v", test.day, got, test.want)
+			}
+			for i := range got {
+				if !got[i].Start.Equal(test.want[i].Start) || !got[i].End.Equal(test.want[i].End) {
+					t.Errorf("window %v: %v, want %v", i, got[i], test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWindowAt(t *testing.T) {
+	tests := []struct {
+		name  string
+		now   time.Time
+		start time.Time
+		ok    bool
+	}{
+		{"first window", on(23, 9, 0), on(23, 9, 0), true},
+		{"second window", on(23, 16, 59), on(23, 13, 0), true},
+		{"between windows", on(23, 12, 30), time.Time{}, false},
+		{"at a window's end", on(23, 12, 0), time.Time{}, false},
+		{"before hours", on(23, 8, 59), time.Time{}, false},
+		{"holiday", on(26, 10, 0), time.Time{}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			window, ok := testSchedule.WindowAt(test.now)
+			if ok != test.ok || (ok && !window.Start.Equal(test.start)) {
+				t.Errorf("WindowAt(%s) = %v, %v; want start %s, %v", test.now, window, ok, test.start, test.ok)
+			}
+		})
+	}
+}
+
+func TestLastEndBetween(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to time.Time
+		end      time.Time
+		ok       bool
+	}{
+		{"one end", on(23, 14, 0), on(23, 19, 0), on(23, 17, 0), true},
+		{"the later of two", on(23, 10, 0), on(23, 19, 0), on(23, 17, 0), true},
+		{"end equal to to", on(23, 14, 0), on(23, 17, 0), on(23, 17, 0), true},
+		{"end equal to from is left out", on(23, 17, 0), on(23, 19, 0), time.Time{}, false},
+		{"spanning midnight", on(24, 21, 0), on(25, 1, 0), on(24, 23, 30), true},
+		{"over days", on(23, 10, 0), on(25, 10, 0), on(24, 23, 30), true},
+		{"over a holiday", on(26, 8, 0), on(26, 20, 0), time.Time{}, false},
+		{"no window in between", on(23, 18, 0), on(23, 23, 0), time.Time{}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			end, ok := testSchedule.LastEndBetween(test.from, test.to)
+			if ok != test.ok || !end.Equal(test.end) {
+				t.Errorf("LastEndBetween(%s, %s) = %s, %v; want %s, %v", test.from, test.to, end, ok, test.end, test.ok)
+			}
+		})
+	}
+}
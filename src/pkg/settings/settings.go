@@ -0,0 +1,225 @@
+/*
+Package settings holds the user-editable settings file (./cfg/settings.json by default).
+
+Unlike the config package (logger setup, loaded once per process) these values
+are edited from the tracker's Settings window, so the package knows how to
+validate and save them as well as load them.
+*/
+package settings
+
+import (
+	"time"
+
+	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/email"
+	"work-tracker/src/pkg/locale"
+	"work-tracker/src/pkg/report"
+	"work-tracker/src/pkg/store"
+)
+
+const DefaultPath = "./cfg/settings.json"
+
+const (
+	ThemeModeLight  = "light"
+	ThemeModeDark   = "dark"
+	ThemeModeSystem = "system" // follow the desktop's light/dark preference
+)
+
+var ThemeModes = []string{ThemeModeLight, ThemeModeDark, ThemeModeSystem}
+
+// what happens to a running task when the session locks, is switched away from or goes to sleep
+const (
+	LockActionStop   = "stop"
+	LockActionPause  = "pause" // recorded as an "away" pause
+	LockActionIgnore = "ignore"
+)
+
+var LockActions = []string{LockActionStop, LockActionPause, LockActionIgnore}
+
+type Settings struct {
+	// storage
+	WorkDir       string `json:"work_dir"`        // directory for daily JSONL files
+	DayFileLayout string `json:"day_file_layout"` // path template of the day files under work_dir, see store.Layout
+	TasksPath     string `json:"tasks_path"`      // file with tasks and their descriptions
+
+	// profiles, see WithProfile
+	ActiveProfile string             `json:"active_profile"` // "" uses the values above, saved by the tracker's profile switcher
+	Profiles      map[string]Profile `json:"profiles,omitempty"`
+
+	// tickers
+	UITickInterval       Duration `json:"ui_tick_interval"`
+	ActivityTickInterval Duration `json:"activity_tick_interval"`
+	FlushTickInterval    Duration `json:"flush_tick_interval"`
+
+	// tracking
+	DailyTarget      Duration `json:"daily_target"`        // the tray icon's ring is full at this much tracked time
+	PromptNoteOnStop bool     `json:"prompt_note_on_stop"` // ask what was done when stopping or switching
+
+	// interface
+	ThemeScale   float32 `json:"theme_scale"`  // multiplies every theme size
+	ThemeMode    string  `json:"theme_mode"`   // one of ThemeModes
+	AccentColor  string  `json:"accent_color"` // "#RRGGBB", empty keeps the theme's primary color
+	WindowWidth  float32 `json:"window_width"`
+	WindowHeight float32 `json:"window_height"`
+
+	// window state, saved by the tracker on quit
+	WindowPosition  *Position `json:"window_position,omitempty"` // nil lets the window manager place it
+	MiniMode        bool      `json:"mini_mode"`                 // compact window: task, clock, start/stop, activity
+	MiniWidth       float32   `json:"mini_width"`
+	MiniHeight      float32   `json:"mini_height"`
+	MiniAlwaysOnTop bool      `json:"mini_always_on_top"`
+	StartHidden     bool      `json:"start_hidden"`    // start in the tray without showing the window
+	ActivityWindow  Duration  `json:"activity_window"` // span of the current activity bar, 0 is the last tick; picked by clicking the bar
+
+	Locale        locale.Options       `json:"locale"` // language, clock, duration format and first weekday
+	Schedule      ScheduleSettings     `json:"schedule"`
+	Focus         FocusSettings        `json:"focus"`
+	TaskTable     TaskTableSettings    `json:"task_table"` // saved by the tracker when columns or sorting change
+	Lock          LockSettings         `json:"lock"`
+	Notifications NotificationSettings `json:"notifications"`
+	Report        ReportDefaults       `json:"report"`
+}
+
+// Position is a window's top-left corner in screen pixels.
+type Position struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// LockSettings decide what screen lock, user switch and suspend do to tracking.
+type LockSettings struct {
+	Action      string `json:"action"`       // one of LockActions
+	OfferResume bool   `json:"offer_resume"` // ask to resume the stopped or paused task on unlock
+}
+
+/*
+NotificationSettings configure the tracker's desktop reminders.
+Clock times are "HH:MM" local time; an empty one turns its feature off.
+*/
+type NotificationSettings struct {
+	Enabled            bool     `json:"enabled"`
+	BreakReminder      Reminder `json:"break_reminder"`       // tracking without a stop or pause
+	IdleWhileRunning   Reminder `json:"idle_while_running"`   // tracking but no input
+	ActiveWhileStopped Reminder `json:"active_while_stopped"` // input but not tracking
+	WorkdayEnd         string   `json:"workday_end"`          // remind that the timer still runs after this time
+	WorkdayEndRepeat   Duration `json:"workday_end_repeat"`   // 0 reminds once
+	QuietFrom          string   `json:"quiet_from"`           // no notifications from this time...
+	QuietTo            string   `json:"quiet_to"`             // ...until this one, may wrap past midnight
+}
+
+// Reminder fires once its condition has held for After, then every Repeat. After 0 turns it off, Repeat 0 reminds once.
+type Reminder struct {
+	After  Duration `json:"after"`
+	Repeat Duration `json:"repeat"`
+}
+
+// ReportDefaults are used by the tracker's reports window and by cmd/report, cmd/send-email when flags are omitted.
+type ReportDefaults struct {
+	Preset     string   `json:"preset"` // one of report.AllPresets
+	OutputPath string   `json:"output_path"`
+	Timezone   string   `json:"timezone"` // IANA name, "Local" for the system zone
+	BarRef     Duration `json:"bar_ref"`  // reference line on the charts
+	Smooth     float64  `json:"smooth"`   // activity smoothing in [0..1]
+	Provider   string   `json:"provider"` // email provider
+	Sender     string   `json:"sender"`
+	Recipients []string `json:"recipients"`
+}
+
+// Layout is the parsed DayFileLayout.
+func (s Settings) Layout() (layout *store.Layout, e *xerr.Error) {
+	layout, err := store.ParseLayout(s.DayFileLayout)
+	if err != nil {
+		return nil, xerr.NewErrorECOL(err, "Unable to parse day_file_layout", "day_file_layout", s.DayFileLayout)
+	}
+	return layout, nil
+}
+
+// Default returns the values the programs used before there was a settings file.
+func Default() Settings {
+	return Settings{
+		WorkDir:              "./out",
+		DayFileLayout:        store.DefaultLayout,
+		TasksPath:            "./cfg/tasks.json",
+		UITickInterval:       Duration{1 * time.Second},
+		ActivityTickInterval: Duration{1 * time.Second},
+		FlushTickInterval:    Duration{10 * time.Second},
+		DailyTarget:          Duration{8 * time.Hour},
+		ThemeScale:           1.30,
+		ThemeMode:            ThemeModeLight,
+		WindowWidth:          1280,
+		WindowHeight:         720,
+		MiniWidth:            360,
+		MiniHeight:           150,
+		MiniAlwaysOnTop:      true,
+		ActivityWindow:       Duration{5 * time.Minute},
+		Locale: locale.Options{
+			DurationFormat: locale.DurationFormatHM,
+		},
+		Schedule: ScheduleSettings{
+			Week:         defaultWeek(),
+			AutoStop:     true,
+			AutoStopIdle: Duration{5 * time.Minute},
+			WarnOutside:  true,
+		},
+		Focus: defaultFocus(),
+		Lock: LockSettings{
+			Action:      LockActionPause,
+			OfferResume: true,
+		},
+		Notifications: NotificationSettings{
+			Enabled:            true,
+			BreakReminder:      Reminder{After: Duration{90 * time.Minute}, Repeat: Duration{30 * time.Minute}},
+			IdleWhileRunning:   Reminder{After: Duration{10 * time.Minute}},
+			ActiveWhileStopped: Reminder{After: Duration{10 * time.Minute}},
+			WorkdayEnd:         "18:00",
+			WorkdayEndRepeat:   Duration{30 * time.Minute},
+		},
+		Report: ReportDefaults{
+			Preset:     string(report.PresetThisWeek),
+			OutputPath: "./out/report.html",
+			Timezone:   "America/Bogota",
+			BarRef:     Duration{12 * time.Hour},
+			Smooth:     0,
+			Provider:   string(email.ProviderMailgun),
+		},
+	}
+}
+
+/*
+InQuietHours reports whether now falls between QuietFrom and QuietTo.
+The range may wrap past midnight ("22:00" to "08:00"). Unset or invalid bounds mean never quiet.
+*/
+func (n NotificationSettings) InQuietHours(now time.Time) bool {
+	from, fromErr := ParseClock(n.QuietFrom)
+	to, toErr := ParseClock(n.QuietTo)
+	if fromErr != nil || toErr != nil || from == to {
+		return false
+	}
+	sinceMidnight := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute
+	if from < to {
+		return sinceMidnight >= from && sinceMidnight < to
+	}
+	return sinceMidnight >= from || sinceMidnight < to
+}
+
+/*
+RestartRequired lists the settings that changed between old and new
+but only take effect after the tracker is restarted.
+Everything else is applied live by the tracker.
+*/
+func RestartRequired(old, new Settings) (names []string) {
+	if old.WorkDir != new.WorkDir {
+		names = append(names, "work_dir")
+	}
+	if old.DayFileLayout != new.DayFileLayout {
+		names = append(names, "day_file_layout")
+	}
+	if old.TasksPath != new.TasksPath {
+		names = append(names, "tasks_path")
+	}
+	if old.Locale != new.Locale {
+		names = append(names, "locale")
+	}
+	return names
+}
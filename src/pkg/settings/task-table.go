@@ -0,0 +1,54 @@
+package settings
+
+import (
+	"fmt"
+	"slices"
+)
+
+// optional stats columns of the tracker's task table
+const (
+	TaskColumnActivity   = "activity"    // today's active share of the task's time
+	TaskColumnSessions   = "sessions"    // separate runs on the task today
+	TaskColumnLastWorked = "last_worked" // time since the task was last tracked
+	TaskColumnLifetime   = "lifetime"    // total tracked in every day file
+)
+
+var TaskColumns = []string{TaskColumnActivity, TaskColumnSessions, TaskColumnLastWorked, TaskColumnLifetime}
+
+// columns that are always shown, they can be sorted by too
+const (
+	TaskColumnName      = "task"
+	TaskColumnCreatedAt = "created_at"
+	TaskColumnHours     = "hours" // tracked today
+)
+
+// TaskSortColumns are the columns the table can be sorted by, "" keeps the tasks file's order.
+var TaskSortColumns = append([]string{"", TaskColumnName, TaskColumnCreatedAt, TaskColumnHours}, TaskColumns...)
+
+/*
+TaskTableSettings are the task table's optional columns and sort order,
+saved by the tracker when they are changed from the table.
+*/
+type TaskTableSettings struct {
+	Columns        []string `json:"columns"`         // shown optional columns, from TaskColumns
+	SortBy         string   `json:"sort_by"`         // one of TaskSortColumns
+	SortDescending bool     `json:"sort_descending"` // largest, latest or Z first
+}
+
+// Shows reports whether the optional column is visible.
+func (t TaskTableSettings) Shows(column string) bool {
+	return slices.Contains(t.Columns, column)
+}
+
+// problems returns one line per invalid task table value, see Settings.Problems.
+func (t TaskTableSettings) problems() (problems []string) {
+	for _, column := range t.Columns {
+		if !slices.Contains(TaskColumns, column) {
+			problems = append(problems, fmt.Sprintf("task_table.columns: '%s' is not one of %v", column, TaskColumns))
+		}
+	}
+	if !slices.Contains(TaskSortColumns, t.SortBy) {
+		problems = append(problems, fmt.Sprintf("task_table.sort_by '%s' is not empty or one of %v", t.SortBy, TaskSortColumns[1:]))
+	}
+	return problems
+}
@@ -0,0 +1,154 @@
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/email"
+	"work-tracker/src/pkg/report"
+	"work-tracker/src/pkg/store"
+)
+
+/*
+Problems returns one human readable line per invalid value.
+An empty slice means the settings can be saved and applied.
+*/
+func (s Settings) Problems() (problems []string) {
+	addIf := func(bad bool, format string, args ...any) {
+		if bad {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	// storage
+	addIf(strings.TrimSpace(s.WorkDir) == "", "work_dir must not be empty")
+	_, layoutErr := store.ParseLayout(s.DayFileLayout)
+	addIf(layoutErr != nil, "day_file_layout '%s' is not usable: %v", s.DayFileLayout, layoutErr)
+	addIf(strings.TrimSpace(s.TasksPath) == "", "tasks_path must not be empty")
+
+	problems = append(problems, profileProblems(s.Profiles, s.ActiveProfile)...)
+
+	// tickers
+	addIf(!within(s.UITickInterval.Duration, 100*time.Millisecond, time.Minute),
+		"ui_tick_interval must be between 100ms and 1m, got %s", s.UITickInterval)
+	addIf(!within(s.ActivityTickInterval.Duration, 100*time.Millisecond, time.Minute),
+		"activity_tick_interval must be between 100ms and 1m, got %s", s.ActivityTickInterval)
+	addIf(!within(s.FlushTickInterval.Duration, time.Second, time.Hour),
+		"flush_tick_interval must be between 1s and 1h, got %s", s.FlushTickInterval)
+	addIf(s.FlushTickInterval.Duration < s.ActivityTickInterval.Duration,
+		"flush_tick_interval (%s) must not be shorter than activity_tick_interval (%s)", s.FlushTickInterval, s.ActivityTickInterval)
+
+	// tracking
+	addIf(!within(s.DailyTarget.Duration, time.Minute, 24*time.Hour),
+		"daily_target must be between 1m and 24h, got %s", s.DailyTarget)
+
+	// interface
+	addIf(!within(s.ThemeScale, 0.5, 3), "theme_scale must be between 0.5 and 3, got %.2f", s.ThemeScale)
+	addIf(!slices.Contains(ThemeModes, s.ThemeMode), "theme_mode '%s' is not one of %v", s.ThemeMode, ThemeModes)
+	_, colorErr := ParseHexColor(s.AccentColor)
+	addIf(s.AccentColor != "" && colorErr != nil, "accent_color '%s' is not a #RRGGBB color", s.AccentColor)
+	addIf(!within(s.WindowWidth, 320, 7680), "window_width must be between 320 and 7680, got %.0f", s.WindowWidth)
+	addIf(!within(s.WindowHeight, 240, 4320), "window_height must be between 240 and 4320, got %.0f", s.WindowHeight)
+	addIf(!within(s.MiniWidth, 160, 7680), "mini_width must be between 160 and 7680, got %.0f", s.MiniWidth)
+	addIf(!within(s.MiniHeight, 80, 4320), "mini_height must be between 80 and 4320, got %.0f", s.MiniHeight)
+	addIf(s.ActivityWindow.Duration != 0 && !within(s.ActivityWindow.Duration, time.Minute, time.Hour),
+		"activity_window must be 0 (last tick) or between 1m and 1h, got %s", s.ActivityWindow)
+
+	// locale
+	problems = append(problems, s.Locale.Problems()...)
+
+	// schedule
+	problems = append(problems, s.Schedule.problems()...)
+
+	// focus
+	problems = append(problems, s.Focus.problems()...)
+
+	// task table
+	problems = append(problems, s.TaskTable.problems()...)
+
+	// lock
+	addIf(!slices.Contains(LockActions, s.Lock.Action), "lock.action '%s' is not one of %v", s.Lock.Action, LockActions)
+
+	// notifications
+	reminders := map[string]Reminder{
+		"break_reminder":       s.Notifications.BreakReminder,
+		"idle_while_running":   s.Notifications.IdleWhileRunning,
+		"active_while_stopped": s.Notifications.ActiveWhileStopped,
+	}
+	for _, name := range slices.Sorted(maps.Keys(reminders)) {
+		reminder := reminders[name]
+		addIf(!within(reminder.After.Duration, 0, 24*time.Hour),
+			"notifications.%s.after must be between 0 (off) and 24h, got %s", name, reminder.After)
+		addIf(reminder.Repeat.Duration != 0 && !within(reminder.Repeat.Duration, time.Minute, 24*time.Hour),
+			"notifications.%s.repeat must be 0 (once) or between 1m and 24h, got %s", name, reminder.Repeat)
+	}
+	addIf(s.Notifications.WorkdayEndRepeat.Duration != 0 && !within(s.Notifications.WorkdayEndRepeat.Duration, time.Minute, 24*time.Hour),
+		"notifications.workday_end_repeat must be 0 (once) or between 1m and 24h, got %s", s.Notifications.WorkdayEndRepeat)
+	badClock := func(clock string) bool {
+		_, err := ParseClock(clock)
+		return clock != "" && err != nil
+	}
+	addIf(badClock(s.Notifications.WorkdayEnd), "notifications.workday_end '%s' is not an HH:MM time", s.Notifications.WorkdayEnd)
+	addIf(badClock(s.Notifications.QuietFrom), "notifications.quiet_from '%s' is not an HH:MM time", s.Notifications.QuietFrom)
+	addIf(badClock(s.Notifications.QuietTo), "notifications.quiet_to '%s' is not an HH:MM time", s.Notifications.QuietTo)
+	addIf((s.Notifications.QuietFrom == "") != (s.Notifications.QuietTo == ""),
+		"notifications.quiet_from and quiet_to must be set together")
+
+	// report
+	addIf(!slices.Contains(report.AllPresets, report.Preset(s.Report.Preset)), "report.preset '%s' is not one of %v", s.Report.Preset, report.AllPresets)
+	addIf(strings.TrimSpace(s.Report.OutputPath) == "", "report.output_path must not be empty")
+	_, tzErr := time.LoadLocation(s.Report.Timezone)
+	addIf(tzErr != nil, "report.timezone '%s' is not a known IANA timezone", s.Report.Timezone)
+	addIf(s.Report.BarRef.Duration <= 0, "report.bar_ref must be positive, got %s", s.Report.BarRef)
+	addIf(!within(s.Report.Smooth, 0, 1), "report.smooth must be between 0 and 1, got %.2f", s.Report.Smooth)
+	addIf(email.IsValidProvider(email.Provider(s.Report.Provider)) != nil, "report.provider '%s' is not one of %v", s.Report.Provider, email.AllowedProviders)
+	addIf(s.Report.Sender != "" && !strings.Contains(s.Report.Sender, "@"), "report.sender '%s' is not an email address", s.Report.Sender)
+	for _, recipient := range s.Report.Recipients {
+		addIf(!strings.Contains(recipient, "@"), "report.recipients: '%s' is not an email address", recipient)
+	}
+
+	return problems
+}
+
+// Validate wraps Problems into a single error (nil when there are none).
+func (s Settings) Validate() (e *xerr.Error) {
+	problems := s.Problems()
+	if len(problems) == 0 {
+		return nil
+	}
+	return xerr.NewErrorECML(errors.New("invalid settings"), "settings failed validation", "problems", strings.Join(problems, "\n"))
+}
+
+// ParseHexColor parses "#RRGGBB" (the leading '#' is optional).
+func ParseHexColor(text string) (c color.NRGBA, err error) {
+	text = strings.TrimPrefix(strings.TrimSpace(text), "#")
+	if len(text) != 6 {
+		return c, fmt.Errorf("expected 6 hex digits, got '%s'", text)
+	}
+	_, err = fmt.Sscanf(text, "%02x%02x%02x", &c.R, &c.G, &c.B)
+	if err != nil {
+		return c, err
+	}
+	c.A = 255
+	return c, nil
+}
+
+// ParseClock parses "HH:MM" (24h) into the time since midnight.
+func ParseClock(text string) (sinceMidnight time.Duration, err error) {
+	clock, err := time.Parse("15:04", strings.TrimSpace(text))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+func within[T time.Duration | float32 | float64](v, min, max T) bool {
+	return v >= min && v <= max
+}
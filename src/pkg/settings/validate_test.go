@@ -0,0 +1,65 @@
+package settings
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"00:00", 0, false},
+		{"09:30", 9*time.Hour + 30*time.Minute, false},
+		{"9:30", 9*time.Hour + 30*time.Minute, false},
+		{" 23:59 ", 23*time.Hour + 59*time.Minute, false},
+		{"24:00", 0, true},
+		{"12:60", 0, true},
+		{"9am", 0, true},
+		{"", 0, true},
+	}
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			got, err := ParseClock(test.text)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParseClock(%q) error %v, want error %v", test.text, err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("ParseClock(%q) = %s, want %s", test.text, got, test.want)
+			}
+		})
+	}
+}
+
+func TestInQuietHours(t *testing.T) {
+	clock := func(hour, minute int) time.Time { return time.Date(2026, 1, 23, hour, minute, 0, 0, time.UTC) }
+	tests := []struct {
+		name     string
+		from, to string
+		now      time.Time
+		want     bool
+	}{
+		{"inside the same day", "12:00", "13:00", clock(12, 30), true},
+		{"at the start", "12:00", "13:00", clock(12, 0), true},
+		{"at the end", "12:00", "13:00", clock(13, 0), false},
+		{"before", "12:00", "13:00", clock(11, 59), false},
+		{"crossing midnight, late", "22:00", "07:00", clock(23, 0), true},
+		{"crossing midnight, early", "22:00", "07:00", clock(6, 59), true},
+		{"crossing midnight, at the end", "22:00", "07:00", clock(7, 0), false},
+		{"crossing midnight, daytime", "22:00", "07:00", clock(12, 0), false},
+		{"crossing midnight, at midnight", "22:00", "07:00", clock(0, 0), true},
+		{"same from and to is off", "22:00", "22:00", clock(22, 0), false},
+		{"unset", "", "", clock(12, 0), false},
+		{"half set", "22:00", "", clock(23, 0), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			n := NotificationSettings{QuietFrom: test.from, QuietTo: test.to}
+			if got := n.InQuietHours(test.now); got != test.want {
+				t.Errorf("InQuietHours(%s) from %q to %q = %v, want %v", test.now.Format("15:04"), test.from, test.to, got, test.want)
+			}
+		})
+	}
+}
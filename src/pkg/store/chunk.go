@@ -0,0 +1,159 @@
+/*
+Package store owns the day files: the chunk schema, where each day's file lives
+under the work dir, what makes a chunk valid, and reading and writing them.
+The tracker and the reports both go through it, so the format is defined once.
+
+A day file is JSON lines, one Chunk per line, blank lines and lines starting
+with # are ignored. Every line written carries the SchemaVersion it follows.
+*/
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+/*
+SchemaVersion is the version of the chunk schema written by this build.
+
+	0  lines without "schema_version", from before it was added; durations may be strings
+	1  "schema_version" on every line, durations in nanoseconds
+
+Older lines are still read as they are, the migrate command rewrites them.
+*/
+const SchemaVersion = 1
+
+// chunk kinds; work chunks leave Kind empty so files written before pauses existed stay valid
+const (
+	ChunkKindWork  = ""
+	ChunkKindPause = "pause" // non-work span, not counted in worked time
+	ChunkKindFocus = "focus" // record of a focus session's work interval, its time is already in the work chunks
+)
+
+// focus session modes
+const (
+	FocusModePomodoro = "pomodoro"
+	FocusModeTimebox  = "timebox"
+)
+
+// Chunk is one line of a day file.
+type Chunk struct {
+	SchemaVersion int `json:"schema_version"` // 0 when the line has none, set to SchemaVersion on write
+
+	TaskName    string        `json:"task_name"` // "" is unassigned
+	StartedAt   time.Time     `json:"started_at"`
+	FinishedAt  time.Time     `json:"finished_at"`
+	ActiveTime  time.Duration `json:"active_time"`            // written as nanoseconds, "1m30s" is read too
+	Kind        string        `json:"kind,omitempty"`         // ChunkKindWork, ChunkKindPause or ChunkKindFocus
+	PauseReason string        `json:"pause_reason,omitempty"` // pause chunks only
+	StartReason string        `json:"start_reason,omitempty"` // first chunk of a run not started by hand
+	StopReason  string        `json:"stop_reason,omitempty"`  // last chunk of a run (or a pause) not stopped by hand
+	Note        string        `json:"note,omitempty"`         // what was done, free text
+	Focus       *FocusRecord  `json:"focus,omitempty"`        // focus chunks only
+
+	// unassigned work chunks only: the task suggested when the run started, to confirm later
+	SuggestedTask string `json:"suggested_task,omitempty"`
+}
+
+// FocusRecord is how one pomodoro or timebox went, from StartedAt to FinishedAt of its chunk.
+type FocusRecord struct {
+	Mode          string        `json:"mode"`          // FocusModePomodoro or FocusModeTimebox
+	Planned       time.Duration `json:"planned"`       // length it was meant to have, read like ActiveTime
+	Completed     bool          `json:"completed"`     // ran out on the task, false when stopped early
+	Interruptions int           `json:"interruptions"` // pauses and switches to other tasks during it
+}
+
+// Duration is the chunk's length.
+func (c Chunk) Duration() time.Duration {
+	return c.FinishedAt.Sub(c.StartedAt)
+}
+
+/*
+Validate reports what is wrong with c, nil when it's fine. Kinds it doesn't
+know are not an error, readers skip what they don't understand.
+*/
+func (c Chunk) Validate() error {
+	switch {
+	case c.SchemaVersion > SchemaVersion:
+		return fmt.Errorf("schema_version %v is newer than this build's %v", c.SchemaVersion, SchemaVersion)
+	case c.StartedAt.IsZero():
+		return errors.New("started_at is missing")
+	case c.FinishedAt.IsZero():
+		return errors.New("finished_at is missing")
+	case !c.FinishedAt.After(c.StartedAt):
+		return errors.New("finished_at is not after started_at")
+	case c.ActiveTime < 0 || c.ActiveTime > c.Duration():
+		return errors.New("active_time is not within the chunk")
+	case c.Kind == ChunkKindFocus && c.Focus == nil:
+		return errors.New("focus chunk without a focus record")
+	}
+	return nil
+}
+
+// Clamped is c with ActiveTime brought within zero and the chunk's length.
+func (c Chunk) Clamped() Chunk {
+	c.ActiveTime = min(max(c.ActiveTime, 0), max(c.Duration(), 0))
+	return c
+}
+
+func (c *Chunk) UnmarshalJSON(b []byte) error {
+	type plain Chunk // without this method
+	var raw struct {
+		*plain
+		ActiveTime jsonDuration `json:"active_time"`
+	}
+	raw.plain = (*plain)(c)
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		return err
+	}
+	c.ActiveTime = raw.ActiveTime.Duration
+	return nil
+}
+
+func (f *FocusRecord) UnmarshalJSON(b []byte) error {
+	type plain FocusRecord // without this method
+	var raw struct {
+		*plain
+		Planned jsonDuration `json:"planned"`
+	}
+	raw.plain = (*plain)(f)
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		return err
+	}
+	f.Planned = raw.Planned.Duration
+	return nil
+}
+
+/*
+jsonDuration reads a duration either as a JSON number (nanoseconds) or as a
+string: time.ParseDuration's "999ms", "1.23s", or a bare number of nanoseconds.
+*/
+type jsonDuration struct{ time.Duration }
+
+func (d *jsonDuration) UnmarshalJSON(b []byte) error {
+	var n int64
+	if json.Unmarshal(b, &n) == nil {
+		d.Duration = time.Duration(n)
+		return nil
+	}
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+	s = strings.TrimSpace(s)
+	parsed, parseErr := time.ParseDuration(s)
+	if parseErr != nil {
+		if json.Unmarshal([]byte(s), &n) != nil {
+			return parseErr
+		}
+		parsed = time.Duration(n)
+	}
+	d.Duration = parsed
+	return nil
+}
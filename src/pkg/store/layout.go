@@ -0,0 +1,261 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+/*
+A Layout says where each day's chunks live under the work dir, as a path
+template over LayoutFields, slash separated, for example
+
+	{{.Year}}/{{.MonthName}}/{{.Day02}}_{{.MonthName}}_{{.Year}}.jsonl   (DefaultLayout)
+	{{.Year}}/{{.Month02}}/{{.Date}}.jsonl                                a file a day, numeric months
+	{{.Year}}/{{.Year}}-{{.Month02}}.jsonl                                a file a month
+	{{.Year}}.jsonl                                                       a file a year
+
+A template that leaves the day out puts a month's or a year's days in one file,
+they are told apart by the day their chunks started on.
+*/
+
+// DefaultLayout is the layout day files have always had.
+const DefaultLayout = "{{.Year}}/{{.MonthName}}/{{.Day02}}_{{.MonthName}}_{{.Year}}.jsonl"
+
+// LayoutFields are what a layout template can use.
+type LayoutFields struct {
+	Year      string // "2026"
+	Month     string // "1"
+	Month02   string // "01"
+	MonthName string // "january", always English
+	Day       string // "5"
+	Day02     string // "05"
+	Date      string // "2026-01-05"
+}
+
+// Span is how many days share one file.
+type Span int
+
+const (
+	SpanDay Span = iota
+	SpanMonth
+	SpanYear
+)
+
+// what each field matches when a path is read back into a date
+var layoutFieldPatterns = map[string]string{
+	"Year":      `(\d{4})`,
+	"Month":     `(\d{1,2})`,
+	"Month02":   `(\d{2})`,
+	"MonthName": `([a-z]+)`,
+	"Day":       `(\d{1,2})`,
+	"Day02":     `(\d{2})`,
+	"Date":      `(\d{4}-\d{2}-\d{2})`,
+}
+
+type Layout struct {
+	Text string
+	Span Span
+
+	template *template.Template
+	pattern  *regexp.Regexp // matches a relative, slash separated path
+	fields   []string       // the field each of pattern's groups is
+}
+
+/*
+ParseLayout checks that text is a usable layout: every file it names is under
+the work dir, and each day, month or year gets a file of its own whose path
+reads back into its date.
+*/
+func ParseLayout(text string) (layout *Layout, err error) {
+	tmpl, err := template.New("layout").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	layout = &Layout{Text: text, template: tmpl}
+
+	// the pattern is the template rendered with a marker for each field
+	var markers LayoutFields
+	markerOf := map[string]*string{
+		"Year": &markers.Year, "Month": &markers.Month, "Month02": &markers.Month02, "MonthName": &markers.MonthName,
+		"Day": &markers.Day, "Day02": &markers.Day02, "Date": &markers.Date,
+	}
+	for name, marker := range markerOf {
+		*marker = "\x00" + name + "\x00"
+	}
+	var rendered strings.Builder
+	err = tmpl.Execute(&rendered, markers)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(rendered.String(), "\x00")
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	for i, part := range parts {
+		if i%2 == 0 {
+			pattern.WriteString(regexp.QuoteMeta(part))
+			continue
+		}
+		pattern.WriteString(layoutFieldPatterns[part])
+		layout.fields = append(layout.fields, part)
+	}
+	pattern.WriteString("$")
+	layout.pattern, err = regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// a leap year is enough to see which days share a file and that every path reads back
+	filesOf := make(map[string][]time.Time)
+	for day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); day.Year() == 2024; day = day.AddDate(0, 0, 1) {
+		relativePath, err := layout.relativePath(day)
+		if err != nil {
+			return nil, err
+		}
+		if !filepath.IsLocal(filepath.FromSlash(relativePath)) {
+			return nil, fmt.Errorf("'%s' is not a path inside the work dir", relativePath)
+		}
+		filesOf[relativePath] = append(filesOf[relativePath], day)
+	}
+	nextYear, _ := layout.relativePath(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	switch {
+	case len(filesOf) == 366:
+		layout.Span = SpanDay
+	case len(filesOf) == 12:
+		layout.Span = SpanMonth
+	case len(filesOf) == 1 && filesOf[nextYear] == nil:
+		layout.Span = SpanYear
+	default:
+		return nil, errors.New("the layout must give each day, month or year a file of its own")
+	}
+	for relativePath, days := range filesOf {
+		first := layout.spanStart(days[0])
+		date, ok := layout.parse(relativePath, time.UTC)
+		if !ok || !date.Equal(first) || slices.ContainsFunc(days, func(day time.Time) bool { return !layout.spanStart(day).Equal(first) }) {
+			return nil, fmt.Errorf("'%s' doesn't read back into the date it was made from", relativePath)
+		}
+	}
+	return layout, nil
+}
+
+// MustParseLayout is ParseLayout for layouts known to be fine, it panics otherwise.
+func MustParseLayout(text string) *Layout {
+	layout, err := ParseLayout(text)
+	if err != nil {
+		panic(fmt.Sprintf("bad layout '%s': %s", text, err))
+	}
+	return layout
+}
+
+// DayFilePath is the directory and file of day's chunks under workDir.
+func (l *Layout) DayFilePath(workDir string, day time.Time) (dir, file string) {
+	relativePath, _ := l.relativePath(day) // executed once in ParseLayout, fields are plain strings
+	file = filepath.Join(workDir, filepath.FromSlash(relativePath))
+	return filepath.Dir(file), file
+}
+
+/*
+FileDate is the first day of the file at path, somewhere under workDir, in
+loc. ok is false when the path isn't laid out like a day file.
+*/
+func (l *Layout) FileDate(workDir, path string, loc *time.Location) (date time.Time, ok bool) {
+	relativePath, err := filepath.Rel(workDir, path)
+	if err != nil {
+		return date, false
+	}
+	return l.parse(filepath.ToSlash(relativePath), loc)
+}
+
+/*
+OnDay tells whether chunk belongs to day. In a file of its own every chunk does,
+even one started the evening before and written after midnight; in a file
+shared by several days it's the day the chunk started on, so such a chunk only
+counts when it's in the file that holds that day.
+*/
+func (l *Layout) OnDay(day time.Time, chunk Chunk) bool {
+	if l.Span == SpanDay {
+		return true
+	}
+	y1, m1, d1 := day.Date()
+	y2, m2, d2 := chunk.StartedAt.In(day.Location()).Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
+
+func (l *Layout) relativePath(day time.Time) (relativePath string, err error) {
+	var rendered strings.Builder
+	err = l.template.Execute(&rendered, LayoutFields{
+		Year:      day.Format("2006"),
+		Month:     day.Format("1"),
+		Month02:   day.Format("01"),
+		MonthName: strings.ToLower(day.Format("January")),
+		Day:       day.Format("2"),
+		Day02:     day.Format("02"),
+		Date:      day.Format("2006-01-02"),
+	})
+	return rendered.String(), err
+}
+
+// spanStart is the first day of the file day is in
+func (l *Layout) spanStart(day time.Time) time.Time {
+	switch l.Span {
+	case SpanMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	case SpanYear:
+		return time.Date(day.Year(), 1, 1, 0, 0, 0, 0, day.Location())
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+}
+
+// parse reads a relative, slash separated path back into the first day of its file
+func (l *Layout) parse(relativePath string, loc *time.Location) (date time.Time, ok bool) {
+	groups := l.pattern.FindStringSubmatch(relativePath)
+	if groups == nil {
+		return date, false
+	}
+	year, month, day := -1, -1, -1
+	// a value given twice must agree
+	set := func(target *int, value int) bool {
+		if *target != -1 && *target != value {
+			return false
+		}
+		*target = value
+		return true
+	}
+	for i, field := range l.fields {
+		value := groups[i+1]
+		number, _ := strconv.Atoi(value)
+		switch field {
+		case "Year":
+			ok = set(&year, number)
+		case "Month", "Month02":
+			ok = set(&month, number)
+		case "MonthName":
+			parsed, err := time.Parse("January", value) // any case
+			ok = err == nil && set(&month, int(parsed.Month()))
+		case "Day", "Day02":
+			ok = set(&day, number)
+		case "Date":
+			parsed, err := time.Parse("2006-01-02", value)
+			ok = err == nil && set(&year, parsed.Year()) && set(&month, int(parsed.Month())) && set(&day, parsed.Day())
+		}
+		if !ok {
+			return date, false
+		}
+	}
+	if year == -1 {
+		return date, false
+	}
+	month, day = max(month, 1), max(day, 1)
+	date = time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc)
+	// no 31st of February
+	if date.Month() != time.Month(month) || date.Day() != day {
+		return date, false
+	}
+	return date, true
+}
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseLayout(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		span   Span
+		reject bool
+	}{
+		{"default", DefaultLayout, SpanDay, false},
+		{"numeric months", "{{.Year}}/{{.Month02}}/{{.Date}}.jsonl", SpanDay, false},
+		{"unpadded", "{{.Year}}/{{.Month}}/{{.Day}}.jsonl", SpanDay, false},
+		{"a file a month", "{{.Year}}/{{.Year}}-{{.Month02}}.jsonl", SpanMonth, false},
+		{"a file a month by name", "{{.Year}}/{{.MonthName}}.jsonl", SpanMonth, false},
+		{"a file a year", "{{.Year}}.jsonl", SpanYear, false},
+		{"no year", "{{.MonthName}}/{{.Day02}}.jsonl", 0, true},
+		{"no year, a file a month", "{{.Month02}}.jsonl", 0, true},
+		{"day without month", "{{.Year}}/{{.Day02}}.jsonl", 0, true},
+		{"no fields", "days.jsonl", 0, true},
+		{"escapes the work dir", "../{{.Year}}/{{.Date}}.jsonl", 0, true},
+		{"escapes through a field", "{{.Year}}/../../{{.Date}}.jsonl", 0, true},
+		{"absolute", "/{{.Year}}/{{.Date}}.jsonl", 0, true},
+		{"ambiguous", "{{.Year}}/{{.Month}}{{.Day}}.jsonl", 0, true},
+		{"unknown field", "{{.Year}}/{{.Week}}.jsonl", 0, true},
+		{"not a template", "{{.Year}/{{.Date}}.jsonl", 0, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			layout, err := ParseLayout(test.text)
+			if test.reject {
+				if err == nil {
+					t.Errorf("ParseLayout(%q) = span %v, want an error", test.text, layout.Span)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLayout(%q): %s", test.text, err)
+			}
+			if layout.Span != test.span {
+				t.Errorf("ParseLayout(%q).Span = %v, want %v", test.text, layout.Span, test.span)
+			}
+		})
+	}
+}
+
+func TestLayoutRoundTrip(t *testing.T) {
+	workDir := t.TempDir()
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	layouts := []string{
+		DefaultLayout,
+		"{{.Year}}/{{.Month}}/{{.Day}}.jsonl",
+		"{{.Year}}/{{.Year}}-{{.Month02}}.jsonl",
+		"{{.Year}}.jsonl",
+	}
+	for _, text := range layouts {
+		t.Run(text, func(t *testing.T) {
+			layout := MustParseLayout(text)
+			// every day of a year that isn't the leap year ParseLayout checks with, at noon
+			for day := time.Date(2026, 1, 1, 12, 0, 0, 0, loc); day.Year() == 2026; day = day.AddDate(0, 0, 1) {
+				_, file := layout.DayFilePath(workDir, day)
+				date, ok := layout.FileDate(workDir, file, loc)
+				if want := layout.spanStart(day); !ok || !date.Equal(want) {
+					t.Fatalf("FileDate(%s) = %s, %v; want %s", file, date, ok, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLayoutParse(t *testing.T) {
+	daily := MustParseLayout(DefaultLayout)
+	numeric := MustParseLayout("{{.Year}}/{{.Month02}}/{{.Date}}.jsonl")
+	monthly := MustParseLayout("{{.Year}}/{{.Year}}-{{.Month02}}.jsonl")
+	date := func(month time.Month, day int) time.Time { return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name   string
+		layout *Layout
+		path   string
+		date   time.Time
+		ok     bool
+	}{
+		{"default", daily, "2026/january/23_january_2026.jsonl", date(1, 23), true},
+		{"months disagree", daily, "2026/january/23_february_2026.jsonl", time.Time{}, false},
+		{"years disagree", daily, "2026/january/23_january_2025.jsonl", time.Time{}, false},
+		{"no such day", daily, "2026/february/30_february_2026.jsonl", time.Time{}, false},
+		{"not a month", daily, "2026/smarch/01_smarch_2026.jsonl", time.Time{}, false},
+		{"legacy flat file", daily, "23_january_2026.jsonl", time.Time{}, false},
+		{"another file", daily, "2026/january/notes.txt", time.Time{}, false},
+		{"date", numeric, "2026/01/2026-01-23.jsonl", date(1, 23), true},
+		{"date against its dir", numeric, "2026/02/2026-01-23.jsonl", time.Time{}, false},
+		{"month", monthly, "2026/2026-02.jsonl", date(2, 1), true},
+		{"no such month", monthly, "2026/2026-13.jsonl", time.Time{}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			date, ok := test.layout.parse(test.path, time.UTC)
+			if ok != test.ok || (ok && !date.Equal(test.date)) {
+				t.Errorf("parse(%q) = %s, %v; want %s, %v", test.path, date, ok, test.date, test.ok)
+			}
+		})
+	}
+}
+
+func TestOnDay(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	daily, monthly := MustParseLayout(DefaultLayout), MustParseLayout("{{.Year}}/{{.Year}}-{{.Month02}}.jsonl")
+	jan31, feb1 := time.Date(2026, 1, 31, 0, 0, 0, 0, loc), time.Date(2026, 2, 1, 0, 0, 0, 0, loc)
+	// started at 23:59 on the 31st, written after midnight
+	lateChunk := Chunk{StartedAt: jan31.Add(23*time.Hour + 59*time.Minute), FinishedAt: feb1.Add(5 * time.Minute)}
+	tests := []struct {
+		name   string
+		layout *Layout
+		day    time.Time
+		chunk  Chunk
+		want   bool
+	}{
+		{"a file a day, the day it started", daily, jan31, lateChunk, true},
+		{"a file a day, the file it's in", daily, feb1, lateChunk, true},
+		{"shared, the day it started", monthly, jan31, lateChunk, true},
+		{"shared, the day it finished", monthly, feb1, lateChunk, false},
+		// 03:00 UTC on the 1st is still the 31st where the day is
+		{"shared, in the day's location", monthly, jan31, Chunk{StartedAt: time.Date(2026, 2, 1, 3, 0, 0, 0, time.UTC)}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.layout.OnDay(test.day, test.chunk); got != test.want {
+				t.Errorf("OnDay(%s, chunk started %s) = %v, want %v", test.day.Format(time.DateOnly), test.chunk.StartedAt, got, test.want)
+			}
+		})
+	}
+}
+
+func TestDayFilePath(t *testing.T) {
+	workDir := filepath.Join("work", "out")
+	dir, file := MustParseLayout(DefaultLayout).DayFilePath(workDir, time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC))
+	if want := filepath.Join(workDir, "2026", "january"); dir != want {
+		t.Errorf("dir = %q, want %q", dir, want)
+	}
+	if want := filepath.Join(workDir, "2026", "january", "05_january_2026.jsonl"); file != want {
+		t.Errorf("file = %q, want %q", file, want)
+	}
+}
@@ -0,0 +1,347 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+)
+
+/*
+Migrate brings a data dir up to the current SchemaVersion and into a layout.
+
+Day files are looked for in FromLayout, in DefaultLayout and as legacy files
+kept flat in the data dir itself as <D>_<monthname>_<YEAR>.jsonl. Every chunk
+is rewritten at the current version into its day's file in Layout, which also
+moves a data dir from one layout to another. A file that gets chunks from more
+than one file, or already exists in the target dir, is merged in order of start
+without repeating chunks. Running it again changes nothing.
+
+Nothing is written until every file has been read, so a bad line stops the
+migration before it starts. In place, the files about to be rewritten or
+removed are copied to Backup first. Into another dir, From is left as it is.
+*/
+
+// MigrateOptions says what to migrate and where to.
+type MigrateOptions struct {
+	From       string  // data dir to migrate
+	To         string  // where the migrated day files go; empty migrates From in place
+	Backup     string  // in place only: dir the changed files are copied to first, required then
+	DryRun     bool    // only log what would be done
+	Layout     *Layout // layout to write, nil is DefaultLayout
+	FromLayout *Layout // layout From is in, nil is Layout
+}
+
+// MigrateResult counts what Migrate did, or would do in a dry run.
+type MigrateResult struct {
+	Files     int // day files in the target dir after the migration
+	Rewritten int // day files written
+	Moved     int // files whose chunks went into other files, in place only
+	Unchanged int // day files already current
+	Backup    string
+}
+
+// legacy day file, directly in the data dir; day unpadded or not, month name in any case
+var legacyDayFilePattern = regexp.MustCompile(`(?i)^\d{1,2}_[a-z]+_\d{4}\.jsonl$`)
+
+// sourceFile is a day file found in the data dir
+type sourceFile struct {
+	Path   string
+	Date   time.Time // first day of the file
+	Layout *Layout   // nil for a legacy file
+}
+
+// migrateFile is one day file in the target dir and the files its chunks come from
+type migrateFile struct {
+	Target  string
+	Sources []string // in the order they were read
+	Content []byte   // the target's new content
+	Changed bool     // Content differs from what the target holds
+	lines   []migrateLine
+}
+
+type migrateLine struct {
+	Line  []byte
+	Start time.Time
+}
+
+func Migrate(options MigrateOptions) (result MigrateResult, e *xerr.Error) {
+	from := filepath.Clean(options.From)
+	to := filepath.Clean(options.To)
+	inPlace := options.To == "" || to == from
+	if inPlace {
+		to = from
+		if options.Backup == "" {
+			return result, xerr.NewErrorECOL(errors.New("no backup dir"), "refusing to migrate in place without a backup", "dir", from)
+		}
+	}
+	layout := options.Layout
+	if layout == nil {
+		layout = MustParseLayout(DefaultLayout)
+	}
+	fromLayout := options.FromLayout
+	if fromLayout == nil {
+		fromLayout = layout
+	}
+	tl.Log(
+		tl.Notice, palette.Blue, "%s '%s' to schema version %v into '%s', layout '%s'",
+		"Migrating", from, SchemaVersion, to, layout.Text,
+	)
+
+	layouts := []*Layout{fromLayout}
+	if fromLayout.Text != DefaultLayout {
+		layouts = append(layouts, MustParseLayout(DefaultLayout))
+	}
+	sources, e := findDayFiles(from, []string{to, filepath.Clean(options.Backup)}, layouts)
+	if e != nil {
+		return result, e
+	}
+
+	// read everything before writing anything
+	files, e := planFiles(to, layout, sources, inPlace)
+	if e != nil {
+		return result, e
+	}
+
+	result.Files = len(files)
+	var backupFiles, removeFiles []string
+	for _, file := range files {
+		if !file.Changed {
+			result.Unchanged++
+			continue
+		}
+		result.Rewritten++
+		_, statErr := os.Stat(file.Target)
+		if inPlace && statErr == nil {
+			backupFiles = append(backupFiles, file.Target)
+		}
+	}
+	if inPlace {
+		for _, source := range sources {
+			if !slices.ContainsFunc(files, func(file migrateFile) bool { return file.Target == source.Path }) {
+				result.Moved++
+				backupFiles = append(backupFiles, source.Path)
+				removeFiles = append(removeFiles, source.Path)
+			}
+		}
+	}
+
+	if options.DryRun {
+		for _, file := range files {
+			if file.Changed {
+				tl.Log(tl.Info, palette.Cyan, "%s '%s' from '%s'", "Would write", file.Target, strings.Join(file.Sources, "', '"))
+			}
+		}
+		for _, source := range removeFiles {
+			tl.Log(tl.Info, palette.Cyan, "%s '%s'", "Would remove", source)
+		}
+		return result, nil
+	}
+
+	if len(backupFiles) > 0 {
+		e = backUp(from, options.Backup, backupFiles)
+		if e != nil {
+			return result, e
+		}
+		result.Backup = options.Backup
+	}
+	for _, file := range files {
+		if !file.Changed {
+			continue
+		}
+		e = replaceFile(file.Target, file.Content)
+		if e != nil {
+			return result, e
+		}
+	}
+	for _, source := range removeFiles {
+		err := os.Remove(source)
+		if err != nil {
+			return result, xerr.NewErrorECOL(err, "failed to remove migrated file", "file_path", source)
+		}
+		// and the dirs that held only moved files, a dir that isn't empty stays
+		dir := filepath.Dir(source)
+		for dir != from && os.Remove(dir) == nil {
+			dir = filepath.Dir(dir)
+		}
+	}
+
+	tl.Log(
+		tl.Notice, palette.Green, "%s '%s'. Files: %v, rewritten: %v, moved: %v, unchanged: %v",
+		"Migrated", from, result.Files, result.Rewritten, result.Moved, result.Unchanged,
+	)
+	return result, nil
+}
+
+/*
+findDayFiles walks from for day files in any of layouts or the legacy flat
+layout, in path order. Hidden dirs and skipDirs are not looked into.
+*/
+func findDayFiles(from string, skipDirs []string, layouts []*Layout) (sources []sourceFile, e *xerr.Error) {
+	err := filepath.WalkDir(from, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != from && (strings.HasPrefix(entry.Name(), ".") || slices.Contains(skipDirs, path)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		for _, layout := range layouts {
+			date, ok := layout.FileDate(from, path, time.Local)
+			if ok {
+				sources = append(sources, sourceFile{Path: path, Date: date, Layout: layout})
+				return nil
+			}
+		}
+		name := filepath.Base(path)
+		if filepath.Dir(path) != from || !legacyDayFilePattern.MatchString(name) {
+			return nil
+		}
+		date, parseErr := time.ParseInLocation("2_January_2006.jsonl", name, time.Local) // month names match in any case
+		if parseErr != nil {
+			tl.Log(tl.Notice, palette.Purple, "%s '%s', the name is not a date: %s", "Skipping", path, parseErr)
+			return nil
+		}
+		sources = append(sources, sourceFile{Path: path, Date: date})
+		return nil
+	})
+	if err != nil {
+		return nil, xerr.NewErrorECOL(err, "failed to walk the data dir", "dir", from)
+	}
+	return sources, nil
+}
+
+/*
+planFiles reads sources and sorts their chunks into the files of layout under
+to, rendering each file's new content. A chunk goes to the file of its source's
+day, or of the day it started on when either layout shares files between days.
+Chunks of a single file keep their order; merged files are put in order of start
+and chunks that appear in more than one of them are kept once. A file that
+exists under to and gets chunks is merged with; in place, a source that is
+already where layout puts it is kept even when it has no chunks.
+*/
+func planFiles(to string, layout *Layout, sources []sourceFile, inPlace bool) (files []migrateFile, e *xerr.Error) {
+	indexOf := make(map[string]int)
+	fileFor := func(target string) *migrateFile {
+		i, found := indexOf[target]
+		if !found {
+			i = len(files)
+			indexOf[target] = i
+			files = append(files, migrateFile{Target: target})
+		}
+		return &files[i]
+	}
+	addSource := func(file *migrateFile, source string) {
+		if !slices.Contains(file.Sources, source) {
+			file.Sources = append(file.Sources, source)
+		}
+	}
+
+	for _, source := range sources {
+		chunks, e := ReadFile(source.Path, Strict)
+		if e != nil {
+			return nil, e
+		}
+		if inPlace {
+			_, home := layout.DayFilePath(to, source.Date)
+			if home == source.Path {
+				addSource(fileFor(home), source.Path)
+			}
+		}
+		for _, chunk := range chunks {
+			// a day file can hold a chunk started the day before, in a shared file only that day reads it
+			day := source.Date
+			if layout.Span != SpanDay || (source.Layout != nil && source.Layout.Span != SpanDay) {
+				start := chunk.StartedAt.In(time.Local)
+				day = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.Local)
+			}
+			line, e := marshalChunk(source.Path, chunk)
+			if e != nil {
+				return nil, e
+			}
+			_, target := layout.DayFilePath(to, day)
+			file := fileFor(target)
+			addSource(file, source.Path)
+			file.lines = append(file.lines, migrateLine{Line: line, Start: chunk.StartedAt})
+		}
+	}
+
+	for i := range files {
+		file := &files[i]
+		existing, err := os.ReadFile(file.Target)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, xerr.NewErrorECOL(err, "failed to read day file", "file_path", file.Target)
+		}
+		// a target that is not one of the sources, in another dir or layout, is merged with
+		if err == nil && !slices.Contains(file.Sources, file.Target) {
+			chunks, e := ReadFile(file.Target, Strict)
+			if e != nil {
+				return nil, e
+			}
+			var lines []migrateLine
+			for _, chunk := range chunks {
+				line, e := marshalChunk(file.Target, chunk)
+				if e != nil {
+					return nil, e
+				}
+				lines = append(lines, migrateLine{Line: line, Start: chunk.StartedAt})
+			}
+			file.Sources = append([]string{file.Target}, file.Sources...)
+			file.lines = append(lines, file.lines...)
+		}
+
+		lines := file.lines
+		if len(file.Sources) > 1 {
+			seen := make(map[string]bool)
+			lines = slices.DeleteFunc(slices.Clone(lines), func(line migrateLine) bool {
+				repeated := seen[string(line.Line)]
+				seen[string(line.Line)] = true
+				return repeated
+			})
+			slices.SortStableFunc(lines, func(a, b migrateLine) int { return a.Start.Compare(b.Start) })
+		}
+		for _, line := range lines {
+			file.Content = append(file.Content, line.Line...)
+		}
+		file.Changed = err != nil || !bytes.Equal(existing, file.Content)
+	}
+	slices.SortFunc(files, func(a, b migrateFile) int { return strings.Compare(a.Target, b.Target) })
+	return files, nil
+}
+
+// backUp copies files, all under from, to the same relative paths under backup
+func backUp(from, backup string, files []string) (e *xerr.Error) {
+	tl.Log(tl.Info, palette.Blue, "%s %v files to '%s'", "Backing up", len(files), backup)
+	for _, file := range files {
+		relativePath, err := filepath.Rel(from, file)
+		if err != nil {
+			return xerr.NewErrorECOL(err, "failed to place file in the backup", "file_path", file)
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return xerr.NewErrorECOL(err, "failed to read file to back up", "file_path", file)
+		}
+		backupPath := filepath.Join(backup, relativePath)
+		err = os.MkdirAll(filepath.Dir(backupPath), 0o755)
+		if err != nil {
+			return xerr.NewErrorECOL(err, "failed to create backup directory", "dir", filepath.Dir(backupPath))
+		}
+		err = os.WriteFile(backupPath, content, 0o644)
+		if err != nil {
+			return xerr.NewErrorECOL(err, "failed to write backup", "file_path", backupPath)
+		}
+	}
+	tl.Log(tl.Info, palette.Green, "%s %v files to '%s'", "Backed up", len(files), backup)
+	return nil
+}
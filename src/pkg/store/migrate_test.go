@@ -0,0 +1,290 @@
+package store
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// legacyLine is a chunk line as written before schema_version, the active time as a string
+func legacyLine(task string, start, finish time.Time, active string) string {
+	return fmt.Sprintf(
+		`{"task_name":%q,"started_at":%q,"finished_at":%q,"active_time":%q}`+"\n",
+		task, start.Format(time.RFC3339), finish.Format(time.RFC3339), active,
+	)
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err == nil {
+		err = os.WriteFile(path, []byte(content), 0o644)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// snapshot is every file under dir by relative path, nil when dir doesn't exist
+func snapshot(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(dir, path)
+		files[filepath.ToSlash(relativePath)] = string(content)
+		return err
+	})
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+/*
+newMixedDataDir is a data dir with legacy flat files, one of them for a day that
+also has a default-layout file, a default-layout file that is already current
+and a file that isn't a day file.
+*/
+func newMixedDataDir(t *testing.T) (dir string) {
+	t.Helper()
+	dir = t.TempDir()
+	clock := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 1, day, hour, minute, 0, 0, time.Local)
+	}
+	writeTestFile(t, filepath.Join(dir, "23_january_2026.jsonl"),
+		legacyLine("Code", clock(23, 9, 0), clock(23, 10, 0), "45m")+legacyLine("Email", clock(23, 10, 0), clock(23, 10, 30), "20m"))
+	writeTestFile(t, filepath.Join(dir, "5_JANUARY_2026.jsonl"), legacyLine("Code", clock(5, 14, 0), clock(5, 15, 0), "1h"))
+	writeTestFile(t, filepath.Join(dir, "notes.txt"), "not a day file\n")
+
+	writer := NewWriter(dir, MustParseLayout(DefaultLayout))
+	e := writer.RewriteDay(clock(5, 0, 0), []Chunk{{TaskName: "Review", StartedAt: clock(5, 9, 0), FinishedAt: clock(5, 10, 0), ActiveTime: time.Hour}})
+	if e == nil {
+		e = writer.RewriteDay(clock(24, 0, 0), []Chunk{{TaskName: "Code", StartedAt: clock(24, 9, 0), FinishedAt: clock(24, 9, 30), ActiveTime: 10 * time.Minute}})
+	}
+	if e != nil {
+		t.Fatalf("write: %s", e.Msg)
+	}
+	return dir
+}
+
+func TestMigrateInPlace(t *testing.T) {
+	dir := newMixedDataDir(t)
+	backup := filepath.Join(t.TempDir(), "backup")
+	before := snapshot(t, dir)
+
+	result, e := Migrate(MigrateOptions{From: dir, Backup: backup})
+	if e != nil {
+		t.Fatalf("Migrate: %s", e.Msg)
+	}
+	want := MigrateResult{Files: 3, Rewritten: 2, Moved: 2, Unchanged: 1, Backup: backup}
+	if result != want {
+		t.Errorf("Migrate = %+v, want %+v", result, want)
+	}
+
+	after := snapshot(t, dir)
+	wantFiles := []string{
+		"2026/january/05_january_2026.jsonl",
+		"2026/january/23_january_2026.jsonl",
+		"2026/january/24_january_2026.jsonl",
+		"notes.txt",
+	}
+	if files := slices.Sorted(maps.Keys(after)); !slices.Equal(files, wantFiles) {
+		t.Errorf("files after = %v, want %v", files, wantFiles)
+	}
+
+	// the removed legacy files and the default-layout file merged into are backed up as they were
+	backedUp := snapshot(t, backup)
+	wantBackup := []string{"2026/january/05_january_2026.jsonl", "23_january_2026.jsonl", "5_JANUARY_2026.jsonl"}
+	if files := slices.Sorted(maps.Keys(backedUp)); !slices.Equal(files, wantBackup) {
+		t.Errorf("backup = %v, want %v", files, wantBackup)
+	}
+	for path, content := range backedUp {
+		if content != before[path] {
+			t.Errorf("backup of %s = %q, want %q", path, content, before[path])
+		}
+	}
+
+	// the merged day is in order of start, every chunk at the current version
+	reader := NewReader(dir, MustParseLayout(DefaultLayout), Strict)
+	tests := []struct {
+		day   int
+		tasks []string
+	}{
+		{5, []string{"Review", "Code"}},
+		{23, []string{"Code", "Email"}},
+		{24, []string{"Code"}},
+	}
+	for _, test := range tests {
+		chunks, e := reader.ReadDay(time.Date(2026, 1, test.day, 0, 0, 0, 0, time.Local))
+		if e != nil {
+			t.Fatalf("read January %v: %s", test.day, e.Msg)
+		}
+		var tasks []string
+		for _, chunk := range chunks {
+			tasks = append(tasks, chunk.TaskName)
+			if chunk.SchemaVersion != SchemaVersion {
+				t.Errorf("January %v, %s: schema version %v, want %v", test.day, chunk.TaskName, chunk.SchemaVersion, SchemaVersion)
+			}
+		}
+		if !slices.Equal(tasks, test.tasks) {
+			t.Errorf("January %v: tasks %v, want %v", test.day, tasks, test.tasks)
+		}
+	}
+
+	// running it again changes nothing and backs nothing up
+	backupAgain := filepath.Join(t.TempDir(), "backup")
+	result, e = Migrate(MigrateOptions{From: dir, Backup: backupAgain})
+	if e != nil {
+		t.Fatalf("second Migrate: %s", e.Msg)
+	}
+	want = MigrateResult{Files: 3, Unchanged: 3}
+	if result != want {
+		t.Errorf("second Migrate = %+v, want %+v", result, want)
+	}
+	if again := snapshot(t, dir); !maps.Equal(again, after) {
+		t.Error("second Migrate changed the data dir")
+	}
+	if backedUp := snapshot(t, backupAgain); backedUp != nil {
+		t.Errorf("second Migrate backed up %v", slices.Sorted(maps.Keys(backedUp)))
+	}
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	dir := newMixedDataDir(t)
+	backup := filepath.Join(t.TempDir(), "backup")
+	before := snapshot(t, dir)
+
+	result, e := Migrate(MigrateOptions{From: dir, Backup: backup, DryRun: true})
+	if e != nil {
+		t.Fatalf("Migrate: %s", e.Msg)
+	}
+	want := MigrateResult{Files: 3, Rewritten: 2, Moved: 2, Unchanged: 1}
+	if result != want {
+		t.Errorf("Migrate = %+v, want %+v", result, want)
+	}
+	if after := snapshot(t, dir); !maps.Equal(after, before) {
+		t.Error("a dry run changed the data dir")
+	}
+	if backedUp := snapshot(t, backup); backedUp != nil {
+		t.Errorf("a dry run backed up %v", slices.Sorted(maps.Keys(backedUp)))
+	}
+}
+
+func TestMigrateIntoAnotherDir(t *testing.T) {
+	dir := newMixedDataDir(t)
+	to := t.TempDir()
+	before := snapshot(t, dir)
+
+	result, e := Migrate(MigrateOptions{From: dir, To: to})
+	if e != nil {
+		t.Fatalf("Migrate: %s", e.Msg)
+	}
+	want := MigrateResult{Files: 3, Rewritten: 3}
+	if result != want {
+		t.Errorf("Migrate = %+v, want %+v", result, want)
+	}
+	if after := snapshot(t, dir); !maps.Equal(after, before) {
+		t.Error("Migrate into another dir changed From")
+	}
+	if files := snapshot(t, to); len(files) != 3 {
+		t.Errorf("%v files in To, want 3", len(files))
+	}
+}
+
+func TestMigrateStopsOnBadLine(t *testing.T) {
+	start := time.Date(2026, 1, 9, 9, 0, 0, 0, time.Local)
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"not JSON", "{\"task_name\":\n"},
+		{"invalid chunk", legacyLine("Code", start, start.Add(-time.Hour), "0s")},
+		{"active time too long", legacyLine("Code", start, start.Add(time.Hour), "2h")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := newMixedDataDir(t)
+			// after every other file in path order, so the good ones are planned first
+			writeTestFile(t, filepath.Join(dir, "9_january_2026.jsonl"), legacyLine("Email", start, start.Add(time.Hour), "5m")+test.line)
+			backup := filepath.Join(t.TempDir(), "backup")
+			before := snapshot(t, dir)
+
+			_, e := Migrate(MigrateOptions{From: dir, Backup: backup})
+			if e == nil {
+				t.Fatal("Migrate should fail")
+			}
+			if after := snapshot(t, dir); !maps.Equal(after, before) {
+				t.Error("a failed Migrate changed the data dir")
+			}
+			if backedUp := snapshot(t, backup); backedUp != nil {
+				t.Errorf("a failed Migrate backed up %v", slices.Sorted(maps.Keys(backedUp)))
+			}
+		})
+	}
+}
+
+func TestMigrateInPlaceNeedsBackup(t *testing.T) {
+	dir := newMixedDataDir(t)
+	before := snapshot(t, dir)
+	_, e := Migrate(MigrateOptions{From: dir})
+	if e == nil {
+		t.Fatal("Migrate in place without a backup should fail")
+	}
+	if after := snapshot(t, dir); !maps.Equal(after, before) {
+		t.Error("a refused Migrate changed the data dir")
+	}
+}
+
+func TestMigrateIntoMonthlyFilesKeepsLateChunk(t *testing.T) {
+	from, to := t.TempDir(), t.TempDir()
+	jan31, feb1 := time.Date(2026, 1, 31, 0, 0, 0, 0, time.Local), time.Date(2026, 2, 1, 0, 0, 0, 0, time.Local)
+	// started at 23:59 on the 31st and flushed into the 1st's file
+	late := Chunk{TaskName: "Late", StartedAt: jan31.Add(23*time.Hour + 59*time.Minute), FinishedAt: feb1.Add(5 * time.Minute), ActiveTime: time.Minute}
+	early := Chunk{TaskName: "Early", StartedAt: feb1.Add(9 * time.Hour), FinishedAt: feb1.Add(10 * time.Hour), ActiveTime: time.Hour}
+	e := NewWriter(from, MustParseLayout(DefaultLayout)).RewriteDay(feb1, []Chunk{late, early})
+	if e != nil {
+		t.Fatalf("write: %s", e.Msg)
+	}
+
+	monthly := MustParseLayout("{{.Year}}/{{.Year}}-{{.Month02}}.jsonl")
+	_, e = Migrate(MigrateOptions{From: from, To: to, Layout: monthly, FromLayout: MustParseLayout(DefaultLayout)})
+	if e != nil {
+		t.Fatalf("Migrate: %s", e.Msg)
+	}
+	reader := NewReader(to, monthly, Strict)
+	tests := []struct {
+		day   time.Time
+		tasks []string
+	}{
+		{jan31, []string{"Late"}},
+		{feb1, []string{"Early"}},
+	}
+	for _, test := range tests {
+		chunks, e := reader.ReadDay(test.day)
+		if e != nil {
+			t.Fatalf("read %s: %s", test.day.Format(time.DateOnly), e.Msg)
+		}
+		var tasks []string
+		for _, chunk := range chunks {
+			tasks = append(tasks, chunk.TaskName)
+		}
+		if !slices.Equal(tasks, test.tasks) {
+			t.Errorf("%s: tasks %v, want %v", test.day.Format(time.DateOnly), tasks, test.tasks)
+		}
+	}
+}
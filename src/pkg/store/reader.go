@@ -0,0 +1,213 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+)
+
+// Policy is what a Reader does with a line that isn't a valid chunk.
+type Policy int
+
+const (
+	Strict  Policy = iota // the first bad line is an error, for files that are written back
+	Lenient               // bad lines are logged and skipped, an active time outside its chunk clamped; for reports and history
+)
+
+// longest line a day file may have
+const maxLineLength = 2 * 1024 * 1024
+
+// Reader reads the day files under WorkDir, laid out by Layout.
+type Reader struct {
+	WorkDir string
+	Layout  *Layout
+	Policy  Policy
+}
+
+func NewReader(workDir string, layout *Layout, policy Policy) *Reader {
+	return &Reader{WorkDir: workDir, Layout: layout, Policy: policy}
+}
+
+// Day is one day file and its chunks, in the order they were written.
+type Day struct {
+	Date   time.Time // midnight, in the location the range was asked for in
+	Path   string    // shared with the other days of its month or year in such layouts
+	Chunks []Chunk   // nil when the file is missing
+}
+
+// ReadDay reads day's chunks from its file, a missing file has no chunks.
+func (r *Reader) ReadDay(day time.Time) (chunks []Chunk, e *xerr.Error) {
+	_, filePath := r.Layout.DayFilePath(r.WorkDir, day)
+	chunks, e = ReadFile(filePath, r.Policy)
+	return r.onDay(day, chunks), e
+}
+
+// onDay is the part of chunks, read from day's file, that is day's
+func (r *Reader) onDay(day time.Time, chunks []Chunk) (dayChunks []Chunk) {
+	if r.Layout.Span == SpanDay {
+		return chunks
+	}
+	for _, chunk := range chunks {
+		if r.Layout.OnDay(day, chunk) {
+			dayChunks = append(dayChunks, chunk)
+		}
+	}
+	return dayChunks
+}
+
+/*
+ReadFile reads every chunk of a day file in one pass. A missing file means no
+chunks. Lines that don't parse or validate are an error or skipped, by policy;
+Lenient keeps a chunk whose only fault is its active time, clamped.
+*/
+func ReadFile(filePath string, policy Policy) (chunks []Chunk, e *xerr.Error) {
+	fileHandle, openErr := os.Open(filePath)
+	if errors.Is(openErr, fs.ErrNotExist) {
+		tl.Log(tl.Notice, palette.PurpleBold, "No such file: '%s', %s", filePath, "skipping this step")
+		return nil, nil
+	}
+	if openErr != nil {
+		return nil, xerr.NewErrorECOL(openErr, "failed to open day file", "path", filePath)
+	}
+	defer fileHandle.Close()
+
+	scanner := bufio.NewScanner(fileHandle)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+	var lineNumber int64
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var chunk Chunk
+		err := json.Unmarshal([]byte(line), &chunk)
+		if err == nil {
+			err = chunk.Validate()
+			// reports have always counted such a chunk with its active time clamped
+			if err != nil && policy == Lenient && chunk.Clamped().Validate() == nil {
+				tl.Log(tl.Notice, palette.Purple, "%s active time in '%s' line %d: %s", "Clamping", filePath, lineNumber, err)
+				chunk, err = chunk.Clamped(), nil
+			}
+		}
+		if err == nil {
+			chunks = append(chunks, chunk)
+			continue
+		}
+		if policy == Lenient {
+			tl.Log(tl.Notice, palette.Purple, "%s bad chunk in '%s' line %d: %s", "Skipping", filePath, lineNumber, err)
+			continue
+		}
+		tl.Log(tl.Notice, palette.Purple, "Premature exit on a bad chunk at line %v in '%s'", lineNumber, filePath)
+		return chunks, xerr.NewErrorECML(err, "invalid chunk in day file", "line", map[string]any{
+			"path":        filePath,
+			"line_number": lineNumber,
+			"text":        line,
+		})
+	}
+
+	scanErr := scanner.Err()
+	if scanErr != nil {
+		return chunks, xerr.NewErrorECML(scanErr, "scanner error while reading day file", "line", map[string]any{
+			"path":      filePath,
+			"last_line": lineNumber,
+		})
+	}
+	return chunks, nil
+}
+
+/*
+Days goes through the days from from to to, both included, one Day each
+(missing files included, with no chunks). A day that can't be read comes with
+its error, the caller decides whether to go on. A file shared by several days
+is read once.
+*/
+func (r *Reader) Days(from, to time.Time) iter.Seq2[Day, *xerr.Error] {
+	return func(yield func(Day, *xerr.Error) bool) {
+		first := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+		last := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, from.Location())
+		var readPath string
+		var chunks []Chunk
+		var e *xerr.Error
+		for date := first; !date.After(last); date = date.AddDate(0, 0, 1) {
+			_, filePath := r.Layout.DayFilePath(r.WorkDir, date)
+			if filePath != readPath {
+				readPath = filePath
+				chunks, e = ReadFile(filePath, r.Policy)
+			}
+			if !yield(Day{Date: date, Path: filePath, Chunks: r.onDay(date, chunks)}, e) {
+				return
+			}
+		}
+	}
+}
+
+/*
+Files goes through every day file under WorkDir, in path order, whatever its
+date. Files that are laid out like day files but can't be read come with their
+error, other files are passed over. A file shared by several days comes as one
+Day for each day that has chunks in it, in order.
+*/
+func (r *Reader) Files() iter.Seq2[Day, *xerr.Error] {
+	return func(yield func(Day, *xerr.Error) bool) {
+		// the walk never fails by itself, its errors are yielded
+		_ = filepath.WalkDir(r.WorkDir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				if !yield(Day{Path: path}, xerr.NewErrorECOL(err, "failed to walk the work dir", "path", path)) {
+					return filepath.SkipAll
+				}
+				return nil
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			date, ok := r.Layout.FileDate(r.WorkDir, path, time.Local)
+			if !ok {
+				return nil
+			}
+			chunks, e := ReadFile(path, r.Policy)
+			if r.Layout.Span == SpanDay || e != nil {
+				if !yield(Day{Date: date, Path: path, Chunks: chunks}, e) {
+					return filepath.SkipAll
+				}
+				return nil
+			}
+			for _, day := range splitDays(path, chunks) {
+				if !yield(day, nil) {
+					return filepath.SkipAll
+				}
+			}
+			return nil
+		})
+	}
+}
+
+// splitDays groups the chunks of a shared file by the local day they started on
+func splitDays(path string, chunks []Chunk) (days []Day) {
+	indexOf := make(map[time.Time]int)
+	for _, chunk := range chunks {
+		start := chunk.StartedAt.In(time.Local)
+		date := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.Local)
+		i, found := indexOf[date]
+		if !found {
+			i = len(days)
+			indexOf[date] = i
+			days = append(days, Day{Date: date, Path: path})
+		}
+		days[i].Chunks = append(days[i].Chunks, chunk)
+	}
+	slices.SortStableFunc(days, func(a, b Day) int { return a.Date.Compare(b.Date) })
+	return days
+}
@@ -0,0 +1,149 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+)
+
+// Writer writes the day files under WorkDir, laid out by Layout. Chunks are validated before anything is written.
+type Writer struct {
+	WorkDir string
+	Layout  *Layout
+}
+
+func NewWriter(workDir string, layout *Layout) *Writer {
+	return &Writer{WorkDir: workDir, Layout: layout}
+}
+
+// AppendDay adds chunk to the end of day's file, creating it and its directory when needed.
+func (w *Writer) AppendDay(day time.Time, chunk Chunk) (e *xerr.Error) {
+	_, filePath := w.Layout.DayFilePath(w.WorkDir, day)
+	return AppendFile(filePath, chunk)
+}
+
+/*
+RewriteDay replaces day's chunks with chunks, see RewriteFile. In a file shared
+by several days the other days' chunks are kept, the earlier ones before and
+the later ones after.
+*/
+func (w *Writer) RewriteDay(day time.Time, chunks []Chunk) (e *xerr.Error) {
+	_, filePath := w.Layout.DayFilePath(w.WorkDir, day)
+	if w.Layout.Span == SpanDay {
+		return RewriteFile(filePath, chunks)
+	}
+	fileChunks, e := ReadFile(filePath, Strict)
+	if e != nil {
+		return e
+	}
+	var before, after []Chunk
+	for _, chunk := range fileChunks {
+		switch {
+		case w.Layout.OnDay(day, chunk):
+		case chunk.StartedAt.Before(day):
+			before = append(before, chunk)
+		default:
+			after = append(after, chunk)
+		}
+	}
+	return RewriteFile(filePath, slices.Concat(before, chunks, after))
+}
+
+// AppendFile adds chunk to the end of a day file, creating it and its directory when needed.
+func AppendFile(filePath string, chunk Chunk) (e *xerr.Error) {
+	line, e := marshalChunk(filePath, chunk)
+	if e != nil {
+		return e
+	}
+	err := os.MkdirAll(filepath.Dir(filePath), 0o755)
+	if err != nil {
+		return xerr.NewErrorECOL(err, "failed to create day file directory", "dir", filepath.Dir(filePath))
+	}
+
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return xerr.NewErrorECOL(err, "failed to open file for appending", "file_path", filePath)
+	}
+	defer f.Close()
+	_, err = f.Write(line)
+	if err != nil {
+		return xerr.NewError(err, "failed to write chunk to file", map[string]any{
+			"file_path": filePath,
+			"chunk":     chunk,
+		})
+	}
+	return nil
+}
+
+/*
+RewriteFile replaces a whole day file with chunks.
+
+Used by the actions that change history (retroactive switch, undo, split). The
+file is written to a temporary sibling first and renamed over the original, so
+a crash never leaves a half-written day. Comment lines of the old file are not kept.
+*/
+func RewriteFile(filePath string, chunks []Chunk) (e *xerr.Error) {
+	tl.Log(tl.Detailed, palette.Blue, "%s %d chunks to file: '%s'", "Rewriting", len(chunks), filePath)
+
+	var buf bytes.Buffer
+	for _, chunk := range chunks {
+		line, e := marshalChunk(filePath, chunk)
+		if e != nil {
+			return e
+		}
+		buf.Write(line)
+	}
+
+	e = replaceFile(filePath, buf.Bytes())
+	if e != nil {
+		return e
+	}
+
+	tl.Log(tl.Detailed1, palette.Green, "%s %d chunks to file: '%s'", "Rewrote", len(chunks), filePath)
+	return nil
+}
+
+// replaceFile writes content to a temporary sibling of filePath and renames it over filePath
+func replaceFile(filePath string, content []byte) (e *xerr.Error) {
+	err := os.MkdirAll(filepath.Dir(filePath), 0o755)
+	if err != nil {
+		return xerr.NewErrorECOL(err, "failed to create day file directory", "dir", filepath.Dir(filePath))
+	}
+	tmpPath := filePath + ".tmp"
+	err = os.WriteFile(tmpPath, content, 0o644)
+	if err != nil {
+		return xerr.NewErrorECOL(err, "failed to write day file", "file_path", tmpPath)
+	}
+	err = os.Rename(tmpPath, filePath)
+	if err != nil {
+		return xerr.NewErrorECOL(err, "failed to replace day file", "file_path", filePath)
+	}
+	return nil
+}
+
+// marshalChunk is chunk's line at the current SchemaVersion, newline included, if it's valid
+func marshalChunk(filePath string, chunk Chunk) (line []byte, e *xerr.Error) {
+	chunk.SchemaVersion = SchemaVersion
+	err := chunk.Validate()
+	if err != nil {
+		return nil, xerr.NewError(err, "refusing to write an invalid chunk", map[string]any{
+			"file_path": filePath,
+			"chunk":     chunk,
+		})
+	}
+	line, err = json.Marshal(chunk)
+	if err != nil {
+		return nil, xerr.NewError(err, "failed to marshal chunk", map[string]any{
+			"file_path": filePath,
+			"chunk":     chunk,
+		})
+	}
+	return append(line, '\n'), nil
+}
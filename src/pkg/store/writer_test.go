@@ -0,0 +1,82 @@
+package store
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRewriteDay(t *testing.T) {
+	clock := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 1, day, hour, minute, 0, 0, time.Local)
+	}
+	chunk := func(task string, day, hour, minute int) Chunk {
+		return Chunk{TaskName: task, StartedAt: clock(day, hour, minute), FinishedAt: clock(day, hour, minute+30), ActiveTime: 10 * time.Minute}
+	}
+	// a month's file: the 5th, two chunks on the 23rd and one started at 23:59 on the 31st
+	month := []Chunk{chunk("A", 5, 9, 0), chunk("B", 23, 9, 0), chunk("C", 23, 10, 0), chunk("D", 31, 23, 59)}
+	tests := []struct {
+		name   string
+		layout string
+		day    int
+		chunks []Chunk
+		want   []string // tasks in the file, in order
+	}{
+		{"a day in the middle", "{{.Year}}/{{.Year}}-{{.Month02}}.jsonl", 23, []Chunk{chunk("X", 23, 11, 0)}, []string{"A", "X", "D"}},
+		{"the first day", "{{.Year}}/{{.Year}}-{{.Month02}}.jsonl", 5, []Chunk{chunk("X", 5, 8, 0), chunk("Y", 5, 9, 0)}, []string{"X", "Y", "B", "C", "D"}},
+		{"a day without chunks", "{{.Year}}/{{.Year}}-{{.Month02}}.jsonl", 10, []Chunk{chunk("X", 10, 9, 0)}, []string{"A", "X", "B", "C", "D"}},
+		{"emptied", "{{.Year}}/{{.Year}}-{{.Month02}}.jsonl", 23, nil, []string{"A", "D"}},
+		{"the last day, past midnight", "{{.Year}}.jsonl", 31, []Chunk{chunk("X", 31, 9, 0)}, []string{"A", "B", "C", "X"}},
+		{"a file a day", DefaultLayout, 23, []Chunk{chunk("X", 23, 11, 0)}, []string{"X"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			writer := NewWriter(t.TempDir(), MustParseLayout(test.layout))
+			day := clock(test.day, 0, 0)
+			_, filePath := writer.Layout.DayFilePath(writer.WorkDir, day)
+			e := RewriteFile(filePath, month)
+			if e != nil {
+				t.Fatalf("write: %s", e.Msg)
+			}
+
+			e = writer.RewriteDay(day, test.chunks)
+			if e != nil {
+				t.Fatalf("RewriteDay: %s", e.Msg)
+			}
+			chunks, e := ReadFile(filePath, Strict)
+			if e != nil {
+				t.Fatalf("read: %s", e.Msg)
+			}
+			var tasks []string
+			for _, chunk := range chunks {
+				tasks = append(tasks, chunk.TaskName)
+			}
+			if !slices.Equal(tasks, test.want) {
+				t.Errorf("file holds %v, want %v", tasks, test.want)
+			}
+		})
+	}
+}
+
+func TestRewriteDayRefusesInvalidChunk(t *testing.T) {
+	writer := NewWriter(t.TempDir(), MustParseLayout("{{.Year}}.jsonl"))
+	day := time.Date(2026, 1, 23, 0, 0, 0, 0, time.Local)
+	kept := Chunk{TaskName: "A", StartedAt: day.Add(-time.Hour), FinishedAt: day.Add(-time.Minute)}
+	e := writer.AppendDay(day, kept)
+	if e != nil {
+		t.Fatalf("append: %s", e.Msg)
+	}
+
+	e = writer.RewriteDay(day, []Chunk{{TaskName: "B", StartedAt: day.Add(time.Hour), FinishedAt: day}})
+	if e == nil {
+		t.Fatal("RewriteDay should refuse a chunk that ends before it starts")
+	}
+	_, filePath := writer.Layout.DayFilePath(writer.WorkDir, day)
+	chunks, e := ReadFile(filePath, Strict)
+	if e != nil {
+		t.Fatalf("read: %s", e.Msg)
+	}
+	if len(chunks) != 1 || chunks[0].TaskName != "A" {
+		t.Errorf("file holds %+v after a refused rewrite, want only A", chunks)
+	}
+}
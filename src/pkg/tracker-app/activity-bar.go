@@ -2,11 +2,13 @@ package trackerapp
 
 import (
 	"fmt"
-	"image/color"
 	"math"
+	"slices"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
@@ -16,16 +18,19 @@ type ActivityBar struct {
 
 	Caption    string  // e.g., "Average activity"
 	percent    float64 // 0..100
-	col        color.Color
 	WidthRatio float32 // fraction of available width to use for the bar (0..1), e.g. 0.8 for 80%
+
+	// rolling windows, clicking the bar moves to the next one; fewer than two makes it fixed
+	Windows         []time.Duration
+	Window          time.Duration
+	OnWindowChanged func(window time.Duration)
 }
 
 func NewActivityBar(caption string) *ActivityBar {
 	ab := &ActivityBar{
 		Caption:    caption,
 		percent:    0,
-		col:        color.NRGBA{R: 200, G: 40, B: 40, A: 255}, // start red-ish
-		WidthRatio: 0.5,                                       // default to 80%
+		WidthRatio: 0.5, // default to 80%
 	}
 	ab.ExtendBaseWidget(ab)
 	return ab
@@ -39,7 +44,6 @@ func (a *ActivityBar) SetPercent(p float64) {
 		p = 100
 	}
 	a.percent = p
-	a.col = barColorFor(p)
 	a.Refresh()
 }
 
@@ -56,6 +60,32 @@ func (a *ActivityBar) SetWidthRatio(r float32) {
 
 func (a *ActivityBar) Percent() float64 { return a.percent }
 
+// SetCaption changes the text above the bar, e.g. when the window changes.
+func (a *ActivityBar) SetCaption(caption string) {
+	a.Caption = caption
+	a.Refresh()
+}
+
+// Tapped moves to the next window.
+func (a *ActivityBar) Tapped(*fyne.PointEvent) {
+	if len(a.Windows) < 2 {
+		return
+	}
+	next := (slices.Index(a.Windows, a.Window) + 1) % len(a.Windows) // an unknown window starts over
+	a.Window = a.Windows[next]
+	if a.OnWindowChanged != nil {
+		a.OnWindowChanged(a.Window)
+	}
+}
+
+// Cursor shows the bar can be clicked when it has windows to pick from.
+func (a *ActivityBar) Cursor() desktop.Cursor {
+	if len(a.Windows) < 2 {
+		return desktop.DefaultCursor
+	}
+	return desktop.PointerCursor
+}
+
 // --- widget.Renderer ---
 
 type activityBarRenderer struct {
@@ -73,9 +103,9 @@ func (a *ActivityBar) CreateRenderer() fyne.WidgetRenderer {
 	cap.TextSize = theme.TextSize()
 
 	bg := canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground))
-	fill := canvas.NewRectangle(a.col)
+	fill := canvas.NewRectangle(barColorFor(a.percent))
 
-	txt := canvas.NewText(fmt.Sprintf("%.1f%%", a.percent), theme.Color(theme.ColorNameForeground))
+	txt := canvas.NewText(fmt.Sprintf("%.1f%%", a.percent), theme.Color(colorNameActivityText))
 	txt.Alignment = fyne.TextAlignCenter
 	txt.TextSize = theme.TextSize()
 
@@ -116,7 +146,7 @@ func (r *activityBarRenderer) Layout(sz fyne.Size) {
 
 	// Fill width by percent
 	fillW := float32(float64(innerW) * (r.a.percent / 100.0))
-	r.fill.FillColor = r.a.col
+	r.fill.FillColor = barColorFor(r.a.percent) // resolved on every layout so variant switches recolor the bar
 	r.fill.Move(fyne.NewPos(innerX, barY))
 	r.fill.Resize(fyne.NewSize(fillW, barH))
 
@@ -134,6 +164,13 @@ func (r *activityBarRenderer) MinSize() fyne.Size {
 }
 
 func (r *activityBarRenderer) Refresh() {
+	// follow theme changes (scale and variant)
+	r.caption.Text = r.a.Caption
+	r.caption.TextSize = theme.TextSize()
+	r.caption.Color = theme.Color(theme.ColorNameForeground)
+	r.percentT.TextSize = theme.TextSize()
+	r.percentT.Color = theme.Color(colorNameActivityText)
+	r.bg.FillColor = theme.Color(theme.ColorNameInputBackground)
 	r.Layout(r.a.Size())
 	canvas.Refresh(r.a)
 }
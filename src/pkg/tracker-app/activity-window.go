@@ -0,0 +1,131 @@
+package trackerapp
+
+import (
+	"slices"
+	"time"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+
+	"work-tracker/src/pkg/settings"
+	"work-tracker/src/pkg/store"
+)
+
+/*
+The current activity bar shows a rolling window (settings.ActivityWindow,
+picked by clicking the bar) instead of only the last tick, and the sparkline
+under the bars shows the last hour. Both come from activitySamples: one per
+activity tick while tracking, seeded from today's chunks when the day is opened
+so a restart doesn't begin from nothing.
+*/
+
+// windows the current activity bar cycles through, 0 is the last tick
+var activityWindows = []time.Duration{0, 5 * time.Minute, 15 * time.Minute, time.Hour}
+
+const (
+	activityHistory  = time.Hour // samples kept, and the sparkline's span
+	sparklineBuckets = 120       // points on the sparkline, 30s each
+)
+
+type activitySample struct {
+	At     time.Time // end of the tick (or chunk, for seeded samples)
+	Span   time.Duration
+	Active time.Duration
+}
+
+// recordActivityLocked keeps one activity tick and forgets the ones older than activityHistory. Caller holds t.Mutex.
+func (t *TrackerApp) recordActivityLocked(now time.Time, span, active time.Duration) {
+	t.activitySamples = append(t.activitySamples, activitySample{At: now, Span: span, Active: active})
+	cutoff := now.Add(-activityHistory)
+	first, _ := slices.BinarySearchFunc(t.activitySamples, cutoff, func(sample activitySample, at time.Time) int { return sample.At.Compare(at) })
+	t.activitySamples = t.activitySamples[first:]
+}
+
+// activitySamplesFromChunks turns the work chunks of the last hour into samples, oldest first
+func activitySamplesFromChunks(chunks []store.Chunk, now time.Time) (samples []activitySample) {
+	cutoff := now.Add(-activityHistory)
+	for _, chunk := range chunks {
+		if chunk.Kind != store.ChunkKindWork || !chunk.FinishedAt.After(cutoff) {
+			continue
+		}
+		samples = append(samples, activitySample{At: chunk.FinishedAt, Span: chunk.FinishedAt.Sub(chunk.StartedAt), Active: chunk.ActiveTime})
+	}
+	slices.SortFunc(samples, func(a, b activitySample) int { return a.At.Compare(b.At) })
+	return samples
+}
+
+/*
+activityOverLocked is the activity percentage of the time tracked in the last window. Caller holds t.Mutex.
+A sample reaching back past the window's start (a seeded chunk, mostly) counts with the part
+inside it, its active time in proportion.
+*/
+func (t *TrackerApp) activityOverLocked(now time.Time, window time.Duration) float64 {
+	var span, active time.Duration
+	windowStart := now.Add(-window)
+	for _, sample := range t.activitySamples {
+		start := sample.At.Add(-sample.Span)
+		inside := earliest(sample.At, now).Sub(latest(start, windowStart))
+		if inside <= 0 {
+			continue
+		}
+		span += inside
+		active += time.Duration(float64(sample.Active) * float64(inside) / float64(sample.Span))
+	}
+	return getActivityPercentage(active, span)
+}
+
+/*
+sparklineLocked is the activity of the last hour in sparklineBuckets slices,
+oldest first, -1 where nothing was tracked. Caller holds t.Mutex.
+*/
+func (t *TrackerApp) sparklineLocked(now time.Time) (values []float64) {
+	bucketLength := activityHistory / sparklineBuckets
+	spans := make([]time.Duration, sparklineBuckets)
+	actives := make([]time.Duration, sparklineBuckets)
+	for _, sample := range t.activitySamples {
+		age := now.Sub(sample.At)
+		if age < 0 || age >= activityHistory {
+			continue
+		}
+		bucket := sparklineBuckets - 1 - int(age/bucketLength)
+		spans[bucket] += sample.Span
+		actives[bucket] += sample.Active
+	}
+	values = make([]float64, sparklineBuckets)
+	for i := range values {
+		values[i] = -1
+		if spans[i] > 0 {
+			values[i] = getActivityPercentage(actives[i], spans[i])
+		}
+	}
+	return values
+}
+
+// activityWindowCaption is the current activity bar's caption for window
+func (t *TrackerApp) activityWindowCaption(window time.Duration) string {
+	if window <= 0 {
+		return t.Locale.T("CurrentActivity")
+	}
+	return t.Locale.T("ActivityLastMinutes", "Minutes", int(window.Minutes()))
+}
+
+/*
+setActivityWindow is called when the current activity bar is clicked to another
+window. Only activity_window is written to the settings file.
+Must run on the UI goroutine.
+*/
+func (t *TrackerApp) setActivityWindow(window time.Duration) {
+	tl.Log(tl.Info, palette.Cyan, "%s. Window: %s", "Changing current activity window", window)
+	t.Mutex.Lock()
+	t.Settings.ActivityWindow = settings.Duration{Duration: window}
+	t.Mutex.Unlock()
+	t.CurrentActivityBar.SetCaption(t.activityWindowCaption(window))
+	go t.updateInterface()
+
+	e := settings.Update(t.SettingsPath, func(saved *settings.Settings) {
+		saved.ActivityWindow = settings.Duration{Duration: window}
+	})
+	if e != nil {
+		tl.Log(tl.Warning, palette.Yellow, "%s: %s", "Activity window not saved", e.Msg)
+	}
+}
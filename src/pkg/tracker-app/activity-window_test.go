@@ -0,0 +1,39 @@
+package trackerapp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActivityOverLocked(t *testing.T) {
+	now := at(10, 0)
+	tests := []struct {
+		name    string
+		samples []activitySample
+		window  time.Duration
+		want    float64
+	}{
+		{"ticks inside the window", []activitySample{
+			{At: at(9, 58), Span: time.Minute, Active: time.Minute},
+			{At: at(9, 59), Span: time.Minute, Active: 0},
+		}, 5 * time.Minute, 50},
+		{"ticks before the window left out", []activitySample{
+			{At: at(9, 50), Span: time.Minute, Active: 0},
+			{At: at(9, 59), Span: time.Minute, Active: time.Minute},
+		}, 5 * time.Minute, 100},
+		// a seeded chunk, 9:00-9:56 and half active: its last minute counts, not all 56
+		{"a seeded chunk only counts inside the window", []activitySample{
+			{At: at(9, 56), Span: 56 * time.Minute, Active: 28 * time.Minute},
+			{At: at(10, 0), Span: 4 * time.Minute, Active: 4 * time.Minute},
+		}, 5 * time.Minute, 90},
+		{"nothing tracked", nil, 5 * time.Minute, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := &TrackerApp{activitySamples: test.samples}
+			if got := app.activityOverLocked(now, test.window); got != test.want {
+				t.Errorf("activityOverLocked = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package trackerapp
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/tuumbleweed/xerr"
+)
+
+// Ctrl+Shift+S opens the "as of" dialog, Ctrl+Z undoes the last action
+var (
+	asOfShortcut = &desktop.CustomShortcut{KeyName: fyne.KeyS, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}
+	undoShortcut = &fyne.ShortcutUndo{}
+)
+
+/*
+showAsOfDialog asks for a task and a past time, then starts (when stopped)
+or switches (when running) as of that time.
+*/
+func (t *TrackerApp) showAsOfDialog() {
+	t.showWindow() // dialogs need a window, and from the tray it's usually hidden
+
+	t.Mutex.Lock()
+	isRunning := t.IsRunning
+	currentTaskName := t.CurrentTaskName
+	t.Mutex.Unlock()
+
+	l := t.Locale
+	unassignedTaskOption := l.T("Unassigned")
+	options := []string{unassignedTaskOption}
+	for _, task := range t.Tasks {
+		options = append(options, task.Name)
+	}
+	taskSelect := widget.NewSelect(options, nil)
+	if currentTaskName != "" {
+		taskSelect.SetSelected(currentTaskName)
+	} else {
+		taskSelect.SetSelected(unassignedTaskOption)
+	}
+	whenEntry := widget.NewEntry()
+	whenEntry.SetPlaceHolder(l.T("AsOfPlaceholder"))
+
+	title, verb := l.T("AsOfStartTitle"), l.T("Start")
+	if isRunning {
+		title, verb = l.T("AsOfSwitchTitle"), l.T("AsOfSwitch")
+	}
+	items := []*widget.FormItem{
+		widget.NewFormItem(l.T("AsOfTask"), taskSelect),
+		widget.NewFormItem(l.T("AsOfSince"), whenEntry),
+	}
+	formDialog := dialog.NewForm(title, verb, l.T("Cancel"), items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		now := time.Now()
+		at, err := parseAsOf(whenEntry.Text, now)
+		if err != nil {
+			dialog.ShowError(err, t.Window)
+			return
+		}
+		taskName := taskSelect.Selected
+		if taskName == unassignedTaskOption {
+			taskName = ""
+		}
+
+		// the run may have been started or stopped from the tray while the dialog was open
+		t.Mutex.Lock()
+		isRunning := t.IsRunning
+		t.Mutex.Unlock()
+		go func() {
+			var usedAt time.Time
+			var e *xerr.Error
+			if isRunning {
+				usedAt, e = t.switchAsOf(taskName, at)
+			} else {
+				usedAt, e = t.startAsOf(taskName, at)
+			}
+			fyne.Do(func() {
+				if e != nil {
+					showError(e, t.Window)
+					return
+				}
+				if !usedAt.Equal(at) {
+					message := l.T("AsOfAdjusted", "Asked", l.Clock(at), "Used", l.Clock(usedAt))
+					dialog.ShowInformation(title, message, t.Window)
+				}
+			})
+		}()
+	}, t.Window)
+	formDialog.Resize(fyne.NewSize(520, 260))
+	formDialog.Show()
+}
+
+/*
+parseAsOf reads a past time: minutes ago ("15"), a duration ago ("20m", "1h30m")
+or a wall-clock time today ("09:30").
+*/
+func parseAsOf(text string, now time.Time) (at time.Time, err error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return at, errors.New("enter how long ago (15m) or a time (09:30)")
+	}
+
+	if minutes, err := strconv.Atoi(text); err == nil {
+		if minutes < 0 {
+			return at, fmt.Errorf("'%s' is in the future", text)
+		}
+		return now.Add(-time.Duration(minutes) * time.Minute), nil
+	}
+	if ago, err := time.ParseDuration(text); err == nil {
+		if ago < 0 {
+			return at, fmt.Errorf("'%s' is in the future", text)
+		}
+		return now.Add(-ago), nil
+	}
+	clock, err := time.ParseInLocation("15:04", text, now.Location())
+	if err != nil {
+		return at, fmt.Errorf("'%s' is neither a duration (15m) nor a time (09:30)", text)
+	}
+	year, month, day := now.Date()
+	at = time.Date(year, month, day, clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if at.After(now) {
+		return at, fmt.Errorf("%s is in the future", text)
+	}
+	return at, nil
+}
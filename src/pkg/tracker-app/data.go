@@ -1,13 +1,23 @@
 package trackerapp
 
-import (
-	"time"
+// what the day files hold, and how they are read and written, is in the store package
+
+// reasons offered when pausing
+var PauseReasons = []string{PauseReasonBreak, "lunch", "meeting", "interruption"}
+
+// pause reasons the tracker sets itself
+const (
+	PauseReasonAway  = "away"  // the lock action "pause", not offered in the menus
+	PauseReasonBreak = "break" // a pomodoro break, also offered in the menus
 )
 
-// this is what we save to the JSONL file
-type Chunk struct {
-	TaskName   string        `json:"task_name"`
-	StartedAt  time.Time     `json:"started_at"`
-	FinishedAt time.Time     `json:"finished_at"`
-	ActiveTime time.Duration `json:"active_time"`
-}
+// why a run started or stopped when it wasn't a button press; the session package adds lock, sleep and switch-user
+const (
+	StartReasonUnlock   = "unlock"   // resumed from the offer after unlocking
+	StartReasonSchedule = "schedule" // first activity in a working-hours window
+	StopReasonSchedule  = "schedule" // left running out of hours with nobody at the computer
+	StopReasonProfile   = "profile"  // stopped by switching to another profile
+	StopReasonFocus     = "focus"    // a timebox ran out (TimeboxActionStop)
+	StopReasonDeclined  = "declined" // on the away pause: resuming was offered on unlock and turned down
+	StopReasonUnlock    = "unlock"   // on the away pause: unlocked with Lock.OfferResume off
+)
@@ -0,0 +1,89 @@
+package trackerapp
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+/*
+DayBars is a small bar chart of time per day, oldest first, scaled to the
+longest day. Days without time get a thin stub so the row of days stays readable.
+*/
+type DayBars struct {
+	widget.BaseWidget
+
+	days []time.Duration
+}
+
+func NewDayBars() *DayBars {
+	d := &DayBars{}
+	d.ExtendBaseWidget(d)
+	return d
+}
+
+func (d *DayBars) SetDays(days []time.Duration) {
+	d.days = days
+	d.Refresh()
+}
+
+// --- widget.Renderer ---
+
+type dayBarsRenderer struct {
+	d       *DayBars
+	bars    []*canvas.Rectangle
+	objects []fyne.CanvasObject
+}
+
+func (d *DayBars) CreateRenderer() fyne.WidgetRenderer {
+	r := &dayBarsRenderer{d: d}
+	r.Refresh()
+	return r
+}
+
+func (r *dayBarsRenderer) Layout(sz fyne.Size) {
+	if len(r.bars) == 0 {
+		return
+	}
+	var longest time.Duration
+	for _, day := range r.d.days {
+		longest = max(longest, day)
+	}
+	gap := theme.Padding() / 2
+	barW := (sz.Width - gap*float32(len(r.bars)-1)) / float32(len(r.bars))
+	for i, bar := range r.bars {
+		barH := float32(1) // stub
+		if longest > 0 && r.d.days[i] > 0 {
+			barH = max(barH, sz.Height*float32(r.d.days[i])/float32(longest))
+		}
+		bar.Move(fyne.NewPos(float32(i)*(barW+gap), sz.Height-barH))
+		bar.Resize(fyne.NewSize(barW, barH))
+	}
+}
+
+func (r *dayBarsRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(140, theme.TextSize()*3)
+}
+
+func (r *dayBarsRenderer) Refresh() {
+	// one rectangle per day, rebuilt only when the number of days changes
+	if len(r.bars) != len(r.d.days) {
+		r.bars = make([]*canvas.Rectangle, len(r.d.days))
+		r.objects = make([]fyne.CanvasObject, len(r.d.days))
+		for i := range r.bars {
+			r.bars[i] = canvas.NewRectangle(theme.Color(theme.ColorNamePrimary))
+			r.objects[i] = r.bars[i]
+		}
+	}
+	for _, bar := range r.bars {
+		bar.FillColor = theme.Color(theme.ColorNamePrimary) // follow the theme and accent color
+	}
+	r.Layout(r.d.Size())
+	canvas.Refresh(r.d)
+}
+
+func (r *dayBarsRenderer) Destroy()                     {}
+func (r *dayBarsRenderer) Objects() []fyne.CanvasObject { return r.objects }
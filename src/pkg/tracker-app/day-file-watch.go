@@ -0,0 +1,125 @@
+package trackerapp
+
+import (
+	"maps"
+	"os"
+	"time"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/history"
+	"work-tracker/src/pkg/notify"
+)
+
+/*
+Today's file can be changed by other programs while the tracker runs: manual
+edits, import scripts, cmd/split, a second machine syncing the work dir. The
+scheduler polls its size and modification time, every write of the tracker
+itself is stamped right after it happens (all of them hold t.Mutex), so any
+other difference is someone else's change.
+
+On a change the totals and baselines are rebuilt from the file. The open chunk
+is left alone: the run goes on from ChunkStart, which is where the file ends
+as far as the tracker is concerned. When the change touches the running
+session (the file no longer holds exactly what this run flushed) the user is
+told, since that time is likely counted twice or was taken from the run.
+*/
+
+const (
+	dayFileCheckInterval = 5 * time.Second
+	notifyKeyDayFile     = "day-file"
+)
+
+// dayFileStamp is what tells one version of the day file from another, zero when it's missing
+type dayFileStamp struct {
+	Size    int64
+	ModTime time.Time
+}
+
+func statDayFile(filePath string) (stamp dayFileStamp) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return stamp
+	}
+	return dayFileStamp{Size: info.Size(), ModTime: info.ModTime()}
+}
+
+func (s dayFileStamp) same(other dayFileStamp) bool {
+	return s.Size == other.Size && s.ModTime.Equal(other.ModTime)
+}
+
+// stampDayFileLocked records the day file as the tracker left it. Caller holds t.Mutex and just wrote the file.
+func (t *TrackerApp) stampDayFileLocked() {
+	t.dayFileStamp = statDayFile(t.CurrentFilePath)
+}
+
+// checkDayFile reloads today's file if another program changed it. Runs on the scheduler goroutine.
+func (t *TrackerApp) checkDayFile(now time.Time) {
+	t.Mutex.Lock()
+	filePath := t.CurrentFilePath
+	stamp := statDayFile(filePath)
+	if stamp.same(t.dayFileStamp) {
+		t.Mutex.Unlock()
+		return
+	}
+	tl.Log(tl.Info, palette.Blue, "%s '%s', it was changed by another program", "Reloading", filePath)
+	t.dayFileStamp = stamp // a file that can't be read is retried on its next change, not every check
+	overlapping, e := t.reloadDayLocked(now)
+	taskName := t.CurrentTaskName
+	t.Mutex.Unlock()
+	if e != nil {
+		tl.Log(tl.Warning, palette.Yellow, "%s: %s", "Kept the totals from before the change", e.Msg)
+		return
+	}
+	tl.Log(tl.Info1, palette.Green, "%s '%s'", "Reloaded", filePath)
+
+	t.refreshUIState()
+	t.updateInterface()
+	t.updateTray()
+	if !overlapping {
+		return
+	}
+	tl.Log(tl.Warning, palette.Yellow, "%s '%s'", "The change overlaps the running session of", taskName)
+	if taskName == "" {
+		taskName = t.Locale.T("UnassignedTask")
+	}
+	t.showNotification(notify.Notification{
+		Key:   notifyKeyDayFile,
+		Title: t.Locale.T("NotifyDayFileTitle"),
+		Body:  t.Locale.T("NotifyDayFileBody", "Task", taskName),
+	}, now)
+}
+
+/*
+reloadDayLocked takes today's totals from the file again without flushing.
+overlapping reports whether the file's time inside the running session differs
+from what the run flushed. Caller holds t.Mutex.
+*/
+func (t *TrackerApp) reloadDayLocked(now time.Time) (overlapping bool, e *xerr.Error) {
+	chunks, e := t.readDayLocked()
+	if e != nil {
+		return false, e
+	}
+	workedToday, activeToday, timeByTask := sumChunks(chunks)
+
+	t.WorkedToday = workedToday
+	t.WorkedTodayBeforeStartingThisRun = workedToday
+	t.ActiveToday = activeToday
+	t.TimeByTask = timeByTask
+	t.TimeByTaskBeforeStartingThisRun = maps.Clone(timeByTask)
+	t.todayStats = dayStatsFromChunks(chunks)
+	t.suggestedTime = suggestedTimeFromChunks(chunks)
+	if !t.IsRunning {
+		return false, nil
+	}
+
+	// the file holds the run up to ChunkStart, the open chunk goes on from there
+	t.ActiveToday += t.ActiveDuringThisChunk
+	t.RunStart = t.ChunkStart
+	t.TaskRunStart = t.ChunkStart
+	flushed := t.ChunkStart.Sub(t.SessionStart)
+	inFile := history.TrackedBetween(chunks, t.SessionStart, now)
+	return (inFile - flushed).Abs() > sessionGap, nil
+}
@@ -0,0 +1,151 @@
+package trackerapp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"work-tracker/src/pkg/store"
+)
+
+func TestDayFileStamp(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "day.jsonl")
+	if stamp := statDayFile(filePath); stamp != (dayFileStamp{}) {
+		t.Fatalf("missing file stamped %+v, want zero", stamp)
+	}
+	err := os.WriteFile(filePath, []byte("{}\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	written := statDayFile(filePath)
+	modTime := written.ModTime
+
+	tests := []struct {
+		name   string
+		change func() error
+		same   bool
+	}{
+		{"untouched", func() error { return nil }, true},
+		{"touched", func() error { return os.Chtimes(filePath, modTime, modTime.Add(time.Second)) }, false},
+		{"same size, written again at the same time", func() error {
+			err := os.WriteFile(filePath, []byte("[]\n"), 0o644)
+			if err == nil {
+				err = os.Chtimes(filePath, modTime, modTime)
+			}
+			return err
+		}, true},
+		{"grown, the time put back", func() error {
+			err := os.WriteFile(filePath, []byte("{}\n{}\n"), 0o644)
+			if err == nil {
+				err = os.Chtimes(filePath, modTime, modTime)
+			}
+			return err
+		}, false},
+		{"removed", func() error { return os.Remove(filePath) }, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.change()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if same := statDayFile(filePath).same(written); same != test.same {
+				t.Errorf("same = %v, want %v", same, test.same)
+			}
+		})
+	}
+}
+
+func TestReloadDayLocked(t *testing.T) {
+	layout := store.MustParseLayout(store.DefaultLayout)
+	// the run started at 9:00 and flushed up to 9:30, the open chunk has 2m active
+	flushed := []store.Chunk{
+		workChunk("Code", at(9, 0), at(9, 15), 10*time.Minute),
+		workChunk("Code", at(9, 15), at(9, 30), 12*time.Minute),
+	}
+	tests := []struct {
+		name        string
+		running     bool
+		chunks      []store.Chunk // the file after the change
+		worked      time.Duration
+		active      time.Duration // ActiveToday, open chunk included while running
+		overlapping bool
+	}{
+		{"added before the session", true,
+			append([]store.Chunk{workChunk("Email", at(8, 0), at(8, 30), 20*time.Minute)}, flushed...),
+			time.Hour, 44 * time.Minute, false},
+		{"added inside the session", true,
+			append(append([]store.Chunk{}, flushed...), workChunk("Email", at(9, 30), at(9, 40), 5*time.Minute)),
+			40 * time.Minute, 29 * time.Minute, true},
+		{"the session's chunk removed", true,
+			flushed[:1],
+			15 * time.Minute, 12 * time.Minute, true},
+		{"the session's chunk reassigned", true,
+			[]store.Chunk{flushed[0], workChunk("Review", at(9, 15), at(9, 30), 12*time.Minute)},
+			30 * time.Minute, 24 * time.Minute, false},
+		{"off by less than a second", true,
+			[]store.Chunk{flushed[0], workChunk("Code", at(9, 15), at(9, 30).Add(-500*time.Millisecond), 12*time.Minute)},
+			30*time.Minute - 500*time.Millisecond, 24 * time.Minute, false},
+		{"paused inside the session", true,
+			[]store.Chunk{flushed[0], pauseChunk(at(9, 15), at(9, 30))},
+			15 * time.Minute, 12 * time.Minute, true},
+		{"stopped", false,
+			append(append([]store.Chunk{}, flushed...), workChunk("Email", at(9, 30), at(9, 40), 5*time.Minute)),
+			40 * time.Minute, 27 * time.Minute, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			workDir := t.TempDir()
+			writer := store.NewWriter(workDir, layout)
+			e := writer.RewriteDay(testDay, flushed)
+			if e != nil {
+				t.Fatalf("write: %s", e.Msg)
+			}
+			app := &TrackerApp{}
+			app.Mutex.Lock()
+			e = app.openDayLocked(workDir, layout, at(9, 30))
+			app.Mutex.Unlock()
+			if e != nil {
+				t.Fatalf("open: %s", e.Msg)
+			}
+			if test.running {
+				app.IsRunning, app.CurrentTaskName = true, "Code"
+				app.SessionStart, app.RunStart, app.TaskRunStart, app.ChunkStart = at(9, 0), at(9, 0), at(9, 0), at(9, 30)
+				app.ActiveDuringThisChunk = 2 * time.Minute
+			}
+
+			e = writer.RewriteDay(testDay, test.chunks) // by another program
+			if e != nil {
+				t.Fatalf("change: %s", e.Msg)
+			}
+			app.Mutex.Lock()
+			overlapping, e := app.reloadDayLocked(at(9, 45))
+			app.Mutex.Unlock()
+			if e != nil {
+				t.Fatalf("reload: %s", e.Msg)
+			}
+
+			if overlapping != test.overlapping {
+				t.Errorf("overlapping = %v, want %v", overlapping, test.overlapping)
+			}
+			if app.WorkedToday != test.worked || app.WorkedTodayBeforeStartingThisRun != test.worked {
+				t.Errorf("worked %s, %s before the run; want %s", app.WorkedToday, app.WorkedTodayBeforeStartingThisRun, test.worked)
+			}
+			if app.ActiveToday != test.active {
+				t.Errorf("active %s, want %s", app.ActiveToday, test.active)
+			}
+			if !test.running {
+				return
+			}
+			// the open chunk is kept: the run goes on from where the file ends for it
+			if !app.ChunkStart.Equal(at(9, 30)) || !app.RunStart.Equal(at(9, 30)) || !app.TaskRunStart.Equal(at(9, 30)) || app.ActiveDuringThisChunk != 2*time.Minute {
+				t.Errorf("chunk from %s (run %s, task run %s) with %s active; want all from 9:30 with 2m0s",
+					app.ChunkStart, app.RunStart, app.TaskRunStart, app.ActiveDuringThisChunk)
+			}
+			if !app.SessionStart.Equal(at(9, 0)) {
+				t.Errorf("session starts at %s, want 9:00", app.SessionStart)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package trackerapp
+
+import (
+	"maps"
+	"time"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/history"
+	"work-tracker/src/pkg/store"
+)
+
+// readDayLocked is the open day's chunks. Caller holds t.Mutex.
+func (t *TrackerApp) readDayLocked() (chunks []store.Chunk, e *xerr.Error) {
+	return store.NewReader(t.Workdir, t.Layout, store.Strict).ReadDay(t.CurrentDate)
+}
+
+/*
+rebaseLocked makes the day file the new baseline after it was rewritten.
+Caller holds t.Mutex and has flushed the open chunk.
+
+Totals come from the file, and the current run continues from now with nothing
+accumulated yet, so WorkedToday and TimeByTask stay consistent with the file.
+SessionStart is kept: it still marks where the run really began.
+*/
+func (t *TrackerApp) rebaseLocked(now time.Time) (e *xerr.Error) {
+	chunks, e := t.readDayLocked()
+	if e != nil {
+		return e
+	}
+	workedToday, activeToday, timeByTask := sumChunks(chunks)
+
+	t.WorkedToday = workedToday
+	t.WorkedTodayBeforeStartingThisRun = workedToday
+	t.ActiveToday = activeToday
+	t.TimeByTask = timeByTask
+	t.TimeByTaskBeforeStartingThisRun = maps.Clone(timeByTask)
+	t.todayStats = dayStatsFromChunks(chunks)
+	t.suggestedTime = suggestedTimeFromChunks(chunks)
+	t.ActiveDuringThisChunk = 0
+	if t.IsRunning {
+		t.RunStart = now
+		t.TaskRunStart = now
+		t.ChunkStart = now
+	}
+	return nil
+}
+
+// checkDayChange moves the tracker to the next day's file once midnight has passed
+func (t *TrackerApp) checkDayChange(now time.Time) {
+	t.Mutex.Lock()
+	rolled, e := t.rolloverDayLocked(now)
+	filePath := t.CurrentFilePath
+	t.Mutex.Unlock()
+	if e != nil {
+		tl.Log(tl.Warning, palette.Yellow, "%s: %s", "Couldn't open the new day", e.Msg)
+		return
+	}
+	if !rolled {
+		return
+	}
+	tl.Log(tl.Info1, palette.Green, "%s '%s'", "Opened the new day", filePath)
+	t.refreshUIState()
+	t.updateInterface()
+	t.updateTray()
+}
+
+/*
+rolloverDayLocked opens the day of now when it isn't the open day any more.
+Caller holds t.Mutex.
+
+The open chunk is cut at midnight, so what was tracked before it stays on the
+previous day, and the run goes on from midnight with the new day's totals.
+SessionStart is kept, as in rebaseLocked.
+*/
+func (t *TrackerApp) rolloverDayLocked(now time.Time) (rolled bool, e *xerr.Error) {
+	midnight := startOfDay(now)
+	if midnight.Equal(t.CurrentDate) {
+		return false, nil
+	}
+	t.flushChunkLocked(midnight)
+	e = t.openDayLocked(t.Workdir, t.Layout, now)
+	if e != nil {
+		return false, e
+	}
+	if t.IsRunning {
+		t.RunStart = t.ChunkStart
+		t.TaskRunStart = t.ChunkStart
+	}
+	return true, nil
+}
+
+/*
+reassignChunksSince gives everything tracked after from to taskName.
+A chunk that spans from is split in two, active time is shared in proportion.
+Pauses are left alone.
+*/
+func reassignChunksSince(chunks []store.Chunk, from time.Time, taskName string) (result []store.Chunk) {
+	for _, chunk := range chunks {
+		switch {
+		case chunk.Kind != store.ChunkKindWork, !chunk.FinishedAt.After(from):
+			result = append(result, chunk)
+		case !chunk.StartedAt.Before(from):
+			chunk.TaskName = taskName
+			result = append(result, chunk)
+		default:
+			before, after := history.SplitChunk(chunk, from)
+			after.TaskName = taskName
+			result = append(result, before, after)
+		}
+	}
+	return result
+}
+
+// dropChunksSince removes every chunk that started at or after from and cuts the one spanning it.
+func dropChunksSince(chunks []store.Chunk, from time.Time) (result []store.Chunk) {
+	for _, chunk := range chunks {
+		switch {
+		case !chunk.FinishedAt.After(from):
+			result = append(result, chunk)
+		case chunk.StartedAt.Before(from):
+			before, _ := history.SplitChunk(chunk, from)
+			result = append(result, before)
+		}
+	}
+	return result
+}
+
+// lastChunkEnd is the latest FinishedAt in chunks (zero when there are none)
+func lastChunkEnd(chunks []store.Chunk) (end time.Time) {
+	for _, chunk := range chunks {
+		if chunk.FinishedAt.After(end) {
+			end = chunk.FinishedAt
+		}
+	}
+	return end
+}
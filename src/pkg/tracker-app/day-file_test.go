@@ -0,0 +1,285 @@
+package trackerapp
+
+import (
+	"testing"
+	"time"
+
+	"work-tracker/src/pkg/store"
+)
+
+var testDay = time.Date(2026, 1, 23, 0, 0, 0, 0, time.UTC)
+
+// at is a time on testDay
+func at(hour, minute int) time.Time {
+	return testDay.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func workChunk(taskName string, from, to time.Time, active time.Duration) store.Chunk {
+	return store.Chunk{Kind: store.ChunkKindWork, TaskName: taskName, StartedAt: from, FinishedAt: to, ActiveTime: active}
+}
+
+func pauseChunk(from, to time.Time) store.Chunk {
+	return store.Chunk{Kind: store.ChunkKindPause, StartedAt: from, FinishedAt: to}
+}
+
+// chunkSpan is what the tests compare a chunk by
+type chunkSpan struct {
+	Kind       string
+	TaskName   string
+	From, To   time.Time
+	ActiveTime time.Duration
+}
+
+func spans(chunks []store.Chunk) (result []chunkSpan) {
+	for _, chunk := range chunks {
+		result = append(result, chunkSpan{chunk.Kind, chunk.TaskName, chunk.StartedAt, chunk.FinishedAt, chunk.ActiveTime})
+	}
+	return result
+}
+
+func sameSpans(t *testing.T, got, want []store.Chunk) {
+	t.Helper()
+	gotSpans, wantSpans := spans(got), spans(want)
+	if len(gotSpans) != len(wantSpans) {
+		t.Fatalf("got %v chunks, want %v:\n got  %+v\n want %+v", len(gotSpans), len(wantSpans), gotSpans, wantSpans)
+	}
+	for i := range gotSpans {
+		if gotSpans[i] != wantSpans[i] {
+			t.Errorf("chunk %v:\n got  %+v\n want %+v", i, gotSpans[i], wantSpans[i])
+		}
+	}
+}
+
+func TestReassignChunksSince(t *testing.T) {
+	chunks := []store.Chunk{
+		workChunk("Email", at(9, 0), at(9, 10), 10*time.Minute),
+		pauseChunk(at(9, 10), at(9, 20)),
+		workChunk("Email", at(9, 20), at(9, 30), 5*time.Minute),
+		workChunk("Review", at(9, 30), at(9, 40), 10*time.Minute),
+	}
+	tests := []struct {
+		name string
+		from time.Time
+		want []store.Chunk
+	}{
+		{"spanning the cut", at(9, 24), []store.Chunk{
+			chunks[0], chunks[1],
+			workChunk("Email", at(9, 20), at(9, 24), 2*time.Minute),
+			workChunk("Code", at(9, 24), at(9, 30), 3*time.Minute),
+			workChunk("Code", at(9, 30), at(9, 40), 10*time.Minute),
+		}},
+		{"on a chunk boundary", at(9, 30), []store.Chunk{
+			chunks[0], chunks[1], chunks[2],
+			workChunk("Code", at(9, 30), at(9, 40), 10*time.Minute),
+		}},
+		{"pauses left alone", at(9, 5), []store.Chunk{
+			workChunk("Email", at(9, 0), at(9, 5), 5*time.Minute),
+			workChunk("Code", at(9, 5), at(9, 10), 5*time.Minute),
+			chunks[1],
+			workChunk("Code", at(9, 20), at(9, 30), 5*time.Minute),
+			workChunk("Code", at(9, 30), at(9, 40), 10*time.Minute),
+		}},
+		{"after everything", at(10, 0), chunks},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sameSpans(t, reassignChunksSince(chunks, test.from, "Code"), test.want)
+		})
+	}
+}
+
+func TestDropChunksSince(t *testing.T) {
+	chunks := []store.Chunk{
+		workChunk("Email", at(9, 0), at(9, 10), 10*time.Minute),
+		pauseChunk(at(9, 10), at(9, 20)),
+		workChunk("Email", at(9, 20), at(9, 30), 6*time.Minute),
+	}
+	tests := []struct {
+		name string
+		from time.Time
+		want []store.Chunk
+	}{
+		{"spanning the cut", at(9, 25), []store.Chunk{chunks[0], chunks[1], workChunk("Email", at(9, 20), at(9, 25), 3*time.Minute)}},
+		{"on a chunk boundary", at(9, 20), chunks[:2]},
+		{"inside a pause", at(9, 15), []store.Chunk{chunks[0], pauseChunk(at(9, 10), at(9, 15))}},
+		{"before everything", at(8, 0), nil},
+		{"after everything", at(10, 0), chunks},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sameSpans(t, dropChunksSince(chunks, test.from), test.want)
+		})
+	}
+}
+
+func TestParseAsOf(t *testing.T) {
+	now := at(10, 30)
+	tests := []struct {
+		text    string
+		want    time.Time
+		wantErr bool
+	}{
+		{"15", at(10, 15), false},
+		{" 15m ", at(10, 15), false},
+		{"1h30m", at(9, 0), false},
+		{"0", now, false},
+		{"09:30", at(9, 30), false},
+		{"9:30", at(9, 30), false},
+		{"10:30", now, false},
+		{"10:31", time.Time{}, true},
+		{"-5", time.Time{}, true},
+		{"-5m", time.Time{}, true},
+		{"", time.Time{}, true},
+		{"soon", time.Time{}, true},
+		{"25:00", time.Time{}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			got, err := parseAsOf(test.text, now)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("parseAsOf(%q) = %s, want an error", test.text, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseAsOf(%q): %s", test.text, err)
+			}
+			if !got.Equal(test.want) {
+				t.Errorf("parseAsOf(%q) = %s, want %s", test.text, got, test.want)
+			}
+		})
+	}
+}
+
+func TestDiscardRun(t *testing.T) {
+	layout := store.MustParseLayout(store.DefaultLayout)
+	workDir := t.TempDir()
+	before := []store.Chunk{
+		workChunk("Email", at(8, 0), at(9, 0), 40*time.Minute),
+		pauseChunk(at(9, 0), at(9, 30)),
+	}
+	// the run started at 9:30 and has flushed once
+	e := store.NewWriter(workDir, layout).RewriteDay(testDay, append(before, workChunk("Code", at(9, 30), at(9, 40), 8*time.Minute)))
+	if e != nil {
+		t.Fatalf("write: %s", e.Msg)
+	}
+
+	app := &TrackerApp{}
+	app.Mutex.Lock()
+	e = app.openDayLocked(workDir, layout, at(9, 45))
+	app.Mutex.Unlock()
+	if e != nil {
+		t.Fatalf("open: %s", e.Msg)
+	}
+	app.IsRunning, app.CurrentTaskName, app.SuggestedTask = true, "Code", "Review"
+	app.SessionStart, app.RunStart, app.TaskRunStart, app.ChunkStart, app.noteBlockStart = at(9, 30), at(9, 30), at(9, 30), at(9, 40), at(9, 30)
+	app.StartReason, app.StopReason = "resumed", "idle"
+	app.focus = &focusSession{Mode: store.FocusModePomodoro, TaskName: "Code", Phase: focusPhaseWork, Planned: 25 * time.Minute, PhaseStart: at(9, 30)}
+
+	// what discardRun does short of the UI
+	app.endFocus()
+	app.Mutex.Lock()
+	e = app.discardRunLocked(at(9, 45))
+	app.Mutex.Unlock()
+	if e != nil {
+		t.Fatalf("discard: %s", e.Msg)
+	}
+
+	// the flushed chunk and the focus record are gone, the open chunk was never written
+	got, e := store.NewReader(workDir, layout, store.Strict).ReadDay(testDay)
+	if e != nil {
+		t.Fatalf("read: %s", e.Msg)
+	}
+	sameSpans(t, got, before)
+
+	if app.IsRunning || app.CurrentTaskName != "" || app.SuggestedTask != "" {
+		t.Errorf("running %v on %q, suggested %q; want stopped with nothing set", app.IsRunning, app.CurrentTaskName, app.SuggestedTask)
+	}
+	if !app.noteBlockStart.IsZero() || app.StartReason != "" || app.StopReason != "" {
+		t.Errorf("note block %s, reasons %q/%q; want them cleared", app.noteBlockStart, app.StartReason, app.StopReason)
+	}
+	if app.focus != nil {
+		t.Errorf("focus session %+v left open", app.focus)
+	}
+	if app.WorkedToday != time.Hour || app.TimeByTask["Code"] != 0 {
+		t.Errorf("worked %s, %s on Code; want 1h0m0s and nothing", app.WorkedToday, app.TimeByTask["Code"])
+	}
+}
+
+func TestRolloverDayLocked(t *testing.T) {
+	layout := store.MustParseLayout("{{.Year}}/{{.Year}}-{{.Month02}}.jsonl")
+	workDir := t.TempDir()
+	jan31, feb1 := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	clock := func(day time.Time, hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	// the run started at 22:00 and has flushed once
+	e := store.NewWriter(workDir, layout).RewriteDay(jan31, []store.Chunk{workChunk("Code", clock(jan31, 22, 0), clock(jan31, 23, 30), time.Hour)})
+	if e != nil {
+		t.Fatalf("write: %s", e.Msg)
+	}
+
+	app := &TrackerApp{}
+	app.Mutex.Lock()
+	defer app.Mutex.Unlock()
+	e = app.openDayLocked(workDir, layout, clock(jan31, 23, 40))
+	if e != nil {
+		t.Fatalf("open: %s", e.Msg)
+	}
+	app.IsRunning, app.CurrentTaskName = true, "Code"
+	app.SessionStart, app.RunStart, app.TaskRunStart, app.ChunkStart = clock(jan31, 22, 0), clock(jan31, 22, 0), clock(jan31, 22, 0), clock(jan31, 23, 30)
+	app.ActiveDuringThisChunk = 20 * time.Minute
+
+	rolled, e := app.rolloverDayLocked(clock(jan31, 23, 50))
+	if e != nil || rolled {
+		t.Fatalf("rolled %v, %v before midnight; want nothing", rolled, e)
+	}
+	rolled, e = app.rolloverDayLocked(clock(feb1, 0, 30))
+	if e != nil {
+		t.Fatalf("rollover: %s", e.Msg)
+	}
+	if !rolled || !app.CurrentDate.Equal(feb1) {
+		t.Fatalf("rolled %v, open day %s; want the 1st", rolled, app.CurrentDate)
+	}
+	if app.WorkedTodayBeforeStartingThisRun != 0 || !app.RunStart.Equal(feb1) || !app.TaskRunStart.Equal(feb1) || !app.ChunkStart.Equal(feb1) {
+		t.Errorf("worked %s before a run from %s, task from %s, chunk from %s; want nothing before a run from midnight",
+			app.WorkedTodayBeforeStartingThisRun, app.RunStart, app.TaskRunStart, app.ChunkStart)
+	}
+	if !app.SessionStart.Equal(clock(jan31, 22, 0)) {
+		t.Errorf("session start %s, want it kept", app.SessionStart)
+	}
+
+	// a rewrite of the session after midnight only touches the new day
+	e = app.rewriteDayLocked(clock(feb1, 0, 45), func(chunks []store.Chunk) []store.Chunk {
+		return reassignChunksSince(chunks, app.SessionStart, "Review")
+	})
+	if e != nil {
+		t.Fatalf("rewrite: %s", e.Msg)
+	}
+	if app.WorkedToday != 45*time.Minute {
+		t.Errorf("worked %s on the 1st, want 45m0s", app.WorkedToday)
+	}
+
+	reader := store.NewReader(workDir, layout, store.Strict)
+	tests := []struct {
+		name string
+		day  time.Time
+		want []store.Chunk
+	}{
+		{"the 31st", jan31, []store.Chunk{
+			workChunk("Code", clock(jan31, 22, 0), clock(jan31, 23, 30), time.Hour),
+			workChunk("Code", clock(jan31, 23, 30), feb1, 20*time.Minute),
+		}},
+		{"the 1st", feb1, []store.Chunk{workChunk("Review", feb1, clock(feb1, 0, 45), 0)}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, e := reader.ReadDay(test.day)
+			if e != nil {
+				t.Fatalf("read: %s", e.Msg)
+			}
+			sameSpans(t, got, test.want)
+		})
+	}
+}
@@ -0,0 +1,424 @@
+package trackerapp
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+
+	"work-tracker/src/pkg/notify"
+	"work-tracker/src/pkg/settings"
+	"work-tracker/src/pkg/store"
+)
+
+/*
+Focus sessions put a countdown on a task (Settings.Focus):
+
+  - pomodoro: work intervals separated by breaks, every LongBreakEvery-th break is a long one
+  - timebox:  one work interval, then stop, switch or keep going (TimeboxAction)
+
+The countdown only runs while the focus task is tracked. Pausing or switching
+away holds it and counts an interruption, coming back picks it up again.
+Stopping ends the session. Pomodoro breaks are ordinary "break" pauses.
+
+Every work interval that ends is written as a focus chunk (planned length,
+completed or not, interruptions). Its time is already in the work chunks,
+so nothing adds it to worked time.
+*/
+
+const (
+	focusPhaseWork  = "work"
+	focusPhaseBreak = "break"
+	focusPhaseReady = "ready" // break is over, waiting for Resume
+)
+
+const notifyKeyFocus = "focus"
+
+// focusSession is the session in progress, guarded by t.Mutex
+type focusSession struct {
+	Mode          string        // FocusModePomodoro or FocusModeTimebox
+	TaskName      string        // task the session is on, "" is unassigned
+	Phase         string        // focusPhaseWork, focusPhaseBreak or focusPhaseReady
+	Planned       time.Duration // length of the current work interval
+	PhaseStart    time.Time     // when the current work interval first ran on the task
+	Deadline      time.Time     // when the current phase runs out, zero while on hold
+	Remaining     time.Duration // what's left of the work interval while on hold
+	Interruptions int           // during the current work interval
+	Completed     int           // pomodoros completed in this session
+}
+
+/*
+startFocus begins a session of length on the running task. When paused it resumes
+the paused task, when stopped it starts unassigned (like the Start button).
+A session already in progress is ended first.
+*/
+func (t *TrackerApp) startFocus(mode string, length time.Duration) {
+	t.endFocus()
+
+	t.Mutex.Lock()
+	isRunning := t.IsRunning
+	isPaused := t.IsPaused
+	taskName := t.CurrentTaskName
+	if isPaused {
+		taskName = t.PausedTaskName
+	}
+	t.focus = &focusSession{Mode: mode, TaskName: taskName, Phase: focusPhaseWork, Planned: length, Remaining: length}
+	t.Mutex.Unlock()
+
+	tl.Log(tl.Info, palette.Cyan, "%s. Mode: '%s', task name: '%s', length: %s", "Starting focus session", mode, taskName, length)
+	switch {
+	case isRunning:
+		t.afterTrackingChanged("") // starts the countdown
+	case isPaused:
+		t.resumeTracking()
+	default:
+		t.startTask(taskName)
+	}
+}
+
+// endFocus ends the session, recording a work interval in progress as not completed. No-op without a session.
+func (t *TrackerApp) endFocus() {
+	now := time.Now()
+	t.Mutex.Lock()
+	focus := t.focus
+	t.focus = nil
+	if focus != nil && focus.Phase == focusPhaseWork && !focus.PhaseStart.IsZero() {
+		t.recordFocusLocked(focus, now, false)
+	}
+	t.Mutex.Unlock()
+	if focus == nil {
+		return
+	}
+	tl.Log(tl.Info, palette.Cyan, "%s. Task name: '%s', pomodoros completed: %v", "Ended focus session", focus.TaskName, focus.Completed)
+}
+
+// endFocusFromUI is "End focus" in the focus menus
+func (t *TrackerApp) endFocusFromUI() {
+	t.endFocus()
+	t.afterTrackingChanged("")
+}
+
+/*
+focusTrackingChanged follows a start, stop, switch, pause or resume: it holds or picks up
+the countdown, starts the next pomodoro when its task runs again after a break,
+and ends the session once nothing is tracked (or another task runs after a break).
+*/
+func (t *TrackerApp) focusTrackingChanged() {
+	now := time.Now()
+	t.Mutex.Lock()
+	focus := t.focus
+	if focus == nil {
+		t.Mutex.Unlock()
+		return
+	}
+	onTask := t.IsRunning && t.CurrentTaskName == focus.TaskName
+	tracking := t.IsRunning || t.IsPaused
+	ended := !tracking
+
+	switch focus.Phase {
+	case focusPhaseWork:
+		switch {
+		case onTask && focus.Deadline.IsZero(): // started, or back on the task
+			if focus.PhaseStart.IsZero() {
+				focus.PhaseStart = now
+			}
+			focus.Deadline = now.Add(focus.Remaining)
+		case !onTask && !focus.Deadline.IsZero(): // paused, switched away or stopped
+			focus.Remaining = max(focus.Deadline.Sub(now), 0)
+			focus.Deadline = time.Time{}
+			if tracking {
+				focus.Interruptions++
+			}
+		}
+	case focusPhaseBreak, focusPhaseReady:
+		switch {
+		case onTask: // resumed, the next pomodoro starts now
+			focus.Phase = focusPhaseWork
+			focus.Planned = t.Settings.Focus.Work.Duration
+			focus.PhaseStart = now
+			focus.Deadline = now.Add(focus.Planned)
+			focus.Interruptions = 0
+		case t.IsRunning: // went on with another task instead
+			ended = true
+		}
+	}
+	t.Mutex.Unlock()
+
+	if ended {
+		t.endFocus()
+	}
+}
+
+// what checkFocus does once a phase is over, see focusNext
+const (
+	focusNextStop     = "stop"     // a timebox is over, TimeboxActionStop
+	focusNextSwitch   = "switch"   // a timebox is over, TimeboxActionSwitch
+	focusNextContinue = "continue" // a timebox is over, TimeboxActionContinue
+	focusNextBreak    = "break"    // a pomodoro is over, pause for the break
+	focusNextResume   = "resume"   // a break is over, AutoResume
+	focusNextWait     = "wait"     // a break is over, wait for Resume
+)
+
+/*
+checkFocus ends the current phase once its time is up. Runs on the scheduler goroutine,
+which wakes up for focusDeadline.
+
+A finished work interval is recorded as completed. A pomodoro then pauses for its break,
+a timebox stops, switches or keeps going. When a break is over the task is resumed
+(AutoResume) or the session waits for Resume.
+*/
+func (t *TrackerApp) checkFocus(now time.Time) {
+	t.Mutex.Lock()
+	config := t.Settings.Focus
+	ended, over := t.advanceFocusLocked(now, config)
+	var completed int
+	if t.focus != nil {
+		completed = t.focus.Completed
+	}
+	t.Mutex.Unlock()
+	if !over {
+		return
+	}
+
+	l := t.Locale
+	taskName := ended.TaskName
+	if taskName == "" {
+		taskName = l.T("UnassignedTask")
+	}
+	length := l.DurationMinutes(ended.Planned)
+	breakLength := config.BreakAfter(completed)
+	notification := notify.Notification{Key: notifyKeyFocus}
+	switch next := focusNext(ended, config); next {
+	case focusNextStop, focusNextSwitch, focusNextContinue:
+		tl.Log(tl.Notice, palette.Cyan, "%s. Task name: '%s', action: '%s'", "Timebox is over", ended.TaskName, next)
+		notification.Title = l.T("NotifyTimeboxDoneTitle")
+		switch next {
+		case focusNextStop:
+			notification.Body = l.T("NotifyTimeboxDoneStopped", "Duration", length, "Task", taskName)
+			t.stopTracking()
+		case focusNextSwitch:
+			switchTo := config.SwitchTo
+			if switchTo == "" {
+				switchTo = l.T("UnassignedTask")
+			}
+			notification.Body = l.T("NotifyTimeboxDoneSwitched", "Duration", length, "Task", taskName, "Next", switchTo)
+			t.switchTask(config.SwitchTo)
+		default:
+			notification.Body = l.T("NotifyTimeboxDoneContinue", "Duration", length, "Task", taskName)
+			t.afterTrackingChanged("")
+		}
+	case focusNextBreak:
+		tl.Log(tl.Notice, palette.Cyan, "%s. Task name: '%s', completed: %v, break: %s", "Pomodoro is over", ended.TaskName, completed, breakLength)
+		notification.Title = l.T("NotifyPomodoroDoneTitle")
+		notification.Body = l.T("NotifyPomodoroDoneBody", "Count", fmt.Sprint(completed), "Task", taskName, "Duration", l.DurationMinutes(breakLength))
+		t.pauseTracking(PauseReasonBreak)
+	default:
+		tl.Log(tl.Notice, palette.Cyan, "%s. Task name: '%s', auto resume: %v", "Break is over", ended.TaskName, config.AutoResume)
+		notification.Title = l.T("NotifyBreakOverTitle")
+		if next == focusNextResume {
+			notification.Body = l.T("NotifyBreakOverResumed", "Task", taskName, "Number", fmt.Sprint(completed+1))
+			t.resumeTracking()
+		} else {
+			notification.Body = l.T("NotifyBreakOverWaiting", "Task", taskName)
+			t.afterTrackingChanged("")
+		}
+	}
+	t.showNotification(notification, now)
+}
+
+/*
+advanceFocusLocked moves the session past a phase whose time is up at now and returns
+the session as it was when it ran out. over is false when nothing ran out.
+A finished work interval is recorded; a timebox ends (with StopReasonFocus for
+TimeboxActionStop), a pomodoro goes on its break. A finished break waits for Resume.
+Caller holds t.Mutex.
+*/
+func (t *TrackerApp) advanceFocusLocked(now time.Time, config settings.FocusSettings) (ended focusSession, over bool) {
+	focus := t.focus
+	if focus == nil || focus.Deadline.IsZero() || now.Before(focus.Deadline) {
+		return ended, false
+	}
+	ended = *focus
+	switch focus.Phase {
+	case focusPhaseWork:
+		t.recordFocusLocked(focus, now, true)
+		if focus.Mode == store.FocusModeTimebox {
+			t.focus = nil
+			if config.TimeboxAction == settings.TimeboxActionStop {
+				t.StopReason = StopReasonFocus // picked up by the final flush
+			}
+			break
+		}
+		focus.Completed++
+		focus.Phase = focusPhaseBreak
+		focus.PhaseStart = time.Time{}
+		focus.Deadline = now.Add(config.BreakAfter(focus.Completed))
+	case focusPhaseBreak:
+		focus.Phase = focusPhaseReady
+		focus.Deadline = time.Time{}
+	}
+	return ended, true
+}
+
+// focusNext is what follows the end of the phase ended was in
+func focusNext(ended focusSession, config settings.FocusSettings) string {
+	switch {
+	case ended.Phase == focusPhaseWork && ended.Mode == store.FocusModeTimebox:
+		switch config.TimeboxAction {
+		case settings.TimeboxActionStop:
+			return focusNextStop
+		case settings.TimeboxActionSwitch:
+			return focusNextSwitch
+		}
+		return focusNextContinue
+	case ended.Phase == focusPhaseWork:
+		return focusNextBreak
+	case config.AutoResume:
+		return focusNextResume
+	}
+	return focusNextWait
+}
+
+// focusDeadline is when the current phase runs out, zero without a session or while on hold
+func (t *TrackerApp) focusDeadline() time.Time {
+	t.Mutex.Lock()
+	defer t.Mutex.Unlock()
+	if t.focus == nil {
+		return time.Time{}
+	}
+	return t.focus.Deadline
+}
+
+/*
+recordFocusLocked appends the work interval of focus, from its first second on the task to at,
+as a focus chunk. A failed write is logged, like a pause. Caller holds t.Mutex.
+*/
+func (t *TrackerApp) recordFocusLocked(focus *focusSession, at time.Time, completed bool) {
+	chunk := store.Chunk{
+		TaskName:   focus.TaskName,
+		StartedAt:  focus.PhaseStart.Round(0),
+		FinishedAt: at.Round(0),
+		Kind:       store.ChunkKindFocus,
+		Focus: &store.FocusRecord{
+			Mode:          focus.Mode,
+			Planned:       focus.Planned,
+			Completed:     completed,
+			Interruptions: focus.Interruptions,
+		},
+	}
+	e := store.AppendFile(t.CurrentFilePath, chunk)
+	if e != nil {
+		tl.Log(tl.Error, palette.Red, "Failed to write %s record: %s", focus.Mode, e.Msg)
+		return
+	}
+	t.stampDayFileLocked()
+	tl.Log(tl.Detailed1, palette.Green, "%s %s of %s. Completed: %v, interruptions: %v", "Recorded", focus.Mode, focus.Planned, completed, focus.Interruptions)
+}
+
+/*
+focusStatus is the countdown shown under the clock (format gives the time left)
+and whether the work interval is counting down. Empty without a session.
+*/
+func (t *TrackerApp) focusStatus(now time.Time, format func(time.Duration) string) (text string, counting bool) {
+	t.Mutex.Lock()
+	focus := t.focus
+	if focus == nil {
+		t.Mutex.Unlock()
+		return "", false
+	}
+	session := *focus
+	t.Mutex.Unlock()
+
+	l := t.Locale
+	remaining := session.Remaining
+	if !session.Deadline.IsZero() {
+		remaining = max(session.Deadline.Sub(now), 0)
+	}
+	number := fmt.Sprint(session.Completed + 1)
+	switch session.Phase {
+	case focusPhaseBreak:
+		return l.T("FocusBreak", "Remaining", format(remaining)), false
+	case focusPhaseReady:
+		return l.T("FocusReady", "Number", number), false
+	}
+	if session.Mode == store.FocusModeTimebox {
+		text = l.T("FocusTimebox", "Remaining", format(remaining))
+	} else {
+		text = l.T("FocusPomodoro", "Number", number, "Remaining", format(remaining))
+	}
+	if session.Deadline.IsZero() {
+		return l.T("FocusOnHold", "Focus", text), false
+	}
+	return text, true
+}
+
+// formatCountdown is mm:ss, or h:mm:ss from an hour up
+func formatCountdown(d time.Duration) string {
+	d = d.Round(time.Second)
+	h, m, s := int(d/time.Hour), int(d%time.Hour/time.Minute), int(d%time.Minute/time.Second)
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
+
+// focusItems is the focus menu shared by the focus button and the tray: pomodoro, timeboxes, custom, end
+func (t *TrackerApp) focusItems(inSession bool) (items []*fyne.MenuItem) {
+	l := t.Locale
+	config := t.settingsSnapshot().Focus
+	items = append(items, fyne.NewMenuItem(l.T("FocusPomodoroItem", "Duration", l.DurationMinutes(config.Work.Duration)), func() {
+		t.startFocus(store.FocusModePomodoro, config.Work.Duration)
+	}))
+	for _, timebox := range config.Timeboxes {
+		items = append(items, fyne.NewMenuItem(l.T("FocusTimeboxItem", "Duration", l.DurationMinutes(timebox.Duration)), func() {
+			t.startFocus(store.FocusModeTimebox, timebox.Duration)
+		}))
+	}
+	items = append(items, fyne.NewMenuItem(l.T("FocusTimeboxCustom"), t.showTimeboxDialog))
+	if inSession {
+		items = append(items, fyne.NewMenuItemSeparator(), fyne.NewMenuItem(l.T("FocusEnd"), t.endFocusFromUI))
+	}
+	return items
+}
+
+// onFocusButtonTapped offers the focus menu below the button
+func (t *TrackerApp) onFocusButtonTapped() {
+	t.Mutex.Lock()
+	inSession := t.focus != nil
+	t.Mutex.Unlock()
+
+	focusMenu := fyne.NewMenu("", t.focusItems(inSession)...)
+	below := fyne.NewPos(0, t.FocusButton.Size().Height)
+	widget.ShowPopUpMenuAtRelativePosition(focusMenu, t.Window.Canvas(), below, t.FocusButton)
+}
+
+// showTimeboxDialog asks for a timebox length ("45m", "1h30m") and starts it
+func (t *TrackerApp) showTimeboxDialog() {
+	t.showWindow() // dialogs need a window, and from the tray it's usually hidden
+
+	lengthEntry := widget.NewEntry()
+	lengthEntry.SetPlaceHolder("45m")
+	l := t.Locale
+	items := []*widget.FormItem{
+		widget.NewFormItem(l.T("TimeboxLength"), lengthEntry),
+	}
+	formDialog := dialog.NewForm(l.T("TimeboxTitle"), l.T("Start"), l.T("Cancel"), items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		length, err := time.ParseDuration(lengthEntry.Text)
+		if err != nil || length < time.Minute || length > 12*time.Hour {
+			dialog.ShowError(errors.New(l.T("TimeboxInvalid", "Text", lengthEntry.Text)), t.Window)
+			return
+		}
+		go t.startFocus(store.FocusModeTimebox, length)
+	}, t.Window)
+	formDialog.Show()
+	t.Window.Canvas().Focus(lengthEntry)
+}
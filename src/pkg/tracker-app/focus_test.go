@@ -0,0 +1,227 @@
+package trackerapp
+
+import (
+	"testing"
+	"time"
+
+	"work-tracker/src/pkg/settings"
+	"work-tracker/src/pkg/store"
+)
+
+// newFocusTestApp is a tracker on today's file in a temp dir, so recorded focus chunks can be read back
+func newFocusTestApp(t *testing.T) *TrackerApp {
+	t.Helper()
+	app := &TrackerApp{}
+	app.Settings.Focus = settings.FocusSettings{
+		Work:           minutes(25),
+		ShortBreak:     minutes(5),
+		LongBreak:      minutes(15),
+		LongBreakEvery: 4,
+	}
+	app.Mutex.Lock()
+	e := app.openDayLocked(t.TempDir(), store.MustParseLayout(store.DefaultLayout), time.Now())
+	app.Mutex.Unlock()
+	if e != nil {
+		t.Fatalf("open: %s", e.Msg)
+	}
+	return app
+}
+
+func focusRecords(t *testing.T, app *TrackerApp) (records []store.FocusRecord) {
+	t.Helper()
+	chunks, e := store.ReadFile(app.CurrentFilePath, store.Strict)
+	if e != nil {
+		t.Fatalf("read: %s", e.Msg)
+	}
+	for _, chunk := range chunks {
+		if chunk.Kind == store.ChunkKindFocus {
+			records = append(records, *chunk.Focus)
+		}
+	}
+	return records
+}
+
+func TestFocusTrackingChanged(t *testing.T) {
+	const remaining = 10 * time.Minute
+	type state struct {
+		running, paused bool
+		taskName        string
+	}
+	var (
+		onTask    = state{running: true, taskName: "Code"}
+		otherTask = state{running: true, taskName: "Email"}
+		paused    = state{paused: true}
+		stopped   = state{}
+	)
+	tests := []struct {
+		name          string
+		phase         string
+		counting      bool // the countdown runs before the change
+		after         state
+		wantCounting  bool
+		interruptions int
+		ended         bool
+	}{
+		{"started on the task", focusPhaseWork, false, onTask, true, 0, false},
+		{"held by a pause", focusPhaseWork, true, paused, false, 1, false},
+		{"held by a switch", focusPhaseWork, true, otherTask, false, 1, false},
+		{"still away", focusPhaseWork, false, otherTask, false, 0, false},
+		{"back on the task", focusPhaseWork, false, onTask, true, 0, false},
+		{"stopped", focusPhaseWork, true, stopped, false, 0, true},
+		{"resumed after a break", focusPhaseBreak, false, onTask, true, 0, false},
+		{"resumed when ready", focusPhaseReady, false, onTask, true, 0, false},
+		{"on the break pause", focusPhaseBreak, true, paused, true, 0, false},
+		{"another task after a break", focusPhaseReady, false, otherTask, false, 0, true},
+		{"stopped on a break", focusPhaseBreak, true, stopped, false, 0, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := newFocusTestApp(t)
+			focus := &focusSession{Mode: store.FocusModePomodoro, TaskName: "Code", Phase: test.phase, Planned: 25 * time.Minute, Remaining: remaining, Completed: 1}
+			if test.phase == focusPhaseWork {
+				focus.PhaseStart = time.Now().Add(-15 * time.Minute)
+			}
+			if test.counting {
+				focus.Deadline = time.Now().Add(remaining)
+			}
+			app.focus = focus
+			app.IsRunning, app.IsPaused, app.CurrentTaskName = test.after.running, test.after.paused, test.after.taskName
+
+			before := time.Now()
+			app.focusTrackingChanged()
+			if test.ended {
+				if app.focus != nil {
+					t.Fatalf("session %+v, want it ended", app.focus)
+				}
+				return
+			}
+			if app.focus == nil {
+				t.Fatal("session ended")
+			}
+			if counting := !focus.Deadline.IsZero(); counting != test.wantCounting {
+				t.Errorf("counting %v, want %v", counting, test.wantCounting)
+			}
+			if focus.Interruptions != test.interruptions {
+				t.Errorf("%v interruptions, want %v", focus.Interruptions, test.interruptions)
+			}
+			if !test.counting && !test.wantCounting && focus.Remaining != remaining {
+				t.Errorf("remaining %s while on hold, want %s", focus.Remaining, remaining)
+			}
+			if test.counting && !test.wantCounting && (focus.Remaining > remaining || focus.Remaining < remaining-time.Minute) {
+				t.Errorf("remaining %s after holding, want about %s", focus.Remaining, remaining)
+			}
+			if test.phase != focusPhaseWork && test.wantCounting && !test.counting {
+				// the next pomodoro starts from scratch
+				if focus.Phase != focusPhaseWork || focus.Planned != 25*time.Minute || focus.PhaseStart.Before(before) {
+					t.Errorf("phase %s of %s from %s, want a new work interval of 25m0s", focus.Phase, focus.Planned, focus.PhaseStart)
+				}
+			}
+		})
+	}
+}
+
+func TestFocusEndedRecordsTheInterval(t *testing.T) {
+	app := newFocusTestApp(t)
+	app.focus = &focusSession{Mode: store.FocusModePomodoro, TaskName: "Code", Phase: focusPhaseWork, Planned: 25 * time.Minute,
+		PhaseStart: time.Now().Add(-15 * time.Minute), Deadline: time.Now().Add(10 * time.Minute), Interruptions: 2}
+
+	app.focusTrackingChanged() // stopped
+	records := focusRecords(t, app)
+	want := store.FocusRecord{Mode: store.FocusModePomodoro, Planned: 25 * time.Minute, Completed: false, Interruptions: 2}
+	if len(records) != 1 || records[0] != want {
+		t.Errorf("records %+v, want %+v", records, want)
+	}
+}
+
+func TestPomodoroCycle(t *testing.T) {
+	app := newFocusTestApp(t)
+	config := app.Settings.Focus
+	now := time.Now()
+	app.focus = &focusSession{Mode: store.FocusModePomodoro, TaskName: "Code", Phase: focusPhaseWork, Planned: 25 * time.Minute,
+		PhaseStart: now.Add(-25 * time.Minute), Deadline: now, Interruptions: 1, Completed: 2}
+
+	// not yet
+	if _, over := app.advanceFocusLocked(now.Add(-time.Second), config); over {
+		t.Fatal("over a second early")
+	}
+
+	// work is over: recorded as completed, the break starts
+	ended, over := app.advanceFocusLocked(now, config)
+	if !over || focusNext(ended, config) != focusNextBreak {
+		t.Fatalf("over %v, next %q; want the break", over, focusNext(ended, config))
+	}
+	focus := app.focus
+	if focus.Phase != focusPhaseBreak || focus.Completed != 3 || !focus.Deadline.Equal(now.Add(5*time.Minute)) {
+		t.Errorf("phase %s, %v completed, until %s; want a 5m0s break after 3", focus.Phase, focus.Completed, focus.Deadline)
+	}
+	records := focusRecords(t, app)
+	if len(records) != 1 || !records[0].Completed || records[0].Interruptions != 1 {
+		t.Errorf("records %+v, want one completed with 1 interruption", records)
+	}
+
+	// the break is over: ready, waiting or resuming as set
+	ended, over = app.advanceFocusLocked(focus.Deadline, config)
+	if !over || focus.Phase != focusPhaseReady || !focus.Deadline.IsZero() {
+		t.Errorf("over %v, phase %s until %s; want ready with no deadline", over, focus.Phase, focus.Deadline)
+	}
+	if next := focusNext(ended, config); next != focusNextWait {
+		t.Errorf("next %q, want %q", next, focusNextWait)
+	}
+	config.AutoResume = true
+	if next := focusNext(ended, config); next != focusNextResume {
+		t.Errorf("next %q with auto resume, want %q", next, focusNextResume)
+	}
+
+	// ready has nothing left to run out
+	if _, over = app.advanceFocusLocked(now.Add(time.Hour), config); over {
+		t.Error("ready ran out")
+	}
+
+	// the fourth pomodoro gets the long break
+	focus.Phase, focus.PhaseStart, focus.Deadline = focusPhaseWork, now, now.Add(25*time.Minute)
+	app.advanceFocusLocked(focus.Deadline, config)
+	if focus.Completed != 4 || !focus.Deadline.Equal(now.Add(40*time.Minute)) {
+		t.Errorf("%v completed, break until %s; want a 15m0s break after 4", focus.Completed, focus.Deadline)
+	}
+}
+
+func TestTimeboxOver(t *testing.T) {
+	tests := []struct {
+		action     string
+		next       string
+		stopReason string
+	}{
+		{settings.TimeboxActionStop, focusNextStop, StopReasonFocus},
+		{settings.TimeboxActionSwitch, focusNextSwitch, ""},
+		{settings.TimeboxActionContinue, focusNextContinue, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.action, func(t *testing.T) {
+			app := newFocusTestApp(t)
+			config := app.Settings.Focus
+			config.TimeboxAction = test.action
+			now := time.Now()
+			app.IsRunning, app.CurrentTaskName = true, "Code"
+			app.focus = &focusSession{Mode: store.FocusModeTimebox, TaskName: "Code", Phase: focusPhaseWork, Planned: 45 * time.Minute,
+				PhaseStart: now.Add(-45 * time.Minute), Deadline: now}
+
+			ended, over := app.advanceFocusLocked(now, config)
+			if !over {
+				t.Fatal("not over")
+			}
+			if next := focusNext(ended, config); next != test.next {
+				t.Errorf("next %q, want %q", next, test.next)
+			}
+			if app.focus != nil {
+				t.Errorf("session %+v, want it ended", app.focus)
+			}
+			if app.StopReason != test.stopReason {
+				t.Errorf("stop reason %q, want %q", app.StopReason, test.stopReason)
+			}
+			want := store.FocusRecord{Mode: store.FocusModeTimebox, Planned: 45 * time.Minute, Completed: true}
+			if records := focusRecords(t, app); len(records) != 1 || records[0] != want {
+				t.Errorf("records %+v, want %+v", records, want)
+			}
+		})
+	}
+}
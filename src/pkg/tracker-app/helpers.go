@@ -1,16 +1,21 @@
 package trackerapp
 
 import (
+	"errors"
 	"fmt"
 	"image/color"
 	"os/exec"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
 	"golang.org/x/exp/constraints"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/xerr"
 )
 
 // returns "YYYY-MM-DD" for t
@@ -18,12 +23,6 @@ func dateID(t time.Time) (year, month, day string) {
 	return t.Format("2006"), strings.ToLower(t.Format("January")), t.Format("02")
 }
 
-func dayFilePath(workDir, year, month, day string) (currentDir, currentFile string) {
-	currentDir = filepath.Join(workDir, year, month)
-	currentFile = filepath.Join(currentDir, fmt.Sprintf("%s_%s_%s.jsonl", day, month, year))
-	return currentDir, currentFile
-}
-
 func formatDuration(d time.Duration) string {
 	if d < 0 {
 		d = 0
@@ -103,13 +102,13 @@ func lerpColor(c1, c2 color.NRGBA, t float64) color.NRGBA {
 	}
 }
 
-// 0–50%: red→yellow, 50–75%: yellow→green, 75–100%: blueBase→bluePeak (no teal)
+// 0–50%: red→yellow, 50–75%: yellow→green, 75–100%: green→greenPeak.
+// Stops come from the theme so the ramp follows the light/dark variant.
 func barColorFor(p float64) color.Color {
-	red := color.NRGBA{R: 220, G: 60, B: 60, A: 255}     // 0%
-	yellow := color.NRGBA{R: 235, G: 190, B: 50, A: 255} // 50%
-	green := color.NRGBA{R: 60, G: 180, B: 90, A: 255}   // 75%
-	// green peak at 100% (same hue family, different brightness/sat)
-	greenPeak := color.NRGBA{R: 20, G: 180, B: 45, A: 255} // deeper/richer toward 100%
+	red := toNRGBA(theme.Color(colorNameActivity0))         // 0%
+	yellow := toNRGBA(theme.Color(colorNameActivity50))     // 50%
+	green := toNRGBA(theme.Color(colorNameActivity75))      // 75%
+	greenPeak := toNRGBA(theme.Color(colorNameActivity100)) // deeper/richer toward 100%
 
 	t := clamp01(p / 100.0)
 
@@ -121,8 +120,22 @@ func barColorFor(p float64) color.Color {
 		// 50..75%: yellow -> green
 		return lerpColor(yellow, green, (t-0.5)*4.0)
 	default:
-		// 75..100%: start at clearly blue immediately, then deepen the blue
+		// 75..100%: deepen the green
 		u := (t - 0.75) * 4.0 // map [0.75,1] → [0,1]
 		return lerpColor(green, greenPeak, u)
 	}
 }
+
+func toNRGBA(c color.Color) color.NRGBA {
+	return color.NRGBAModel.Convert(c).(color.NRGBA)
+}
+
+// showError logs e and shows it in a dialog on top of parent. Call it from the UI goroutine.
+func showError(e *xerr.Error, parent fyne.Window) {
+	e.Print(xerr.ErrorTypeError, tl.Error, 0)
+	message := e.Msg
+	if e.ErrStr != "" {
+		message = fmt.Sprintf("%s: %s", e.Msg, e.ErrStr)
+	}
+	dialog.ShowError(errors.New(message), parent)
+}
@@ -1,54 +1,51 @@
 package trackerapp
 
 import (
-	"maps"
 	"time"
 
 	tl "github.com/tuumbleweed/tintlog/logger"
 	"github.com/tuumbleweed/tintlog/palette"
 	"github.com/tuumbleweed/xerr"
 
-	"work-tracker/src/pkg/util"
+	"work-tracker/src/pkg/settings"
 )
 
-func InitializeTrackerApp(appId, windowTitle, workDir, tasksFilePath string, uiTickInterval, activityTickInterval, flushInterval time.Duration) (trackerApp *TrackerApp, e *xerr.Error) {
+/*
+InitializeTrackerApp builds the app from effective settings (settings file + command line overrides).
+settingsPath is where the Settings window saves its changes.
+*/
+func InitializeTrackerApp(appId, windowTitle, settingsPath string, userSettings settings.Settings) (trackerApp *TrackerApp, e *xerr.Error) {
+	workDir := userSettings.WorkDir
+	uiTickInterval := userSettings.UITickInterval.Duration
+	activityTickInterval := userSettings.ActivityTickInterval.Duration
+	flushInterval := userSettings.FlushTickInterval.Duration
 	tl.Log(
 		tl.Important, palette.BlueBold,
-		"%s tracker app. App id: '%s', window title: '%s', work dir: '%s', UI tick interval: %s, activity tick interval: %s, flush tick interval: '%s'",
-		"Initializing", appId, windowTitle, workDir, uiTickInterval, activityTickInterval, flushInterval,
+		"%s tracker app. App id: '%s', window title: '%s', profile: '%s', work dir: '%s', UI tick interval: %s, activity tick interval: %s, flush tick interval: '%s'",
+		"Initializing", appId, windowTitle, settings.ProfileLabel(userSettings.ActiveProfile), workDir, uiTickInterval, activityTickInterval, flushInterval,
 	)
 
-	trackerApp, e = initializeInterface(appId, windowTitle, tasksFilePath)
+	trackerApp, e = initializeInterface(appId, windowTitle, settingsPath, userSettings)
 	if e != nil {
 		return trackerApp, e
 	}
 
-	// determine current file path
-	trackerApp.Workdir = workDir
-	trackerApp.CurrentYear, trackerApp.CurrentMonth, trackerApp.CurrentDay = dateID(time.Now())
-	trackerApp.CurrentDirPath, trackerApp.CurrentFilePath = dayFilePath(trackerApp.Workdir, trackerApp.CurrentYear, trackerApp.CurrentMonth, trackerApp.CurrentDay)
-	e = util.EnsureDirExists(trackerApp.CurrentDirPath, 0755)
+	// today's file in the work dir and the totals tracked in it so far
+	layout, e := userSettings.Layout()
 	if e != nil {
 		return trackerApp, e
 	}
-
-	// get information about total duration and active time
-	trackerApp.WorkedToday, trackerApp.ActiveToday, trackerApp.TimeByTask, e = loadFileActivityAndDuration(trackerApp.CurrentFilePath)
+	e = trackerApp.openDayLocked(workDir, layout, time.Now())
 	if e != nil {
 		return trackerApp, e
 	}
-	trackerApp.WorkedTodayBeforeStartingThisRun = trackerApp.WorkedToday
-	// copy by entry
-	trackerApp.TimeByTaskBeforeStartingThisRun = make(map[string]time.Duration, len(trackerApp.TimeByTask))
-	maps.Copy(trackerApp.TimeByTaskBeforeStartingThisRun, trackerApp.TimeByTask)
+	trackerApp.TasksContainer = trackerApp.makeTasksUI() // sorted by today's numbers
 
-	// initialize tickers
+	// initialize the scheduler
 	trackerApp.UITickInterval = uiTickInterval
 	trackerApp.ActivityTickInterval = activityTickInterval
 	trackerApp.FlushTickInterval = flushInterval
-	trackerApp.ActivityTicker = time.NewTicker(trackerApp.ActivityTickInterval)
-	trackerApp.UITicker = time.NewTicker(trackerApp.UITickInterval)
-	trackerApp.FlushTicker = time.NewTicker(trackerApp.FlushTickInterval)
+	trackerApp.wake = make(chan struct{}, 1)
 	trackerApp.done = make(chan struct{})
 	trackerApp.LastActivityTickStart = time.Now()
 
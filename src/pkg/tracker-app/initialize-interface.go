@@ -10,66 +10,96 @@ import (
 	tl "github.com/tuumbleweed/tintlog/logger"
 	"github.com/tuumbleweed/tintlog/palette"
 	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/locale"
+	"work-tracker/src/pkg/notify"
+	"work-tracker/src/pkg/settings"
 )
 
 // initializeInterface sets up the Fyne app/window and constructs the UI widgets.
-// It does NOT wire handlers, lay out content, or start tickers.
+// It does NOT wire handlers, lay out content, or start the scheduler.
 // Call t.initUI() later to compose these widgets into the window.
-func initializeInterface(appId, windowTitle, tasksFilePath string) (t *TrackerApp, e *xerr.Error) {
+func initializeInterface(appId, windowTitle, settingsPath string, userSettings settings.Settings) (t *TrackerApp, e *xerr.Error) {
 	tl.Log(tl.Notice, palette.BlueBold, "%s for '%s'", "Initializing interface", windowTitle)
 
 	// set up the app
 	t = &TrackerApp{}
+	t.SettingsPath = settingsPath
+	t.Settings = userSettings
+	t.Locale, e = locale.New(userSettings.Locale)
+	if e != nil {
+		return nil, e
+	}
 	t.App = app.NewWithID(appId)
 	// Apply a slightly larger theme, light theme
-	currentTheme := t.App.Settings().Theme()
-	t.App.Settings().SetTheme(scaledTheme{
-		base:   currentTheme, // or theme.LightTheme()/DarkTheme()
-		factor: 1.30,
-	})
+	t.BaseTheme = t.App.Settings().Theme()
+	t.applyTheme() // scaled, light/dark/system variant, optional accent
 
 	// Start large + fullscreen
 	t.Window = t.App.NewWindow(windowTitle)
-	t.Window.Resize(fyne.NewSize(1280, 720)) // initial size (before FS)
+	t.Window.Resize(fyne.NewSize(t.Settings.WindowWidth, t.Settings.WindowHeight)) // initial size (before FS)
 	// t.Window.SetFullScreen(true)          // launch fullscreen
 
+	t.Notifier = notify.New(windowTitle)
+
 	e = t.initTray()
 	if e != nil {
 		return nil, e
 	}
+	t.setMainMenu()
 
 	// title canvas
-	t.Title = canvas.NewText("Today", theme.Color(theme.ColorNameForeground))
+	t.Title = canvas.NewText(t.Locale.T("Today"), theme.Color(theme.ColorNameForeground))
 	t.Title.Alignment = fyne.TextAlignCenter
 	t.Title.TextStyle = fyne.TextStyle{Bold: true}
-	t.Title.TextSize = theme.TextSize() * 2.0 // 2x normal
 
 	// task name canva
-	t.TaskLabel = canvas.NewText("Current Task", theme.Color(theme.ColorNameForeground))
+	t.TaskLabel = canvas.NewText(t.Locale.T("CurrentTask"), theme.Color(theme.ColorNameForeground))
 	t.TaskLabel.Alignment = fyne.TextAlignCenter
 	t.TaskLabel.TextStyle = fyne.TextStyle{Bold: false}
-	t.TaskLabel.TextSize = theme.TextSize() * 2.0 // 2x normal
+
+	// note for the current block of work
+	t.NoteEntry = widget.NewEntry()
+	t.NoteEntry.SetPlaceHolder(t.Locale.T("NotePlaceholder"))
+	t.NoteEntry.OnChanged = t.setNote
 
 	// clock widget
 	t.Clock = canvas.NewText("00:00:00", theme.Color(theme.ColorNameForeground))
 	t.Clock.Alignment = fyne.TextAlignCenter
 	t.Clock.TextStyle = fyne.TextStyle{Monospace: true, Bold: true}
-	t.Clock.TextSize = theme.TextSize() * 3.2 // really big
+
+	// focus countdown, shown during a pomodoro or timebox
+	t.FocusLabel = canvas.NewText("", theme.Color(theme.ColorNameForeground))
+	t.FocusLabel.Alignment = fyne.TextAlignCenter
+	t.FocusLabel.TextStyle = fyne.TextStyle{Monospace: true}
+	t.FocusLabel.Hide()
+	t.applyTextSizes()
 
 	// activity bars
-	t.AverageActivityBar = NewActivityBar("Average activity")
-	t.CurrentActivityBar = NewActivityBar("Current activity")
+	t.AverageActivityBar = NewActivityBar(t.Locale.T("AverageActivity"))
+	t.CurrentActivityBar = NewActivityBar(t.activityWindowCaption(t.Settings.ActivityWindow.Duration))
+	t.CurrentActivityBar.Windows = activityWindows
+	t.CurrentActivityBar.Window = t.Settings.ActivityWindow.Duration
+	t.CurrentActivityBar.OnWindowChanged = t.setActivityWindow
+	t.ActivitySparkline = NewSparkline()
 
 	// start button
-	t.Button = widget.NewButtonWithIcon("Start", theme.MediaPlayIcon(), nil)
+	t.Button = widget.NewButtonWithIcon(t.Locale.T("Start"), theme.MediaPlayIcon(), nil)
 	t.Button.Importance = widget.MediumImportance
+	t.PauseButton = widget.NewButtonWithIcon(t.Locale.T("PauseMenu"), theme.MediaPauseIcon(), nil)
+	t.PauseButton.Disable()
+	t.FocusButton = widget.NewButtonWithIcon(t.Locale.T("FocusMenu"), theme.HistoryIcon(), nil)
+	t.makeSuggestionBar()
 
 	// after you computed tickers & LastTickStart...
-	tasks, e := loadTasks(tasksFilePath)
+	tasks, e := loadTasks(t.Settings.TasksPath)
 	if e != nil {
 		return t, e
 	}
-	t.TasksContainer = t.makeTasksUI(tasks)
+	t.Tasks = tasks
+
+	// follow theme changes (settings window, desktop switching light/dark)
+	t.App.Settings().AddListener(t.onThemeChanged)
 
 	tl.Log(tl.Notice1, palette.GreenBold, "%s for '%s'", "Initialized interface", windowTitle)
 	return t, nil
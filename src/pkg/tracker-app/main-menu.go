@@ -0,0 +1,39 @@
+package trackerapp
+
+import (
+	"fyne.io/fyne/v2"
+)
+
+// setMainMenu adds the window menu bar. Items open secondary windows; tracking stays on the main screen.
+func (t *TrackerApp) setMainMenu() {
+	// fyne appends a Quit item that calls App.Quit directly; supply our own so the open chunk gets flushed
+	quitItem := fyne.NewMenuItem(t.Locale.T("Quit"), func() { t.onClose() })
+	quitItem.IsQuit = true
+
+	// the shortcut itself is registered on the canvas, so it also works in mini mode without a menu bar
+	miniModeItem := fyne.NewMenuItem(t.Locale.T("MiniMode"), t.toggleMiniMode)
+	miniModeItem.Shortcut = miniModeShortcut
+
+	asOfItem := fyne.NewMenuItem(t.Locale.T("StartOrSwitchAsOf"), t.showAsOfDialog)
+	asOfItem.Shortcut = asOfShortcut
+	undoItem := fyne.NewMenuItem(t.Locale.T("UndoLastAction"), t.undoFromUI)
+	undoItem.Shortcut = undoShortcut
+
+	trackerMenu := fyne.NewMenu(t.Locale.T("TrackerMenu"),
+		asOfItem,
+		fyne.NewMenuItem(t.Locale.T("SplitTime"), t.showSplitDialog),
+		undoItem,
+		fyne.NewMenuItemSeparator(),
+		miniModeItem,
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(t.Locale.T("ReportsMenu"), t.showReportsWindow),
+		fyne.NewMenuItem(t.Locale.T("SettingsMenu"), t.showSettingsWindow),
+		fyne.NewMenuItemSeparator(),
+		quitItem,
+	)
+	menus := []*fyne.Menu{trackerMenu}
+	if len(t.settingsSnapshot().Profiles) > 0 {
+		menus = append(menus, fyne.NewMenu(t.Locale.T("Profile"), t.profileItems()...))
+	}
+	t.Window.SetMainMenu(fyne.NewMainMenu(menus...))
+}
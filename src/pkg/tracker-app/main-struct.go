@@ -6,8 +6,14 @@ import (
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/widget"
+
+	"work-tracker/src/pkg/locale"
+	"work-tracker/src/pkg/notify"
+	"work-tracker/src/pkg/settings"
+	"work-tracker/src/pkg/store"
 )
 
 type TrackerApp struct {
@@ -17,29 +23,42 @@ type TrackerApp struct {
 	// UI elements
 	Title              *canvas.Text
 	TaskLabel          *canvas.Text
+	NoteEntry          *widget.Entry // note for the current block of work, saved on its chunks
 	Clock              *canvas.Text
+	FocusLabel         *canvas.Text // focus countdown under the clock, hidden without a session
 	AverageActivityBar *ActivityBar
-	CurrentActivityBar *ActivityBar
+	CurrentActivityBar *ActivityBar // rolling window picked by clicking it, see activity-window.go
+	ActivitySparkline  *Sparkline   // activity over the last hour, under the bars
 	Button             *widget.Button
-	TableRows          map[string]TableRow
+	PauseButton        *widget.Button   // reason picker while running, ends the pause while paused
+	FocusButton        *widget.Button   // pomodoro and timebox picker
+	TaskTable          *widget.Table    // virtualized, see tasks-ui.go
+	taskView           taskTableView    // what TaskTable shows, UI goroutine only
+	TaskSplit          *container.Split // TaskTable | TaskDetail
+	TaskDetail         *fyne.Container  // hidden until a task is clicked, see task-detail.go
+	SuggestionBar      *fyne.Container  // offers to assign unassigned time to its suggestion, see suggest.go
+	SuggestionLabel    *widget.Label
+	shownSuggestion    string // task the bar offers, UI goroutine only
 	TasksContainer     *fyne.Container
+	TasksTitle         *canvas.Text
 
-	// tickers
-	UITicker             *time.Ticker  // UI clock
-	ActivityTicker       *time.Ticker  //activity clock
-	FlushTicker          *time.Ticker  // file chunk save clock
-	UITickInterval       time.Duration // for UITicker
-	ActivityTickInterval time.Duration // for ActivityTicker
-	FlushTickInterval    time.Duration // for FlushTicker
+	// scheduler (see schedulerLoop), intervals are guarded by Mutex
+	UITickInterval       time.Duration // UI clock
+	ActivityTickInterval time.Duration // activity clock
+	FlushTickInterval    time.Duration // file chunk save clock
+	WindowVisible        bool          // main window shown, widgets are only rendered then
+	wake                 chan struct{}
 	done                 chan struct{}
 
 	// dirs
 	Workdir         string
+	Layout          *store.Layout // where each day's file is under Workdir
 	CurrentYear     string
 	CurrentMonth    string
 	CurrentDay      string
+	CurrentDate     time.Time // midnight of the day CurrentFilePath is open for
 	CurrentDirPath  string
-	CurrentFilePath string
+	CurrentFilePath string // shared with the other days of the month or year in such layouts
 
 	// time
 	WorkedTodayBeforeStartingThisRun time.Duration // for how long user tracked time today
@@ -49,28 +68,81 @@ type TrackerApp struct {
 	LastTickActiveDuration           time.Duration // how much out of that user was active
 	TimeByTaskBeforeStartingThisRun  map[string]time.Duration
 	TimeByTask                       map[string]time.Duration
+	todayStats                       map[string]taskDayStats // per task from today's file, see task-stats.go
+	taskHistory                      map[string]taskHistory  // per task from the other day files
+
+	// today's file as the tracker last wrote or read it, see day-file-watch.go
+	dayFileStamp dayFileStamp
+
+	// unassigned time today per suggested task, offered for confirmation (see suggest.go)
+	suggestedTime map[string]time.Duration
+
+	// ticks of the last hour, oldest first, for the rolling activity window and the sparkline
+	activitySamples []activitySample
 
 	// mutex
 	Mutex sync.Mutex
 
 	// run info
 	IsRunning             bool
-	RunStart              time.Time // when last pressed "start" button
-	TaskRunStart          time.Time // when last pressed "start" button
-	ChunkStart            time.Time // when last time chunk was saved
-	LastActivityTickStart time.Time // when last tick has started
-	CurrentTaskName       string    // which task is running right now, can be empty
+	SessionStart          time.Time      // when the current run started (stays put when the state is rebased)
+	RunStart              time.Time      // when last pressed "start" button
+	TaskRunStart          time.Time      // when last pressed "start" button
+	ChunkStart            time.Time      // when last time chunk was saved
+	LastActivityTickStart time.Time      // when last tick has started
+	CurrentTaskName       string         // which task is running right now, can be empty
+	CurrentNote           string         // written on every chunk until the next stop or switch
+	noteBlockStart        time.Time      // when the block CurrentNote describes began, only start, switch and stop set it (rebasing moves TaskRunStart)
+	SuggestedTask         string         // guess for the current unassigned run, written on its chunks
+	ActivityUnknown       bool           // xprintidle failed on the last activity tick
+	IsPaused              bool           // stopped by Pause, the pause chunk is written when it ends
+	PauseReason           string         // one of PauseReasons
+	PauseStart            time.Time      // when the pause began
+	PausedTaskName        string         // task to resume
+	LastAction            *trackerAction // last start/stop/switch, for undo
+	StartReason           string         // written on the run's first chunk, see StartReasonUnlock
+	StopReason            string         // written on the run's last chunk (or the pause it ends), see session.Reason*
+	lockedRun             *lockedRun     // what the session lock stopped or paused, to offer on unlock
+	scheduleWindowUsed    time.Time      // start of the working-hours window tracking already happened in
+	focus                 *focusSession  // pomodoro or timebox in progress, nil when there's none
 
 	// tray
-	DeskApp       desktop.App
-	TrayIconBlue  fyne.Resource
-	TrayIconGreen fyne.Resource
-}
+	DeskApp         desktop.App
+	TrayMenu        *fyne.Menu
+	TrayStatusItem  *fyne.MenuItem // current task and time on it (disabled)
+	TrayTodayItem   *fyne.MenuItem // worked today (disabled)
+	TrayToggleItem  *fyne.MenuItem // Start/Stop
+	TraySwitchItem  *fyne.MenuItem // submenu of tasks to switch to
+	TrayMiniItem    *fyne.MenuItem // mini mode, checked when on
+	TrayUndoItem    *fyne.MenuItem // undo last start/stop/switch, disabled when there's nothing to undo
+	TrayPauseItem   *fyne.MenuItem // submenu of pause reasons, disabled unless running
+	TrayProfileItem *fyne.MenuItem // submenu of profiles, nil when the settings file has none
+	TrayFocusItem   *fyne.MenuItem // submenu of pomodoro and timeboxes, shows the countdown during a session
+	traySwitchKey   string         // what the switch submenu was built from
+	trayFocusEnd    bool           // the focus submenu has "End focus"
+	trayIconCurrent fyne.Resource
+	trayIconCache   map[trayIconKey]fyne.Resource
+
+	// tasks
+	Tasks       []Task   // as loaded from the tasks file
+	RecentTasks []string // most recently started first
+
+	// notifications
+	Notifier       notify.Notifier
+	IdleFor        time.Duration        // time since the last input, from the last activity tick
+	notifyTriggers notificationTriggers // when each reminder last went out
+
+	// reports
+	ReportsWindow fyne.Window // nil when closed
+
+	// settings
+	SettingsPath   string            // where the Settings window saves to
+	Settings       settings.Settings // effective settings (file + command line overrides), written and read under Mutex, see settingsSnapshot
+	SettingsWindow fyne.Window       // nil when closed
+	Locale         *locale.Locale    // language and formats of the window, tray, notifications and reports
+	BaseTheme      fyne.Theme        // theme that scaledTheme wraps
 
-type TableRow struct {
-	Button           *widget.Button
-	NameLabel        *widget.Label
-	DescriptionLabel *widget.Label
-	CreatedAtLabel   *widget.Label
-	TimeLabel        *widget.Label
+	// window state
+	windowAbove            bool // asked the window manager to keep the window on top
+	windowPositionRestored bool // saved position was applied after the first show
 }
@@ -0,0 +1,136 @@
+package trackerapp
+
+import (
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/store"
+)
+
+/*
+Notes say what a block of work was about. The note in the entry under the task
+label goes on every chunk flushed until the next stop or switch, which clears it.
+A block is what was tracked on one task since its start or switch (noteBlockStart),
+so when it ends, a note typed halfway through is written on its earlier chunks too.
+
+With Settings.PromptNoteOnStop, stopping or switching from the UI asks for a
+summary afterwards and writes it on every chunk of the block that just ended.
+*/
+
+// noteBlock is the stretch of work on one task that a note describes
+type noteBlock struct {
+	TaskName string
+	Start    time.Time // noteBlockStart
+	Note     string
+}
+
+// setNote is the note entry's OnChanged
+func (t *TrackerApp) setNote(text string) {
+	t.Mutex.Lock()
+	t.CurrentNote = strings.TrimSpace(text)
+	t.Mutex.Unlock()
+}
+
+// clearNote starts the next block with a fresh note
+func (t *TrackerApp) clearNote() {
+	t.Mutex.Lock()
+	t.CurrentNote = ""
+	t.Mutex.Unlock()
+	fyne.Do(func() { t.NoteEntry.SetText("") })
+}
+
+// currentNoteBlock describes what's running, nil when nothing is
+func (t *TrackerApp) currentNoteBlock() *noteBlock {
+	t.Mutex.Lock()
+	defer t.Mutex.Unlock()
+	if !t.IsRunning {
+		return nil
+	}
+	return &noteBlock{TaskName: t.CurrentTaskName, Start: t.noteBlockStart, Note: t.CurrentNote}
+}
+
+// backfillNote writes the note of block, which ended at end, on its chunks flushed before the note was typed
+func (t *TrackerApp) backfillNote(block *noteBlock, end time.Time) {
+	if block == nil || block.Note == "" {
+		return
+	}
+	e := t.annotateBlock(*block, end, block.Note)
+	if e != nil {
+		tl.Log(tl.Warning, palette.Yellow, "%s: %s", "Note not written on the earlier chunks", e.Msg)
+	}
+}
+
+/*
+promptNote asks what was done in block once it has ended.
+No-op when block is nil or still going (e.g. switching to the task that was already running).
+*/
+func (t *TrackerApp) promptNote(block *noteBlock) {
+	if block == nil {
+		return
+	}
+	t.Mutex.Lock()
+	stillGoing := t.IsRunning && t.CurrentTaskName == block.TaskName && t.noteBlockStart.Equal(block.Start)
+	promptNote := t.Settings.PromptNoteOnStop
+	t.Mutex.Unlock()
+	if stillGoing {
+		return
+	}
+	end := time.Now()
+
+	if !promptNote {
+		return
+	}
+	fyne.Do(func() {
+		l := t.Locale
+		taskName := block.TaskName
+		if taskName == "" {
+			taskName = l.T("UnassignedTask")
+		}
+		noteEntry := widget.NewMultiLineEntry()
+		noteEntry.SetText(block.Note)
+		noteEntry.SetPlaceHolder(l.T("NotePromptPlaceholder"))
+		title := l.T("NotePromptTitle", "Task", taskName, "Start", l.Clock(block.Start), "End", l.Clock(end))
+		items := []*widget.FormItem{widget.NewFormItem(l.T("NotePromptNote"), noteEntry)}
+
+		t.showWindow() // from the tray the window is usually hidden
+		formDialog := dialog.NewForm(title, l.T("Save"), l.T("Skip"), items, func(confirmed bool) {
+			note := strings.TrimSpace(noteEntry.Text)
+			if !confirmed || note == block.Note {
+				return
+			}
+			go func() {
+				e := t.annotateBlock(*block, end, note)
+				if e != nil {
+					fyne.Do(func() { showError(e, t.Window) })
+				}
+			}()
+		}, t.Window)
+		formDialog.Resize(fyne.NewSize(560, 260))
+		formDialog.Show()
+	})
+}
+
+// annotateBlock writes note on the block's work chunks between its start and end
+func (t *TrackerApp) annotateBlock(block noteBlock, end time.Time, note string) (e *xerr.Error) {
+	tl.Log(tl.Info, palette.Cyan, "%s. Task name: '%s', from: %s, note: '%s'", "Saving note", block.TaskName, block.Start.Format(time.TimeOnly), note)
+	start := block.Start.Round(0)
+	t.Mutex.Lock()
+	defer t.Mutex.Unlock()
+	return t.rewriteDayLocked(time.Now(), func(chunks []store.Chunk) []store.Chunk {
+		for i, chunk := range chunks {
+			inBlock := !chunk.StartedAt.Before(start) && !chunk.FinishedAt.After(end)
+			if chunk.Kind == store.ChunkKindWork && chunk.TaskName == block.TaskName && inBlock {
+				chunks[i].Note = note
+			}
+		}
+		return chunks
+	})
+}
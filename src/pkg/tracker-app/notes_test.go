@@ -0,0 +1,76 @@
+package trackerapp
+
+import (
+	"testing"
+	"time"
+
+	"work-tracker/src/pkg/store"
+)
+
+func TestBackfillNote(t *testing.T) {
+	day := time.Date(2026, 1, 23, 0, 0, 0, 0, time.Local)
+	clock := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	layout := store.MustParseLayout(store.DefaultLayout)
+	workDir := t.TempDir()
+
+	// an earlier block on the same task, another task, then the block: the note was typed during its last chunk
+	chunks := []store.Chunk{
+		{TaskName: "Code", StartedAt: clock(8, 0), FinishedAt: clock(8, 30), ActiveTime: 20 * time.Minute, Note: "earlier"},
+		{TaskName: "Email", StartedAt: clock(8, 30), FinishedAt: clock(9, 0), ActiveTime: 10 * time.Minute},
+		{TaskName: "Code", StartedAt: clock(9, 0), FinishedAt: clock(9, 10), ActiveTime: 5 * time.Minute},
+		{Kind: store.ChunkKindPause, StartedAt: clock(9, 10), FinishedAt: clock(9, 12), PauseReason: "break"},
+		{TaskName: "Code", StartedAt: clock(9, 12), FinishedAt: clock(9, 20), ActiveTime: 5 * time.Minute, Note: "fixed the parser"},
+	}
+	e := store.NewWriter(workDir, layout).RewriteDay(day, chunks)
+	if e != nil {
+		t.Fatalf("write: %s", e.Msg)
+	}
+
+	app := &TrackerApp{}
+	app.Mutex.Lock()
+	e = app.openDayLocked(workDir, layout, clock(9, 30))
+	app.Mutex.Unlock()
+	if e != nil {
+		t.Fatalf("open: %s", e.Msg)
+	}
+
+	// the block began at 9:00 and the state was rebased since, which moves TaskRunStart but not the block
+	app.IsRunning, app.CurrentTaskName, app.CurrentNote = true, "Code", "fixed the parser"
+	app.TaskRunStart, app.ChunkStart, app.noteBlockStart = clock(9, 20), clock(9, 20), clock(9, 0)
+	app.Mutex.Lock()
+	e = app.rebaseLocked(clock(9, 20))
+	app.Mutex.Unlock()
+	if e != nil {
+		t.Fatalf("rebase: %s", e.Msg)
+	}
+	block := app.currentNoteBlock()
+	if !block.Start.Equal(clock(9, 0)) {
+		t.Fatalf("block starts at %s after a rebase, want 9:00", block.Start)
+	}
+
+	app.IsRunning = false
+	app.backfillNote(block, clock(9, 20))
+
+	got, e := store.NewReader(workDir, layout, store.Strict).ReadDay(day)
+	if e != nil {
+		t.Fatalf("read: %s", e.Msg)
+	}
+	want := []string{"earlier", "", "fixed the parser", "", "fixed the parser"}
+	if len(got) != len(want) {
+		t.Fatalf("%v chunks, want %v", len(got), len(want))
+	}
+	for i, chunk := range got {
+		if chunk.Note != want[i] {
+			t.Errorf("chunk %v (%s %s): note %q, want %q", i, chunk.TaskName, chunk.StartedAt.Format("15:04"), chunk.Note, want[i])
+		}
+	}
+}
+
+func TestBackfillNoteWithoutNote(t *testing.T) {
+	// nothing to write, and no app state is needed to find that out
+	app := &TrackerApp{}
+	app.backfillNote(nil, time.Now())
+	app.backfillNote(&noteBlock{TaskName: "Code", Start: time.Now()}, time.Now())
+}
@@ -0,0 +1,149 @@
+package trackerapp
+
+import (
+	"time"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+
+	"work-tracker/src/pkg/notify"
+	"work-tracker/src/pkg/settings"
+)
+
+/*
+Desktop reminders, checked on every activity tick:
+
+  - break:          tracking for a long stretch without a stop or pause
+  - idle:           tracking, but no keyboard/mouse input for a while
+  - workday end:    the timer is still running after working hours
+  - active stopped: input for a while, but nothing is being tracked
+
+During quiet hours nothing is sent; a reminder that came due is sent when they end
+(if its condition still holds).
+*/
+
+// input within this long counts as "active", anything longer as "idle"
+const idleThreshold = time.Minute
+
+// notification keys, a newer one replaces the previous one with the same key
+const (
+	notifyKeyBreak         = "break"
+	notifyKeyIdle          = "idle"
+	notifyKeyWorkdayEnd    = "workday-end"
+	notifyKeyActiveStopped = "active-stopped"
+)
+
+type notificationTriggers struct {
+	breakReminder      notify.Trigger
+	idleWhileRunning   notify.Trigger
+	workdayEnd         notify.Trigger
+	activeWhileStopped notify.Trigger
+}
+
+// checkNotifications sends whichever reminders are due at now. Runs on the activity goroutine.
+func (t *TrackerApp) checkNotifications(now time.Time) {
+	config := t.settingsSnapshot().Notifications
+	if !config.Enabled {
+		return
+	}
+
+	t.Mutex.Lock()
+	isRunning := t.IsRunning
+	isPaused := t.IsPaused
+	activityUnknown := t.ActivityUnknown
+	idleFor := t.IdleFor
+	sessionStart := t.SessionStart
+	taskName := t.CurrentTaskName
+	workedToday := t.WorkedToday
+	t.Mutex.Unlock()
+	if taskName == "" {
+		taskName = t.Locale.T("UnassignedTask")
+	}
+	idleKnown := !activityUnknown // while stopped, only polled when a rule needs it (see idleNeededWhileStopped)
+
+	send := func(trigger *notify.Trigger, n notify.Notification) {
+		if t.showNotification(n, now) {
+			trigger.Fired(now)
+		} // otherwise it stays due and goes out after the quiet hours
+	}
+
+	triggers := &t.notifyTriggers
+	if reminderDue(&triggers.breakReminder, config.BreakReminder, isRunning, sessionStart, now) {
+		send(&triggers.breakReminder, notify.Notification{
+			Key:   notifyKeyBreak,
+			Title: t.Locale.T("NotifyBreakTitle"),
+			Body:  t.Locale.T("NotifyBreakBody", "Duration", t.Locale.DurationMinutes(now.Sub(sessionStart))),
+		})
+	}
+
+	idle := isRunning && idleKnown && idleFor >= idleThreshold
+	if reminderDue(&triggers.idleWhileRunning, config.IdleWhileRunning, idle, now.Add(-idleFor), now) {
+		send(&triggers.idleWhileRunning, notify.Notification{
+			Key:   notifyKeyIdle,
+			Title: t.Locale.T("NotifyIdleTitle"),
+			Body:  t.Locale.T("NotifyIdleBody", "Duration", t.Locale.DurationMinutes(idleFor), "Task", taskName),
+		})
+	}
+
+	if workdayEnd, ok := workdayEndOn(now, config.WorkdayEnd); ok {
+		afterHours := isRunning && !now.Before(workdayEnd)
+		if triggers.workdayEnd.Due(afterHours, workdayEnd, now, 0, config.WorkdayEndRepeat.Duration) {
+			send(&triggers.workdayEnd, notify.Notification{
+				Key:     notifyKeyWorkdayEnd,
+				Title:   t.Locale.T("NotifyWorkdayEndTitle"),
+				Body:    t.Locale.T("NotifyWorkdayEndBody", "Time", t.Locale.Clock(workdayEnd), "Task", taskName, "Today", t.Locale.DurationMinutes(workedToday)),
+				Urgency: notify.UrgencyCritical,
+			})
+		}
+	}
+
+	active := !isRunning && !isPaused && idleKnown && idleFor < idleThreshold
+	if reminderDue(&triggers.activeWhileStopped, config.ActiveWhileStopped, active, now, now) {
+		send(&triggers.activeWhileStopped, notify.Notification{
+			Key:     notifyKeyActiveStopped,
+			Title:   t.Locale.T("NotifyActiveStoppedTitle"),
+			Body:    t.Locale.T("NotifyActiveStoppedBody", "Duration", t.Locale.DurationMinutes(config.ActiveWhileStopped.After.Duration)),
+			Urgency: notify.UrgencyLow,
+		})
+	}
+}
+
+/*
+showNotification shows n unless notifications are off or it's quiet hours,
+and returns whether it went out. A notifier failure is only logged and counts
+as shown, so a broken notifier isn't retried every tick.
+*/
+func (t *TrackerApp) showNotification(n notify.Notification, now time.Time) (shown bool) {
+	config := t.settingsSnapshot().Notifications
+	if !config.Enabled || config.InQuietHours(now) || t.Notifier == nil {
+		return false
+	}
+	tl.Log(tl.Info, palette.Cyan, "%s '%s': %s", "Notifying", n.Key, n.Body)
+	e := t.Notifier.Notify(n)
+	if e != nil {
+		tl.Log(tl.Warning, palette.Yellow, "Failed to show '%s' notification: %s", n.Key, e.Msg)
+	}
+	return true
+}
+
+// idleNeededWhileStopped tells whether some rule watches for activity while nothing is tracked. Must hold Mutex.
+func (t *TrackerApp) idleNeededWhileStopped() bool {
+	activeWhileStopped := t.Settings.Notifications.Enabled && t.Settings.Notifications.ActiveWhileStopped.After.Duration > 0
+	autoStart := t.Settings.Schedule.Enabled && t.Settings.Schedule.AutoStart
+	return activeWhileStopped || autoStart
+}
+
+// reminderDue applies a configured reminder to its trigger, After 0 means the reminder is off
+func reminderDue(trigger *notify.Trigger, reminder settings.Reminder, holding bool, since, now time.Time) bool {
+	enabled := reminder.After.Duration > 0
+	return trigger.Due(enabled && holding, since, now, reminder.After.Duration, reminder.Repeat.Duration)
+}
+
+// workdayEndOn is the "HH:MM" clock time on now's day, false when unset or invalid
+func workdayEndOn(now time.Time, clock string) (at time.Time, ok bool) {
+	sinceMidnight, err := settings.ParseClock(clock)
+	if clock == "" || err != nil {
+		return at, false
+	}
+	return startOfDay(now).Add(sinceMidnight), true
+}
@@ -0,0 +1,151 @@
+package trackerapp
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"work-tracker/src/pkg/locale"
+	"work-tracker/src/pkg/notify"
+	"work-tracker/src/pkg/settings"
+)
+
+func minutes(m int) settings.Duration {
+	return settings.Duration{Duration: time.Duration(m) * time.Minute}
+}
+
+func TestReminderDue(t *testing.T) {
+	start := time.Date(2026, 1, 23, 9, 0, 0, 0, time.Local)
+	tests := []struct {
+		name     string
+		reminder settings.Reminder
+		holding  bool
+		now      time.Time
+		want     bool
+	}{
+		{"after 0 is off", settings.Reminder{}, true, start.Add(10 * time.Hour), false},
+		{"after 0 with a repeat is off", settings.Reminder{Repeat: minutes(5)}, true, start.Add(10 * time.Hour), false},
+		{"not held long enough", settings.Reminder{After: minutes(30)}, true, start.Add(29 * time.Minute), false},
+		{"held long enough", settings.Reminder{After: minutes(30)}, true, start.Add(30 * time.Minute), true},
+		{"not holding", settings.Reminder{After: minutes(30)}, false, start.Add(time.Hour), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var trigger notify.Trigger
+			if got := reminderDue(&trigger, test.reminder, test.holding, start, test.now); got != test.want {
+				t.Errorf("reminderDue = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// newNotifyTestApp is a tracker with only what checkNotifications needs, sending to a Fake
+func newNotifyTestApp(t *testing.T, config settings.NotificationSettings) (*TrackerApp, *notify.Fake) {
+	t.Helper()
+	l, e := locale.New(locale.Options{Language: "en", TimeFormat: locale.TimeFormat24h})
+	if e != nil {
+		t.Fatalf("locale: %s", e.Msg)
+	}
+	fake := &notify.Fake{}
+	app := &TrackerApp{Locale: l, Notifier: fake}
+	app.Settings.Notifications = config
+	return app, fake
+}
+
+func sentKeys(fake *notify.Fake) (keys []string) {
+	for _, n := range fake.Sent() {
+		keys = append(keys, n.Key)
+	}
+	return keys
+}
+
+func TestCheckNotifications(t *testing.T) {
+	day := time.Date(2026, 1, 23, 0, 0, 0, 0, time.Local)
+	clock := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	t.Run("break reminder and its repeat", func(t *testing.T) {
+		app, fake := newNotifyTestApp(t, settings.NotificationSettings{
+			Enabled:       true,
+			BreakReminder: settings.Reminder{After: minutes(60), Repeat: minutes(15)},
+		})
+		app.IsRunning, app.SessionStart, app.ActivityUnknown = true, clock(9, 0), true
+		for _, now := range []time.Time{clock(9, 59), clock(10, 0), clock(10, 10), clock(10, 15)} {
+			app.checkNotifications(now)
+		}
+		if keys := sentKeys(fake); !slices.Equal(keys, []string{notifyKeyBreak, notifyKeyBreak}) {
+			t.Errorf("sent %v", keys)
+		}
+	})
+
+	t.Run("held back in quiet hours, sent after", func(t *testing.T) {
+		app, fake := newNotifyTestApp(t, settings.NotificationSettings{
+			Enabled:       true,
+			BreakReminder: settings.Reminder{After: minutes(60)},
+			QuietFrom:     "22:00",
+			QuietTo:       "07:00",
+		})
+		app.IsRunning, app.SessionStart, app.ActivityUnknown = true, clock(5, 0), true
+		app.checkNotifications(clock(6, 30))
+		if keys := sentKeys(fake); len(keys) != 0 {
+			t.Fatalf("sent %v in quiet hours", keys)
+		}
+		app.checkNotifications(clock(7, 0))
+		if keys := sentKeys(fake); !slices.Equal(keys, []string{notifyKeyBreak}) {
+			t.Errorf("sent %v after quiet hours", keys)
+		}
+	})
+
+	t.Run("idle while running", func(t *testing.T) {
+		app, fake := newNotifyTestApp(t, settings.NotificationSettings{
+			Enabled:          true,
+			IdleWhileRunning: settings.Reminder{After: minutes(5)},
+		})
+		app.IsRunning, app.SessionStart = true, clock(9, 0)
+		app.IdleFor = 4 * time.Minute
+		app.checkNotifications(clock(9, 30))
+		app.IdleFor = 5 * time.Minute
+		app.checkNotifications(clock(9, 31))
+		sent := fake.Sent()
+		if len(sent) != 1 || sent[0].Key != notifyKeyIdle {
+			t.Fatalf("sent %+v", sent)
+		}
+	})
+
+	t.Run("workday end", func(t *testing.T) {
+		app, fake := newNotifyTestApp(t, settings.NotificationSettings{Enabled: true, WorkdayEnd: "18:00"})
+		app.IsRunning, app.SessionStart, app.ActivityUnknown = true, clock(9, 0), true
+		app.checkNotifications(clock(17, 59))
+		app.checkNotifications(clock(18, 0))
+		app.checkNotifications(clock(19, 0)) // repeat 0 reminds once
+		sent := fake.Sent()
+		if len(sent) != 1 || sent[0].Key != notifyKeyWorkdayEnd || sent[0].Urgency != notify.UrgencyCritical {
+			t.Fatalf("sent %+v", sent)
+		}
+	})
+
+	t.Run("active while stopped", func(t *testing.T) {
+		app, fake := newNotifyTestApp(t, settings.NotificationSettings{
+			Enabled:            true,
+			ActiveWhileStopped: settings.Reminder{After: minutes(10)},
+		})
+		app.checkNotifications(clock(9, 0))
+		app.checkNotifications(clock(9, 10))
+		if keys := sentKeys(fake); !slices.Equal(keys, []string{notifyKeyActiveStopped}) {
+			t.Errorf("sent %v", keys)
+		}
+	})
+
+	t.Run("off", func(t *testing.T) {
+		app, fake := newNotifyTestApp(t, settings.NotificationSettings{
+			BreakReminder: settings.Reminder{After: minutes(1)},
+			WorkdayEnd:    "08:00",
+		})
+		app.IsRunning, app.SessionStart = true, clock(9, 0)
+		app.checkNotifications(clock(12, 0))
+		if keys := sentKeys(fake); len(keys) != 0 {
+			t.Errorf("sent %v while notifications are off", keys)
+		}
+	})
+}
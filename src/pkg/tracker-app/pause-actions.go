@@ -0,0 +1,146 @@
+package trackerapp
+
+import (
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+
+	"work-tracker/src/pkg/store"
+)
+
+/*
+Pausing stops the run and remembers why and what was running. The paused span
+is written as a single pause chunk when the pause ends (resume, start, stop or quit),
+so worked time never includes it and reports can show breaks on their own.
+*/
+
+// pauseTracking stops the running task with a reason. No-op unless running.
+func (t *TrackerApp) pauseTracking(reason string) {
+	t.Mutex.Lock()
+	isRunning := t.IsRunning
+	currentTaskName := t.CurrentTaskName
+	t.Mutex.Unlock()
+	if !isRunning {
+		return
+	}
+
+	tl.Log(tl.Info, palette.Cyan, "%s. Task name: '%s', reason: '%s'", "Pausing task", currentTaskName, reason)
+	t.refreshActivityState()
+	t.refreshUIState()
+	t.flushChunkIfRunning()
+	block := t.currentNoteBlock()
+	t.Mutex.Lock()
+	pausedAt := t.ChunkStart // end of the chunk just flushed
+	t.Mutex.Unlock()
+	t.flipSwitch("", time.Time{})
+	t.backfillNote(block, pausedAt) // resuming starts a new block
+
+	t.Mutex.Lock()
+	t.IsPaused = true
+	t.PauseReason = reason
+	t.PauseStart = pausedAt
+	t.PausedTaskName = currentTaskName
+	t.LastAction = nil // resuming is the way back from a pause
+	t.Mutex.Unlock()
+	t.afterTrackingChanged("")
+}
+
+// resumeTracking ends the pause and starts the task that was paused.
+func (t *TrackerApp) resumeTracking() {
+	t.Mutex.Lock()
+	isPaused := t.IsPaused
+	pausedTaskName := t.PausedTaskName
+	t.Mutex.Unlock()
+	if !isPaused {
+		return
+	}
+
+	tl.Log(tl.Info, palette.Cyan, "%s. Task name: '%s'", "Resuming task", pausedTaskName)
+	t.startTaskAt(pausedTaskName, time.Now()) // ends the pause first
+}
+
+/*
+endPauseAt writes the pause chunk [PauseStart, at] and leaves the paused state,
+StopReason goes on the pause when set.
+No-op when not paused. A failed write is logged: losing a break record
+must not stop tracking.
+*/
+func (t *TrackerApp) endPauseAt(at time.Time) {
+	t.Mutex.Lock()
+	defer t.Mutex.Unlock()
+	if !t.IsPaused {
+		return
+	}
+	t.IsPaused = false
+	stopReason := t.StopReason
+	t.StopReason = "" // only this pause carries it
+
+	if !at.After(t.PauseStart) {
+		return // resumed right away, nothing worth recording
+	}
+	chunk := store.Chunk{
+		TaskName:    t.PausedTaskName,
+		StartedAt:   t.PauseStart.Round(0),
+		FinishedAt:  at.Round(0),
+		Kind:        store.ChunkKindPause,
+		PauseReason: t.PauseReason,
+		StopReason:  stopReason,
+	}
+	e := store.AppendFile(t.CurrentFilePath, chunk)
+	if e != nil {
+		tl.Log(tl.Error, palette.Red, "Failed to write %s pause: %s", t.PauseReason, e.Msg)
+		return
+	}
+	t.stampDayFileLocked()
+	tl.Log(tl.Detailed1, palette.Green, "%s %s pause of %s", "Recorded", t.PauseReason, at.Sub(t.PauseStart).Round(time.Second))
+}
+
+// onPauseButtonTapped offers the reasons while running and ends the pause while paused
+func (t *TrackerApp) onPauseButtonTapped() {
+	t.Mutex.Lock()
+	isPaused := t.IsPaused
+	t.Mutex.Unlock()
+	if isPaused {
+		t.stopTracking()
+		return
+	}
+
+	reasonsMenu := fyne.NewMenu("", t.pauseReasonItems()...)
+	below := fyne.NewPos(0, t.PauseButton.Size().Height)
+	widget.ShowPopUpMenuAtRelativePosition(reasonsMenu, t.Window.Canvas(), below, t.PauseButton)
+}
+
+// one menu item per pause reason, shared by the pause button and the tray
+func (t *TrackerApp) pauseReasonItems() (items []*fyne.MenuItem) {
+	for _, reason := range PauseReasons {
+		items = append(items, fyne.NewMenuItem(t.pauseReasonLabel(reason), func() { t.pauseTracking(reason) }))
+	}
+	return items
+}
+
+// pauseReasonLabel is reason as shown on screen, capitalized when the catalogs don't know it
+func (t *TrackerApp) pauseReasonLabel(reason string) string {
+	if reason == "" {
+		return reason
+	}
+	label := strings.ToUpper(reason[:1]) + reason[1:]
+	if !t.Locale.Has("PauseReason" + label) {
+		return label
+	}
+	return t.Locale.T("PauseReason" + label)
+}
+
+// pauseDuration is how long the current pause has lasted (0 when not paused)
+func (t *TrackerApp) pauseDuration(now time.Time) time.Duration {
+	t.Mutex.Lock()
+	defer t.Mutex.Unlock()
+	if !t.IsPaused {
+		return 0
+	}
+	return now.Sub(t.PauseStart)
+}
@@ -0,0 +1,175 @@
+package trackerapp
+
+import (
+	"errors"
+	"maps"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/settings"
+	"work-tracker/src/pkg/store"
+	"work-tracker/src/pkg/util"
+)
+
+/*
+Profiles keep separate work dirs, task lists, targets and report recipients
+(see settings.Profile). Switching stops the running task first, so its last
+chunk goes to the old work dir with stop_reason "profile", then the tracker
+reopens today's file in the new work dir. The choice is saved as active_profile.
+*/
+
+// switchProfile stops tracking and moves the tracker to the named profile.
+func (t *TrackerApp) switchProfile(name string) (e *xerr.Error) {
+	t.Mutex.Lock()
+	activeProfile := t.Settings.ActiveProfile
+	t.Mutex.Unlock()
+	if name == settings.DefaultProfile {
+		name = ""
+	}
+	if name == activeProfile {
+		return nil
+	}
+	tl.Log(tl.Info, palette.Blue, "%s. Previous: '%s', New: '%s'", "Switching profile", settings.ProfileLabel(activeProfile), settings.ProfileLabel(name))
+
+	// everything that can fail comes before stopping, so a bad profile leaves tracking alone
+	saved, e := settings.Load(t.SettingsPath)
+	if e != nil {
+		return e
+	}
+	profiled, e := saved.WithProfile(name)
+	if e != nil {
+		return e
+	}
+	tasks, e := loadTasks(profiled.TasksPath)
+	if e != nil {
+		return e
+	}
+	layout, e := profiled.Layout()
+	if e != nil {
+		return e
+	}
+
+	t.Mutex.Lock()
+	if t.IsRunning {
+		t.StopReason = StopReasonProfile // picked up by the final flush
+	}
+	t.Mutex.Unlock()
+	t.stopTracking() // also ends a pause, in the old work dir
+
+	t.Mutex.Lock()
+	if t.IsRunning {
+		t.Mutex.Unlock()
+		return xerr.NewErrorECOL(errors.New("still running"), "Tracking was restarted while switching profiles", "profile", settings.ProfileLabel(name))
+	}
+	// the profile's values over the current settings, which keep their command line overrides
+	next := t.Settings
+	next.ActiveProfile = profiled.ActiveProfile
+	next.WorkDir = profiled.WorkDir
+	next.DayFileLayout = profiled.DayFileLayout
+	next.TasksPath = profiled.TasksPath
+	next.DailyTarget = profiled.DailyTarget
+	next.Report.OutputPath = profiled.Report.OutputPath
+	next.Report.Recipients = profiled.Report.Recipients
+	t.Settings = next
+	t.Tasks = tasks
+	t.LastAction = nil // undo would rewrite the other profile's file
+	t.lockedRun = nil
+	e = t.openDayLocked(profiled.WorkDir, layout, time.Now())
+	t.Mutex.Unlock()
+	if e != nil {
+		return e
+	}
+
+	// the active profile is the only thing saved, command line overrides stay out of the file
+	e = settings.Update(t.SettingsPath, func(saved *settings.Settings) {
+		saved.ActiveProfile = profiled.ActiveProfile
+	})
+	if e != nil {
+		tl.Log(tl.Warning, palette.Yellow, "%s: %s", "Active profile not saved", e.Msg)
+	}
+
+	fyne.Do(func() {
+		t.rebuildTasksUI()
+		t.hideTaskDetail()  // it shows a task of the previous profile
+		t.applyWindowMode() // rebuilds the main menu
+		t.refreshTrayProfiles()
+	})
+	t.afterTrackingChanged("")
+	tl.Log(tl.Info1, palette.Green, "%s '%s'. Work dir: '%s', tasks: '%s'", "Switched to profile", settings.ProfileLabel(name), profiled.WorkDir, profiled.TasksPath)
+	return nil
+}
+
+/*
+openDayLocked points the tracker at today's file in workDir, laid out by layout,
+and takes its totals as the baseline. Caller holds t.Mutex (or nothing else runs yet) and tracking is stopped,
+or rolls the day over and has flushed the open chunk.
+*/
+func (t *TrackerApp) openDayLocked(workDir string, layout *store.Layout, now time.Time) (e *xerr.Error) {
+	t.Workdir = workDir
+	t.Layout = layout
+	t.CurrentYear, t.CurrentMonth, t.CurrentDay = dateID(now)
+	t.CurrentDate = startOfDay(now)
+	t.CurrentDirPath, t.CurrentFilePath = layout.DayFilePath(t.Workdir, now)
+	e = util.EnsureDirExists(t.CurrentDirPath, 0755)
+	if e != nil {
+		return e
+	}
+
+	// get information about total duration and active time
+	tl.Log(tl.Notice, palette.Blue, "Reading %s and %s from '%s'", "activity", "duration", t.CurrentFilePath)
+	chunks, e := t.readDayLocked()
+	if e != nil {
+		return e
+	}
+	t.WorkedToday, t.ActiveToday, t.TimeByTask = sumChunks(chunks)
+	tl.Log(tl.Notice, palette.Green, "Computed totals for '%s'", t.CurrentFilePath)
+	t.dayFileStamp = statDayFile(t.CurrentFilePath)
+	t.todayStats = dayStatsFromChunks(chunks)
+	t.activitySamples = activitySamplesFromChunks(chunks, now)
+	t.taskHistory = loadTaskHistory(workDir, layout, now)
+	t.suggestedTime = suggestedTimeFromChunks(chunks)
+	t.WorkedTodayBeforeStartingThisRun = t.WorkedToday
+	t.TimeByTaskBeforeStartingThisRun = maps.Clone(t.TimeByTask)
+	t.ActiveDuringThisChunk = 0
+	t.RecentTasks = recentTasksFromTotals(t.TimeByTask)
+	return nil
+}
+
+// profileItems lists the profiles with the active one checked, shared by the tray and the main menu
+func (t *TrackerApp) profileItems() (items []*fyne.MenuItem) {
+	current := t.settingsSnapshot()
+	active := settings.ProfileLabel(current.ActiveProfile)
+	for _, name := range current.ProfileNames() {
+		item := fyne.NewMenuItem(name, func() { t.switchProfileFromUI(name) })
+		item.Checked = name == active
+		items = append(items, item)
+	}
+	return items
+}
+
+// refreshTrayProfiles re-checks the tray's profile submenu. Must run on the UI goroutine.
+func (t *TrackerApp) refreshTrayProfiles() {
+	if t.TrayProfileItem == nil {
+		return
+	}
+	t.TrayProfileItem.ChildMenu.Items = t.profileItems()
+	t.TrayMenu.Refresh()
+}
+
+// switchProfileFromUI runs switchProfile off the UI goroutine and reports failures in the main window
+func (t *TrackerApp) switchProfileFromUI(name string) {
+	go func() {
+		e := t.switchProfile(name)
+		if e != nil {
+			fyne.Do(func() {
+				t.showWindow()
+				showError(e, t.Window)
+			})
+		}
+	}()
+}
@@ -0,0 +1,286 @@
+package trackerapp
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/email"
+	"work-tracker/src/pkg/report"
+	"work-tracker/src/pkg/settings"
+	"work-tracker/src/pkg/store"
+	"work-tracker/src/pkg/util"
+)
+
+const customPresetLabel = "custom"
+
+// where Preview renders the report, apart from the output path that Save, Open and Send write
+var reportPreviewPath = filepath.Join(os.TempDir(), "work-tracker-report-preview.html")
+
+/*
+showReportsWindow opens (or focuses) the window that builds, previews, opens
+and emails reports without leaving the tracker.
+It calls report.BuildReport and email.SendMessage directly, same as the CLIs do.
+*/
+func (t *TrackerApp) showReportsWindow() {
+	if t.ReportsWindow != nil {
+		t.ReportsWindow.Show()
+		t.ReportsWindow.RequestFocus()
+		return
+	}
+	tl.Log(tl.Info, palette.Blue, "%s", "Opening reports window")
+
+	l := t.Locale
+	w := t.App.NewWindow(l.T("ReportsTitle"))
+	w.Resize(fyne.NewSize(900, 700))
+	w.SetOnClosed(func() { t.ReportsWindow = nil })
+	t.ReportsWindow = w
+
+	options := t.settingsSnapshot().Report // for the initial values, actions read it again
+
+	// period
+	presetNames := make([]string, 0, len(report.AllPresets)+1)
+	for _, preset := range report.AllPresets {
+		presetNames = append(presetNames, string(preset))
+	}
+	presetNames = append(presetNames, customPresetLabel)
+	startEntry := widget.NewEntry()
+	startEntry.SetPlaceHolder("DD-MM-YYYY")
+	endEntry := widget.NewEntry()
+	endEntry.SetPlaceHolder("DD-MM-YYYY")
+	presetSelect := widget.NewSelect(presetNames, func(selected string) {
+		if selected == customPresetLabel {
+			startEntry.Enable()
+			endEntry.Enable()
+			return
+		}
+		startDate, endDate, err := report.PresetRange(report.Preset(selected), nowIn(t.settingsSnapshot().Report.Timezone), l.FirstWeekday)
+		if err != nil {
+			return
+		}
+		startEntry.SetText(startDate.Format("02-01-2006"))
+		endEntry.SetText(endDate.Format("02-01-2006"))
+		startEntry.Disable()
+		endEntry.Disable()
+	})
+
+	outputEntry := widget.NewEntry()
+	outputEntry.SetText(options.OutputPath)
+
+	// email
+	providerNames := make([]string, len(email.AllowedProviders))
+	for i, provider := range email.AllowedProviders {
+		providerNames[i] = string(provider)
+	}
+	providerSelect := widget.NewSelect(providerNames, nil)
+	providerSelect.SetSelected(options.Provider)
+	senderEntry := widget.NewEntry()
+	senderEntry.SetPlaceHolder("sender@example.com")
+	senderEntry.SetText(options.Sender)
+	recipientsEntry := widget.NewEntry()
+	recipientsEntry.SetPlaceHolder("one@example.com,two@example.com")
+	recipientsEntry.SetText(strings.Join(options.Recipients, ","))
+
+	// preview, the HTML report itself rendered to a file of its own and opened in the browser
+	previewLabel := widget.NewLabelWithStyle(l.T("ReportsPreviewNone"), fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+	previewLink := widget.NewHyperlink("", nil)
+	previewLink.Hide()
+	statusLabel := widget.NewLabel("")
+
+	// the form's values, the work dir and the report settings, read on the UI goroutine before an action starts
+	type reportForm struct {
+		Start, End, OutputPath string
+		WorkDir                string
+		Layout                 *store.Layout
+		Options                settings.ReportDefaults
+	}
+	readForm := func() reportForm {
+		t.Mutex.Lock()
+		workDir, layout, options := t.Workdir, t.Layout, t.Settings.Report // switchProfile and Settings change them
+		t.Mutex.Unlock()
+		return reportForm{
+			Start:      strings.TrimSpace(startEntry.Text),
+			End:        strings.TrimSpace(endEntry.Text),
+			OutputPath: strings.TrimSpace(outputEntry.Text),
+			WorkDir:    workDir,
+			Layout:     layout,
+			Options:    options,
+		}
+	}
+
+	// resolves the dates in form
+	resolveRange := func(form reportForm) (startDate, endDate time.Time, e *xerr.Error) {
+		_, startDate, endDate, e = report.ResolveRange(form.Options.Timezone, form.Start, form.End, l.FirstWeekday)
+		if e != nil {
+			return startDate, endDate, e
+		}
+		if endDate.Before(startDate) {
+			return startDate, endDate, xerr.NewError(errors.New("end date is before start date"), "invalid report range", map[string]any{
+				"start": form.Start,
+				"end":   form.End,
+			})
+		}
+		return startDate, endDate, nil
+	}
+
+	// runs work off the UI goroutine and reports the outcome in the window
+	var buttons []*widget.Button
+	runAction := func(name string, action func() (status string, e *xerr.Error)) {
+		for _, button := range buttons {
+			button.Disable()
+		}
+		statusLabel.SetText(l.T("ReportsRunning", "Action", name))
+		go func() {
+			status, e := action()
+			fyne.Do(func() {
+				for _, button := range buttons {
+					button.Enable()
+				}
+				if e != nil {
+					statusLabel.SetText(l.T("ReportsFailed", "Action", name))
+					showError(e, w)
+					return
+				}
+				statusLabel.SetText(status)
+			})
+		}()
+	}
+
+	buildReport := func(form reportForm) (outPath string, startDate, endDate time.Time, e *xerr.Error) {
+		startDate, endDate, e = resolveRange(form)
+		if e != nil {
+			return "", startDate, endDate, e
+		}
+		// make sure the running chunk is on disk before reading today's file
+		t.flushChunkIfRunning()
+		outPath = form.OutputPath
+		e = report.BuildReport(form.WorkDir, form.Layout, startDate, endDate, outPath, form.Options.BarRef.Duration, form.Options.Smooth, l)
+		return outPath, startDate, endDate, e
+	}
+
+	previewButton := widget.NewButton(l.T("ReportsPreview"), func() {
+		form := readForm()
+		form.OutputPath = reportPreviewPath // the saved report stays as it is
+		runAction(l.T("ReportsPreview"), func() (string, *xerr.Error) {
+			outPath, startDate, endDate, e := buildReport(form)
+			if e != nil {
+				return "", e
+			}
+			e = util.OpenInChrome(outPath)
+			if e != nil {
+				return "", e
+			}
+			title := report.Title(startDate, endDate, l)
+			fyne.Do(func() {
+				previewLabel.SetText(l.T("ReportsPreviewOf", "Title", title))
+				previewLink.SetText(outPath)
+				previewLink.SetURL(&url.URL{Scheme: "file", Path: filepath.ToSlash(outPath)})
+				previewLink.Show()
+			})
+			return l.T("ReportsPreviewUpdated"), nil
+		})
+	})
+	saveButton := widget.NewButton(l.T("Save"), func() {
+		form := readForm()
+		runAction(l.T("Save"), func() (string, *xerr.Error) {
+			outPath, _, _, e := buildReport(form)
+			if e != nil {
+				return "", e
+			}
+			return l.T("ReportsSaved", "Path", outPath), nil
+		})
+	})
+	openButton := widget.NewButton(l.T("ReportsOpen"), func() {
+		form := readForm()
+		runAction(l.T("ReportsOpen"), func() (string, *xerr.Error) {
+			outPath, _, _, e := buildReport(form)
+			if e != nil {
+				return "", e
+			}
+			e = util.OpenInChrome(outPath)
+			if e != nil {
+				return "", e
+			}
+			return l.T("ReportsOpened", "Path", outPath), nil
+		})
+	})
+	sendButton := widget.NewButton(l.T("ReportsSend"), func() {
+		provider := email.Provider(providerSelect.Selected)
+		sender := strings.TrimSpace(senderEntry.Text)
+		recipients := splitRecipients(recipientsEntry.Text)
+		form := readForm()
+		runAction(l.T("ReportsSend"), func() (string, *xerr.Error) {
+			if sender == "" || len(recipients) == 0 {
+				return "", xerr.NewError(errors.New("sender and recipients are required"), "unable to send report", nil)
+			}
+			e := email.CheckProviderEnv(provider)
+			if e != nil {
+				return "", e
+			}
+			outPath, startDate, endDate, e := buildReport(form)
+			if e != nil {
+				return "", e
+			}
+			htmlBytes, err := os.ReadFile(outPath)
+			if err != nil {
+				return "", xerr.NewErrorECOL(err, "unable to read report", "path", outPath)
+			}
+			subject := report.EmailSubject(report.Title(startDate, endDate, l), time.Now(), l)
+			sendEmails := true
+			e = email.SendMessage(provider, &sendEmails, sender, recipients, subject, "", string(htmlBytes), nil)
+			if e != nil {
+				return "", e
+			}
+			return l.T("ReportsSent", "Recipients", strings.Join(recipients, ", "), "Provider", provider), nil
+		})
+	})
+	sendButton.Importance = widget.HighImportance
+	buttons = []*widget.Button{previewButton, saveButton, openButton, sendButton}
+
+	form := widget.NewForm(
+		widget.NewFormItem(l.T("ReportsPeriod"), presetSelect),
+		widget.NewFormItem(l.T("ReportsStart"), startEntry),
+		widget.NewFormItem(l.T("ReportsEnd"), endEntry),
+		widget.NewFormItem(l.T("ReportsOutput"), outputEntry),
+		widget.NewFormItem(l.T("ReportsProvider"), providerSelect),
+		widget.NewFormItem(l.T("ReportsSender"), senderEntry),
+		widget.NewFormItem(l.T("ReportsRecipients"), recipientsEntry),
+	)
+	actions := container.NewHBox(previewButton, saveButton, openButton, layout.NewSpacer(), statusLabel, layout.NewSpacer(), sendButton)
+	preview := container.NewHBox(previewLabel, previewLink)
+	w.SetContent(container.NewVBox(form, actions, widget.NewSeparator(), preview))
+
+	presetSelect.SetSelected(options.Preset)
+	w.Show()
+}
+
+// current time in the report timezone, falls back to local time for unknown zones
+func nowIn(timezone string) time.Time {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Now()
+	}
+	return time.Now().In(loc)
+}
+
+func splitRecipients(text string) (recipients []string) {
+	for _, recipient := range strings.Split(text, ",") {
+		recipient = strings.TrimSpace(recipient)
+		if recipient != "" {
+			recipients = append(recipients, recipient)
+		}
+	}
+	return recipients
+}
@@ -0,0 +1,244 @@
+package trackerapp
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/store"
+)
+
+/*
+Retroactive actions fix the day after the fact: start as of a past time,
+switch task as of a past time, and undo the last start/stop/switch.
+
+Anything that changes already written chunks flushes first, rewrites the day
+file and rebases the in-memory totals on it (see rebaseLocked), so the file,
+WorkedToday and TimeByTask never disagree.
+*/
+
+// how long after a start/stop/switch it can still be undone
+const undoGracePeriod = 10 * time.Minute
+
+/*
+startAsOf starts taskName as if Start had been pressed at at.
+
+at is moved forward when it would overlap time already in the day file
+or the current pause, or reach into yesterday. Returns the time actually used.
+*/
+func (t *TrackerApp) startAsOf(taskName string, at time.Time) (startedAt time.Time, e *xerr.Error) {
+	t.Mutex.Lock()
+	isRunning := t.IsRunning
+	reader, today := store.NewReader(t.Workdir, t.Layout, store.Strict), t.CurrentDate
+	var pauseStart time.Time // not written yet, so it doesn't show up in lastChunkEnd
+	if t.IsPaused {
+		pauseStart = t.PauseStart
+	}
+	t.Mutex.Unlock()
+	if isRunning {
+		return at, xerr.NewErrorECOL(errors.New("already running"), "Stop tracking before starting retroactively", "task name", taskName)
+	}
+
+	chunks, e := reader.ReadDay(today)
+	if e != nil {
+		return at, e
+	}
+	now := time.Now()
+	startedAt = latest(at, startOfDay(now), lastChunkEnd(chunks), pauseStart)
+	if !startedAt.Before(now) {
+		startedAt = now
+	}
+	if !startedAt.Equal(at) {
+		tl.Log(tl.Info, palette.Cyan, "%s from %s to %s", "Moved retroactive start", at.Format(time.TimeOnly), startedAt.Format(time.TimeOnly))
+	}
+
+	t.startTaskAt(taskName, startedAt)
+	return startedAt, nil
+}
+
+/*
+switchAsOf gives everything tracked since at to taskName and keeps it running.
+at is clamped to the current run, so it never takes time from an earlier run.
+Returns the time actually used.
+*/
+func (t *TrackerApp) switchAsOf(taskName string, at time.Time) (switchedAt time.Time, e *xerr.Error) {
+	t.refreshActivityState()
+	t.refreshUIState()
+
+	t.Mutex.Lock()
+	if !t.IsRunning {
+		t.Mutex.Unlock()
+		return at, xerr.NewErrorECOL(errors.New("not running"), "Start tracking before switching retroactively", "task name", taskName)
+	}
+	now := time.Now()
+	switchedAt = latest(at, t.SessionStart)
+	if switchedAt.After(now) {
+		switchedAt = now
+	}
+	previousTaskName := t.CurrentTaskName
+	tl.Log(tl.Info, palette.Cyan, "%s. Previous: '%s', New: '%s', as of: %s", "Switching tasks", previousTaskName, taskName, switchedAt.Format(time.TimeOnly))
+
+	e = t.rewriteDayLocked(now, func(chunks []store.Chunk) []store.Chunk {
+		return reassignChunksSince(chunks, switchedAt, taskName)
+	})
+	if e == nil {
+		t.CurrentTaskName = taskName
+		t.SuggestedTask = t.suggestionForLocked(taskName, now)
+		t.LastAction = &trackerAction{Kind: actionSwitch, At: switchedAt, PerformedAt: now, TaskName: taskName, PreviousTaskName: previousTaskName}
+	}
+	t.Mutex.Unlock()
+	if e != nil {
+		return switchedAt, e
+	}
+
+	t.afterTrackingChanged(taskName)
+	return switchedAt, nil
+}
+
+// undoableAction returns the last action if it's still within the grace period
+func (t *TrackerApp) undoableAction() *trackerAction {
+	t.Mutex.Lock()
+	defer t.Mutex.Unlock()
+	if t.LastAction == nil || time.Since(t.LastAction.PerformedAt) > undoGracePeriod {
+		return nil
+	}
+	return t.LastAction
+}
+
+/*
+undoLastAction reverts the last start/stop/switch:
+
+  - start:  the run is discarded, as if Start was never pressed
+  - stop:   tracking resumes and the time since stopping is counted
+  - switch: the time since the switch goes back to the previous task
+
+An undo can't be undone.
+*/
+func (t *TrackerApp) undoLastAction() (e *xerr.Error) {
+	action := t.undoableAction()
+	if action == nil {
+		return xerr.NewErrorECOL(errors.New("nothing to undo"), fmt.Sprintf("Nothing to undo in the last %s", undoGracePeriod), "grace period", undoGracePeriod.String())
+	}
+	tl.Log(tl.Info, palette.Cyan, "%s '%s'. Task name: '%s', at: %s", "Undoing", action.Kind, action.TaskName, action.At.Format(time.TimeOnly))
+
+	switch action.Kind {
+	case actionStart:
+		e = t.discardRun()
+	case actionStop:
+		_, e = t.startAsOf(action.TaskName, action.At)
+	case actionSwitch:
+		_, e = t.switchAsOf(action.PreviousTaskName, action.At)
+	}
+	if e != nil {
+		return e
+	}
+
+	t.Mutex.Lock()
+	t.LastAction = nil
+	t.Mutex.Unlock()
+	t.updateInterface()
+	t.updateTray()
+	return nil
+}
+
+// undoFromUI runs undoLastAction off the UI goroutine and reports failures in the main window
+func (t *TrackerApp) undoFromUI() {
+	go func() {
+		e := t.undoLastAction()
+		if e != nil {
+			fyne.Do(func() { showError(e, t.Window) })
+		}
+	}()
+}
+
+// undoLabel is the menu label for undoing action ("Undo" alone when there's nothing to undo)
+func (t *TrackerApp) undoLabel(action *trackerAction) string {
+	if action == nil {
+		return t.Locale.T("Undo")
+	}
+	taskName := action.TaskName
+	if taskName == "" {
+		taskName = t.Locale.T("Unassigned")
+	}
+	return t.Locale.T("Undo"+string(action.Kind), "Task", taskName)
+}
+
+/*
+discardRun stops tracking and removes everything the current run wrote, with the
+same cleanup as a stop: the focus session ends and the note, the reasons and the
+suggestion are cleared.
+*/
+func (t *TrackerApp) discardRun() (e *xerr.Error) {
+	t.Mutex.Lock()
+	isRunning, taskName := t.IsRunning, t.CurrentTaskName
+	t.Mutex.Unlock()
+	if !isRunning {
+		return xerr.NewErrorECOL(errors.New("not running"), "The run to undo is already stopped", "task name", taskName)
+	}
+
+	t.endFocus() // its record falls within the run, so it's dropped below
+	t.Mutex.Lock()
+	e = t.discardRunLocked(time.Now())
+	t.Mutex.Unlock()
+	if e != nil {
+		return e
+	}
+	t.clearNote()
+	t.afterTrackingChanged("")
+	return nil
+}
+
+// discardRunLocked resets the run state and drops the chunks written since the session started. Caller holds t.Mutex.
+func (t *TrackerApp) discardRunLocked(now time.Time) (e *xerr.Error) {
+	sessionStart := t.SessionStart
+	t.IsRunning = false
+	t.CurrentTaskName = ""
+	t.SuggestedTask = ""
+	t.noteBlockStart = time.Time{}
+	t.StartReason, t.StopReason = "", ""
+	t.LastTickActiveDuration = 0
+	// stopped, so nothing gets flushed: the open chunk is dropped along with the written ones
+	return t.rewriteDayLocked(now, func(chunks []store.Chunk) []store.Chunk {
+		return dropChunksSince(chunks, sessionStart)
+	})
+}
+
+// rewriteDayLocked flushes, applies edit to the day file and rebases totals on it. Caller holds t.Mutex.
+func (t *TrackerApp) rewriteDayLocked(now time.Time, edit func([]store.Chunk) []store.Chunk) (e *xerr.Error) {
+	t.flushChunkLocked(now)
+	chunks, e := t.readDayLocked()
+	if e != nil {
+		return e
+	}
+	return t.writeDayLocked(now, edit(chunks))
+}
+
+// writeDayLocked replaces the day file with chunks and rebases totals on it. Caller holds t.Mutex and has flushed.
+func (t *TrackerApp) writeDayLocked(now time.Time, chunks []store.Chunk) (e *xerr.Error) {
+	e = store.NewWriter(t.Workdir, t.Layout).RewriteDay(t.CurrentDate, chunks)
+	if e != nil {
+		return e
+	}
+	t.stampDayFileLocked()
+	return t.rebaseLocked(now)
+}
+
+func startOfDay(now time.Time) time.Time {
+	year, month, day := now.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+}
+
+func latest(first time.Time, rest ...time.Time) time.Time {
+	for _, candidate := range rest {
+		if candidate.After(first) {
+			first = candidate
+		}
+	}
+	return first
+}
@@ -0,0 +1,120 @@
+package trackerapp
+
+import (
+	"time"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+)
+
+/*
+One goroutine runs every periodic job (UI, activity, flush, day file check, day change, focus countdown) off a single timer,
+sleeping until the earliest one is due.
+
+The cadence follows what is actually needed:
+
+  - hidden window: widgets are not rendered, only the tray is kept up to date, and less often
+  - not running:   activity is polled slowly, it only feeds the "active but not tracking" reminder
+
+wakeScheduler makes it re-plan right away, so showing the window or pressing
+Start catches up without waiting for a slow tick.
+*/
+
+const (
+	hiddenUITickInterval        = 15 * time.Second // tray labels have minute precision
+	stoppedActivityTickInterval = 10 * time.Second
+)
+
+func (t *TrackerApp) schedulerLoop() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	var lastUI, lastActivity, lastFlush, lastDayFileCheck time.Time // zero, so everything runs once right away
+	for {
+		select {
+		case <-timer.C:
+		case <-t.wake:
+		case <-t.done:
+			return
+		}
+
+		now := time.Now()
+		uiInterval, activityInterval, flushInterval, windowVisible := t.tickIntervals()
+
+		t.checkDayChange(now) // before anything writes, so nothing after midnight goes to the previous day
+		// activity before flush, so the chunk gets the latest active time
+		if !now.Before(lastActivity.Add(activityInterval)) {
+			t.refreshActivityState()
+			t.checkNotifications(now)
+			t.checkSchedule(now)
+			lastActivity = now
+		}
+		if !now.Before(lastFlush.Add(flushInterval)) {
+			t.flushChunkIfRunning()
+			lastFlush = now
+		}
+		if !now.Before(lastDayFileCheck.Add(dayFileCheckInterval)) {
+			t.checkDayFile(now) // after the flush, so the tracker's own writes are stamped
+			lastDayFileCheck = now
+		}
+		t.checkFocus(now) // before the UI, so the countdown shows the next phase
+		if !now.Before(lastUI.Add(uiInterval)) {
+			t.refreshUIState()
+			if windowVisible {
+				t.updateInterface()
+			}
+			t.updateTray()
+			lastUI = now
+		}
+
+		next := earliest(lastUI.Add(uiInterval), lastActivity.Add(activityInterval), lastFlush.Add(flushInterval), lastDayFileCheck.Add(dayFileCheckInterval))
+		if focusDeadline := t.focusDeadline(); !focusDeadline.IsZero() {
+			next = earliest(next, focusDeadline)
+		}
+		timer.Reset(time.Until(next))
+	}
+}
+
+// tickIntervals is how often each job should run in the current state
+func (t *TrackerApp) tickIntervals() (ui, activity, flush time.Duration, windowVisible bool) {
+	t.Mutex.Lock()
+	defer t.Mutex.Unlock()
+
+	ui, activity, flush = t.UITickInterval, t.ActivityTickInterval, t.FlushTickInterval
+	if !t.WindowVisible {
+		ui = max(ui, hiddenUITickInterval)
+	}
+	if !t.IsRunning {
+		activity = max(activity, stoppedActivityTickInterval)
+	}
+	return ui, activity, flush, t.WindowVisible
+}
+
+// wakeScheduler makes the scheduler re-plan now. Never blocks.
+func (t *TrackerApp) wakeScheduler() {
+	select {
+	case t.wake <- struct{}{}:
+	default: // a wake-up is already pending
+	}
+}
+
+// setWindowVisible records whether the main window is shown and lets the scheduler adapt
+func (t *TrackerApp) setWindowVisible(visible bool) {
+	t.Mutex.Lock()
+	changed := t.WindowVisible != visible
+	t.WindowVisible = visible
+	t.Mutex.Unlock()
+	if changed {
+		tl.Log(tl.Detailed, palette.Cyan, "%s. Visible: %v", "Window visibility changed", visible)
+		t.wakeScheduler()
+	}
+}
+
+func earliest(first time.Time, rest ...time.Time) time.Time {
+	for _, candidate := range rest {
+		if candidate.Before(first) {
+			first = candidate
+		}
+	}
+	return first
+}
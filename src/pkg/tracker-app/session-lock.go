@@ -0,0 +1,207 @@
+package trackerapp
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+
+	"work-tracker/src/pkg/locale"
+	"work-tracker/src/pkg/session"
+	"work-tracker/src/pkg/settings"
+	"work-tracker/src/pkg/store"
+)
+
+/*
+Screen lock, user switch and suspend stop or pause the running task
+(Settings.Lock.Action). The stopped chunk carries the reason as its stop_reason,
+a pause is recorded with the "away" reason. On unlock the tracker offers to pick
+the task up again; accepting starts a run with start_reason "unlock". Otherwise
+the time away ends up as an "away" pause whose stop_reason says why it wasn't
+resumed: "declined", or "unlock" when resuming isn't offered.
+*/
+
+// lockedRun is what a lock interrupted, kept until the unlock offer is answered
+type lockedRun struct {
+	TaskName string
+	Reason   string // session.Reason*
+	LockedAt time.Time
+	Paused   bool // paused rather than stopped
+}
+
+// watchSession follows session events until the app quits. Without D-Bus there's nothing to watch.
+func (t *TrackerApp) watchSession() {
+	system, sessionBus, e := session.Connect()
+	if e != nil {
+		tl.Log(tl.Warning, palette.Yellow, "%s: %s", "Screen lock won't affect tracking", e.Msg)
+		return
+	}
+	events, e := session.Watch(system, sessionBus)
+	if e != nil {
+		tl.Log(tl.Warning, palette.Yellow, "%s: %s", "Screen lock won't affect tracking", e.Msg)
+		return
+	}
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if event.Locked {
+				t.onSessionLocked(event.Reason)
+			} else {
+				t.onSessionUnlocked(event.Reason)
+			}
+		case <-t.done:
+			return
+		}
+	}
+}
+
+// onSessionLocked applies the lock action to a running task
+func (t *TrackerApp) onSessionLocked(reason string) {
+	t.Mutex.Lock()
+	action := t.Settings.Lock.Action
+	isRunning := t.IsRunning
+	taskName := t.CurrentTaskName
+	t.Mutex.Unlock()
+	tl.Log(tl.Info, palette.Cyan, "%s. Reason: '%s', action: '%s', running: %v", "Session locked", reason, action, isRunning)
+	if !isRunning || action == settings.LockActionIgnore {
+		return // nothing to stop, or told not to
+	}
+
+	t.Mutex.Lock()
+	t.StopReason = reason // picked up by the final flush
+	t.lockedRun = &lockedRun{TaskName: taskName, Reason: reason, LockedAt: time.Now(), Paused: action == settings.LockActionPause}
+	t.Mutex.Unlock()
+	if action == settings.LockActionPause {
+		t.pauseTracking(PauseReasonAway)
+	} else {
+		t.stopTracking()
+	}
+}
+
+// onSessionUnlocked offers to resume what the lock interrupted (once, duplicates are ignored)
+func (t *TrackerApp) onSessionUnlocked(reason string) {
+	t.Mutex.Lock()
+	locked := t.lockedRun
+	t.lockedRun = nil
+	isRunning := t.IsRunning
+	offerResume := t.Settings.Lock.OfferResume
+	t.Mutex.Unlock()
+	tl.Log(tl.Info, palette.Cyan, "%s. Reason: '%s'", "Session unlocked", reason)
+	if locked == nil || isRunning {
+		return // the lock didn't interrupt anything, or tracking was already started again
+	}
+	if !offerResume {
+		t.declineResume(locked, StopReasonUnlock)
+		return
+	}
+
+	l := t.Locale
+	taskName := locked.TaskName
+	if taskName == "" {
+		taskName = l.T("UnassignedTask")
+	}
+	messageID := "LockResumeStopped"
+	if locked.Paused {
+		messageID = "LockResumePaused"
+	}
+	message := l.T(messageID, "Task", taskName, "Time", l.Clock(locked.LockedAt), "Reason", lockReasonLabel(locked.Reason, l))
+	fyne.Do(func() {
+		t.showWindow()
+		dialog.ShowConfirm(l.T("LockResumeTitle"), message, func(resume bool) {
+			go func() {
+				if resume {
+					t.resumeLockedRun(locked)
+				} else {
+					t.declineResume(locked, StopReasonDeclined)
+				}
+			}()
+		}, t.Window)
+	})
+}
+
+// resumeLockedRun starts the interrupted task again, marking the run as resumed on unlock
+func (t *TrackerApp) resumeLockedRun(locked *lockedRun) {
+	t.Mutex.Lock()
+	if t.IsRunning {
+		t.Mutex.Unlock()
+		return // started by hand while the offer was open
+	}
+	t.StartReason = StartReasonUnlock
+	isPaused := t.IsPaused
+	t.Mutex.Unlock()
+
+	tl.Log(tl.Info, palette.Cyan, "%s. Task name: '%s'", "Resuming after unlock", locked.TaskName)
+	if isPaused {
+		t.resumeTracking() // ends the away pause now
+	} else {
+		t.startTask(locked.TaskName)
+	}
+}
+
+/*
+declineResume leaves the interrupted task stopped and records stopReason on the
+time away: the away pause ends with it, or after a stop a pause from the lock
+until now is written with it. Nothing is written once tracking was started again.
+*/
+func (t *TrackerApp) declineResume(locked *lockedRun, stopReason string) {
+	message := "Not resuming after unlock"
+	if stopReason == StopReasonUnlock {
+		message = "Resuming after unlock isn't offered"
+	}
+	tl.Log(tl.Info, palette.Cyan, "%s. Task name: '%s'", message, locked.TaskName)
+	now := time.Now()
+	t.Mutex.Lock()
+	awayPause := t.IsPaused && t.PauseReason == PauseReasonAway
+	switch {
+	case awayPause:
+		t.StopReason = stopReason // picked up by endPauseAt
+	case !t.IsRunning && !t.IsPaused:
+		t.recordAwayLocked(locked, now, stopReason)
+	}
+	t.Mutex.Unlock()
+	if awayPause {
+		t.stopTracking()
+	}
+}
+
+// recordAwayLocked writes the time since a stopping lock as an away pause. Caller holds t.Mutex.
+func (t *TrackerApp) recordAwayLocked(locked *lockedRun, now time.Time, stopReason string) {
+	if !now.After(locked.LockedAt) {
+		return
+	}
+	chunk := store.Chunk{
+		TaskName:    locked.TaskName,
+		StartedAt:   locked.LockedAt.Round(0),
+		FinishedAt:  now.Round(0),
+		Kind:        store.ChunkKindPause,
+		PauseReason: PauseReasonAway,
+		StopReason:  stopReason,
+	}
+	e := store.AppendFile(t.CurrentFilePath, chunk)
+	if e != nil {
+		tl.Log(tl.Error, palette.Red, "Failed to write %s pause: %s", PauseReasonAway, e.Msg)
+		return
+	}
+	t.stampDayFileLocked()
+}
+
+// lockReasonLabel is a session.Reason as shown in the resume dialog, unknown ones as written
+func lockReasonLabel(reason string, l *locale.Locale) string {
+	ids := map[string]string{
+		session.ReasonLock:       "LockReasonLock",
+		session.ReasonSleep:      "LockReasonSleep",
+		session.ReasonSwitchUser: "LockReasonSwitchUser",
+	}
+	id, ok := ids[reason]
+	if !ok {
+		return reason
+	}
+	return l.T(id)
+}
@@ -0,0 +1,87 @@
+package trackerapp
+
+import (
+	"testing"
+
+	"work-tracker/src/pkg/session"
+	"work-tracker/src/pkg/store"
+)
+
+func TestDeclineResumeRecordsTimeAway(t *testing.T) {
+	layout := store.MustParseLayout(store.DefaultLayout)
+	locked := &lockedRun{TaskName: "Code", Reason: session.ReasonLock, LockedAt: at(9, 0)}
+	tests := []struct {
+		name       string
+		paused     bool
+		stopReason string
+	}{
+		{"stopped, declined", false, StopReasonDeclined},
+		{"stopped, not offered", false, StopReasonUnlock},
+		{"paused, declined", true, StopReasonDeclined},
+		{"paused, not offered", true, StopReasonUnlock},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			workDir := t.TempDir()
+			app := &TrackerApp{}
+			app.Mutex.Lock()
+			e := app.openDayLocked(workDir, layout, at(9, 30))
+			app.Mutex.Unlock()
+			if e != nil {
+				t.Fatalf("open: %s", e.Msg)
+			}
+
+			if test.paused {
+				// what declineResume leaves for stopTracking, which ends the pause
+				app.IsPaused, app.PauseReason, app.PauseStart, app.PausedTaskName = true, PauseReasonAway, at(9, 0), "Code"
+				app.Mutex.Lock()
+				app.StopReason = test.stopReason
+				app.Mutex.Unlock()
+				app.endPauseAt(at(9, 30))
+			} else {
+				app.declineResume(locked, test.stopReason)
+			}
+
+			chunks, e := store.NewReader(workDir, layout, store.Strict).ReadDay(testDay)
+			if e != nil {
+				t.Fatalf("read: %s", e.Msg)
+			}
+			if len(chunks) != 1 {
+				t.Fatalf("%v chunks, want the away pause", len(chunks))
+			}
+			pause := chunks[0]
+			if pause.Kind != store.ChunkKindPause || pause.PauseReason != PauseReasonAway || pause.TaskName != "Code" || !pause.StartedAt.Equal(at(9, 0)) {
+				t.Errorf("chunk %+v, want an away pause on Code from 9:00", pause)
+			}
+			if pause.StopReason != test.stopReason {
+				t.Errorf("stop_reason %q, want %q", pause.StopReason, test.stopReason)
+			}
+			if app.IsPaused || app.StopReason != "" {
+				t.Errorf("paused %v, stop reason %q left; want neither", app.IsPaused, app.StopReason)
+			}
+		})
+	}
+}
+
+func TestDeclineResumeAfterStartingAgain(t *testing.T) {
+	layout := store.MustParseLayout(store.DefaultLayout)
+	workDir := t.TempDir()
+	app := &TrackerApp{}
+	app.Mutex.Lock()
+	e := app.openDayLocked(workDir, layout, at(9, 30))
+	app.Mutex.Unlock()
+	if e != nil {
+		t.Fatalf("open: %s", e.Msg)
+	}
+	// started by hand while the offer was open
+	app.IsRunning, app.CurrentTaskName = true, "Email"
+
+	app.declineResume(&lockedRun{TaskName: "Code", Reason: session.ReasonLock, LockedAt: at(9, 0)}, StopReasonDeclined)
+	chunks, e := store.NewReader(workDir, layout, store.Strict).ReadDay(testDay)
+	if e != nil {
+		t.Fatalf("read: %s", e.Msg)
+	}
+	if len(chunks) != 0 {
+		t.Errorf("%v chunks written, want none", len(chunks))
+	}
+}
@@ -0,0 +1,421 @@
+package trackerapp
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+
+	"work-tracker/src/pkg/email"
+	"work-tracker/src/pkg/locale"
+	"work-tracker/src/pkg/report"
+	"work-tracker/src/pkg/settings"
+)
+
+/*
+showSettingsWindow opens (or focuses) the window that edits the settings file.
+
+It edits what is saved in t.SettingsPath, not the effective values, so
+command line overrides never get written back to the file.
+*/
+func (t *TrackerApp) showSettingsWindow() {
+	if t.SettingsWindow != nil {
+		t.SettingsWindow.Show()
+		t.SettingsWindow.RequestFocus()
+		return
+	}
+	tl.Log(tl.Info, palette.Blue, "%s '%s'", "Opening settings window for", t.SettingsPath)
+
+	saved, e := settings.Load(t.SettingsPath)
+	if e != nil {
+		showError(e, t.Window)
+		return
+	}
+
+	l := t.Locale
+	w := t.App.NewWindow(l.T("SettingsTitle"))
+	w.Resize(fyne.NewSize(760, 720))
+	w.SetOnClosed(func() { t.SettingsWindow = nil })
+	t.SettingsWindow = w
+
+	// storage
+	workDirEntry := newEntryWithText(saved.WorkDir)
+	tasksPathEntry := newEntryWithText(saved.TasksPath)
+	// tickers
+	uiTickEntry := newEntryWithText(saved.UITickInterval.String())
+	activityTickEntry := newEntryWithText(saved.ActivityTickInterval.String())
+	flushTickEntry := newEntryWithText(saved.FlushTickInterval.String())
+	// tracking
+	dailyTargetEntry := newEntryWithText(saved.DailyTarget.String())
+	promptNoteCheck := widget.NewCheck(l.T("SettingsPromptNote"), nil)
+	promptNoteCheck.SetChecked(saved.PromptNoteOnStop)
+	// interface
+	themeScaleEntry := newEntryWithText(strconv.FormatFloat(float64(saved.ThemeScale), 'f', 2, 32))
+	themeModeSelect := widget.NewSelect(settings.ThemeModes, nil)
+	themeModeSelect.SetSelected(saved.ThemeMode)
+	accentColorEntry := newEntryWithText(saved.AccentColor)
+	accentColorEntry.SetPlaceHolder(l.T("SettingsAccentColorPlaceholder"))
+	windowWidthEntry := newEntryWithText(strconv.FormatFloat(float64(saved.WindowWidth), 'f', 0, 32))
+	windowHeightEntry := newEntryWithText(strconv.FormatFloat(float64(saved.WindowHeight), 'f', 0, 32))
+	miniWidthEntry := newEntryWithText(strconv.FormatFloat(float64(saved.MiniWidth), 'f', 0, 32))
+	miniHeightEntry := newEntryWithText(strconv.FormatFloat(float64(saved.MiniHeight), 'f', 0, 32))
+	miniAlwaysOnTopCheck := widget.NewCheck(l.T("SettingsMiniOnTopCheck"), nil)
+	miniAlwaysOnTopCheck.SetChecked(saved.MiniAlwaysOnTop)
+	startHiddenCheck := widget.NewCheck(l.T("SettingsStartHidden"), nil)
+	startHiddenCheck.SetChecked(saved.StartHidden)
+	// locale
+	languageEntry := newEntryWithText(saved.Locale.Language)
+	languageEntry.SetPlaceHolder(l.T("SettingsLanguagePlaceholder", "Languages", strings.Join(locale.Languages(), ", ")))
+	timeFormatEntry := newEntryWithText(saved.Locale.TimeFormat)
+	timeFormatEntry.SetPlaceHolder(l.T("SettingsClockPlaceholder"))
+	durationFormatSelect := widget.NewSelect(locale.DurationFormats, nil)
+	durationFormatSelect.SetSelected(saved.Locale.DurationFormat)
+	firstWeekdayEntry := newEntryWithText(saved.Locale.FirstWeekday)
+	firstWeekdayEntry.SetPlaceHolder(l.T("SettingsWeekStartsOnPlaceholder"))
+	// schedule
+	scheduleCheck := widget.NewCheck(l.T("SettingsUseWorkingHours"), nil)
+	scheduleCheck.SetChecked(saved.Schedule.Enabled)
+	weekdayEntries := make([]*widget.Entry, len(settings.Weekdays))
+	for i, day := range settings.Weekdays {
+		weekdayEntries[i] = newEntryWithText(settings.FormatWindows(saved.Schedule.Week[day]))
+		weekdayEntries[i].SetPlaceHolder(l.T("SettingsHoursPlaceholder"))
+	}
+	holidaysEntry := newEntryWithText(strings.Join(saved.Schedule.Holidays, ", "))
+	holidaysEntry.SetPlaceHolder(l.T("SettingsHolidaysPlaceholder"))
+	autoStopCheck := widget.NewCheck(l.T("SettingsAutoStop"), nil)
+	autoStopCheck.SetChecked(saved.Schedule.AutoStop)
+	autoStopIdleEntry := newEntryWithText(saved.Schedule.AutoStopIdle.String())
+	warnOutsideCheck := widget.NewCheck(l.T("SettingsWarnOutside"), nil)
+	warnOutsideCheck.SetChecked(saved.Schedule.WarnOutside)
+	autoStartCheck := widget.NewCheck(l.T("SettingsAutoStartCheck"), nil)
+	autoStartCheck.SetChecked(saved.Schedule.AutoStart)
+	// focus
+	focusWorkEntry := newEntryWithText(saved.Focus.Work.String())
+	shortBreakEntry := newEntryWithText(saved.Focus.ShortBreak.String())
+	longBreakEntry := newEntryWithText(saved.Focus.LongBreak.String())
+	longBreakEveryEntry := newEntryWithText(strconv.Itoa(saved.Focus.LongBreakEvery))
+	longBreakEveryEntry.SetPlaceHolder(l.T("SettingsLongBreakEveryPlaceholder"))
+	autoResumeCheck := widget.NewCheck(l.T("SettingsAutoResume"), nil)
+	autoResumeCheck.SetChecked(saved.Focus.AutoResume)
+	timeboxTexts := make([]string, len(saved.Focus.Timeboxes))
+	for i, timebox := range saved.Focus.Timeboxes {
+		timeboxTexts[i] = timebox.String()
+	}
+	timeboxesEntry := newEntryWithText(strings.Join(timeboxTexts, ", "))
+	timeboxesEntry.SetPlaceHolder(l.T("SettingsTimeboxesPlaceholder"))
+	timeboxActionSelect := widget.NewSelect(settings.TimeboxActions, nil)
+	timeboxActionSelect.SetSelected(saved.Focus.TimeboxAction)
+	switchToEntry := newEntryWithText(saved.Focus.SwitchTo)
+	switchToEntry.SetPlaceHolder(l.T("SettingsSwitchToPlaceholder"))
+	// lock
+	lockActionSelect := widget.NewSelect(settings.LockActions, nil)
+	lockActionSelect.SetSelected(saved.Lock.Action)
+	offerResumeCheck := widget.NewCheck(l.T("SettingsOfferResume"), nil)
+	offerResumeCheck.SetChecked(saved.Lock.OfferResume)
+	// notifications
+	notificationsCheck := widget.NewCheck(l.T("SettingsShowNotifications"), nil)
+	notificationsCheck.SetChecked(saved.Notifications.Enabled)
+	breakAfterEntry := newEntryWithText(saved.Notifications.BreakReminder.After.String())
+	breakRepeatEntry := newEntryWithText(saved.Notifications.BreakReminder.Repeat.String())
+	idleAfterEntry := newEntryWithText(saved.Notifications.IdleWhileRunning.After.String())
+	activeAfterEntry := newEntryWithText(saved.Notifications.ActiveWhileStopped.After.String())
+	workdayEndEntry := newEntryWithText(saved.Notifications.WorkdayEnd)
+	workdayEndEntry.SetPlaceHolder(l.T("SettingsNeverPlaceholder"))
+	workdayEndRepeatEntry := newEntryWithText(saved.Notifications.WorkdayEndRepeat.String())
+	quietFromEntry := newEntryWithText(saved.Notifications.QuietFrom)
+	quietFromEntry.SetPlaceHolder(l.T("SettingsNeverPlaceholder"))
+	quietToEntry := newEntryWithText(saved.Notifications.QuietTo)
+	quietToEntry.SetPlaceHolder("HH:MM")
+	// report
+	presetNames := make([]string, len(report.AllPresets))
+	for i, preset := range report.AllPresets {
+		presetNames[i] = string(preset)
+	}
+	presetSelect := widget.NewSelect(presetNames, nil)
+	presetSelect.SetSelected(saved.Report.Preset)
+	outputPathEntry := newEntryWithText(saved.Report.OutputPath)
+	timezoneEntry := newEntryWithText(saved.Report.Timezone)
+	barRefEntry := newEntryWithText(saved.Report.BarRef.String())
+	smoothEntry := newEntryWithText(strconv.FormatFloat(saved.Report.Smooth, 'f', 2, 64))
+	providerNames := make([]string, len(email.AllowedProviders))
+	for i, provider := range email.AllowedProviders {
+		providerNames[i] = string(provider)
+	}
+	providerSelect := widget.NewSelect(providerNames, nil)
+	providerSelect.SetSelected(saved.Report.Provider)
+	senderEntry := newEntryWithText(saved.Report.Sender)
+	recipientsEntry := newEntryWithText(strings.Join(saved.Report.Recipients, ","))
+
+	// collects the form into a Settings value, parse failures become problems
+	readForm := func() (edited settings.Settings, problems []string) {
+		edited = saved
+		current := t.settingsSnapshot()
+		edited.TaskTable = current.TaskTable           // changed from the table while this window was open
+		edited.ActivityWindow = current.ActivityWindow // and this by clicking the activity bar
+		parseDuration := func(name, text string) settings.Duration {
+			d, err := time.ParseDuration(strings.TrimSpace(text))
+			if err != nil {
+				problems = append(problems, l.T("SettingsNotADuration", "Setting", name, "Text", text))
+			}
+			return settings.Duration{Duration: d}
+		}
+		parseFloat := func(name, text string) float64 {
+			f, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+			if err != nil {
+				problems = append(problems, l.T("SettingsNotANumber", "Setting", name, "Text", text))
+			}
+			return f
+		}
+
+		edited.WorkDir = strings.TrimSpace(workDirEntry.Text)
+		edited.TasksPath = strings.TrimSpace(tasksPathEntry.Text)
+		edited.UITickInterval = parseDuration("ui_tick_interval", uiTickEntry.Text)
+		edited.ActivityTickInterval = parseDuration("activity_tick_interval", activityTickEntry.Text)
+		edited.FlushTickInterval = parseDuration("flush_tick_interval", flushTickEntry.Text)
+		edited.DailyTarget = parseDuration("daily_target", dailyTargetEntry.Text)
+		edited.PromptNoteOnStop = promptNoteCheck.Checked
+		edited.ThemeScale = float32(parseFloat("theme_scale", themeScaleEntry.Text))
+		edited.ThemeMode = themeModeSelect.Selected
+		edited.AccentColor = strings.TrimSpace(accentColorEntry.Text)
+		edited.WindowWidth = float32(parseFloat("window_width", windowWidthEntry.Text))
+		edited.WindowHeight = float32(parseFloat("window_height", windowHeightEntry.Text))
+		edited.MiniWidth = float32(parseFloat("mini_width", miniWidthEntry.Text))
+		edited.MiniHeight = float32(parseFloat("mini_height", miniHeightEntry.Text))
+		edited.MiniAlwaysOnTop = miniAlwaysOnTopCheck.Checked
+		edited.StartHidden = startHiddenCheck.Checked
+		edited.Locale.Language = strings.TrimSpace(languageEntry.Text)
+		edited.Locale.TimeFormat = strings.TrimSpace(timeFormatEntry.Text)
+		edited.Locale.DurationFormat = durationFormatSelect.Selected
+		edited.Locale.FirstWeekday = strings.ToLower(strings.TrimSpace(firstWeekdayEntry.Text))
+		edited.Schedule.Enabled = scheduleCheck.Checked
+		edited.Schedule.Week = make(map[string][]settings.HoursWindow)
+		for i, day := range settings.Weekdays {
+			windows, err := settings.ParseWindows(weekdayEntries[i].Text)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("schedule.week.%s: %s", day, err))
+			}
+			if len(windows) > 0 {
+				edited.Schedule.Week[day] = windows
+			}
+		}
+		edited.Schedule.Holidays = splitList(holidaysEntry.Text)
+		edited.Schedule.AutoStop = autoStopCheck.Checked
+		edited.Schedule.AutoStopIdle = parseDuration("schedule.auto_stop_idle", autoStopIdleEntry.Text)
+		edited.Schedule.WarnOutside = warnOutsideCheck.Checked
+		edited.Schedule.AutoStart = autoStartCheck.Checked
+		edited.Focus.Work = parseDuration("focus.work", focusWorkEntry.Text)
+		edited.Focus.ShortBreak = parseDuration("focus.short_break", shortBreakEntry.Text)
+		edited.Focus.LongBreak = parseDuration("focus.long_break", longBreakEntry.Text)
+		edited.Focus.LongBreakEvery = int(parseFloat("focus.long_break_every", longBreakEveryEntry.Text))
+		edited.Focus.AutoResume = autoResumeCheck.Checked
+		edited.Focus.Timeboxes = nil
+		for _, text := range splitList(timeboxesEntry.Text) {
+			edited.Focus.Timeboxes = append(edited.Focus.Timeboxes, parseDuration("focus.timeboxes", text))
+		}
+		edited.Focus.TimeboxAction = timeboxActionSelect.Selected
+		edited.Focus.SwitchTo = strings.TrimSpace(switchToEntry.Text)
+		edited.Lock.Action = lockActionSelect.Selected
+		edited.Lock.OfferResume = offerResumeCheck.Checked
+		edited.Notifications.Enabled = notificationsCheck.Checked
+		edited.Notifications.BreakReminder.After = parseDuration("notifications.break_reminder.after", breakAfterEntry.Text)
+		edited.Notifications.BreakReminder.Repeat = parseDuration("notifications.break_reminder.repeat", breakRepeatEntry.Text)
+		edited.Notifications.IdleWhileRunning.After = parseDuration("notifications.idle_while_running.after", idleAfterEntry.Text)
+		edited.Notifications.ActiveWhileStopped.After = parseDuration("notifications.active_while_stopped.after", activeAfterEntry.Text)
+		edited.Notifications.WorkdayEnd = strings.TrimSpace(workdayEndEntry.Text)
+		edited.Notifications.WorkdayEndRepeat = parseDuration("notifications.workday_end_repeat", workdayEndRepeatEntry.Text)
+		edited.Notifications.QuietFrom = strings.TrimSpace(quietFromEntry.Text)
+		edited.Notifications.QuietTo = strings.TrimSpace(quietToEntry.Text)
+		edited.Report.Preset = presetSelect.Selected
+		edited.Report.OutputPath = strings.TrimSpace(outputPathEntry.Text)
+		edited.Report.Timezone = strings.TrimSpace(timezoneEntry.Text)
+		edited.Report.BarRef = parseDuration("report.bar_ref", barRefEntry.Text)
+		edited.Report.Smooth = parseFloat("report.smooth", smoothEntry.Text)
+		edited.Report.Provider = providerSelect.Selected
+		edited.Report.Sender = strings.TrimSpace(senderEntry.Text)
+		edited.Report.Recipients = splitRecipients(recipientsEntry.Text)
+
+		if len(problems) > 0 {
+			return edited, problems // validation would only repeat the parse errors
+		}
+		return edited, edited.Problems()
+	}
+
+	saveButton := widget.NewButton(l.T("Save"), func() {
+		edited, problems := readForm()
+		if len(problems) > 0 {
+			dialog.ShowError(errors.New(strings.Join(problems, "\n")), w)
+			return
+		}
+		e := settings.Update(t.SettingsPath, func(current *settings.Settings) {
+			// keys the form doesn't show keep what the file has now, the tracker saves some of them while this window is open
+			kept := *current
+			*current = edited
+			current.DayFileLayout = kept.DayFileLayout
+			current.ActiveProfile = kept.ActiveProfile
+			current.Profiles = kept.Profiles
+			current.WindowPosition = kept.WindowPosition
+			current.MiniMode = kept.MiniMode
+			current.TaskTable = kept.TaskTable
+			current.ActivityWindow = kept.ActivityWindow
+		})
+		if e != nil {
+			showError(e, w)
+			return
+		}
+		restartRequired := settings.RestartRequired(saved, edited)
+		saved = edited
+		t.applySettings(edited)
+
+		message := l.T("SettingsSaved", "Path", t.SettingsPath)
+		if len(restartRequired) > 0 {
+			message += "\n\n" + l.T("SettingsRestartRequired", "Settings", strings.Join(restartRequired, ", "))
+		}
+		dialog.ShowInformation(l.T("SettingsTitle"), message, w)
+	})
+	saveButton.Importance = widget.HighImportance
+	closeButton := widget.NewButton(l.T("Close"), w.Close)
+
+	form := widget.NewForm(
+		widget.NewFormItem(l.T("SettingsWorkDir"), workDirEntry),
+		widget.NewFormItem(l.T("SettingsTasksFile"), tasksPathEntry),
+		widget.NewFormItem(l.T("SettingsUITick"), uiTickEntry),
+		widget.NewFormItem(l.T("SettingsActivityTick"), activityTickEntry),
+		widget.NewFormItem(l.T("SettingsAutosave"), flushTickEntry),
+		widget.NewFormItem(l.T("SettingsDailyTarget"), dailyTargetEntry),
+		widget.NewFormItem(l.T("SettingsNotes"), promptNoteCheck),
+		widget.NewFormItem(l.T("SettingsThemeScale"), themeScaleEntry),
+		widget.NewFormItem(l.T("SettingsTheme"), themeModeSelect),
+		widget.NewFormItem(l.T("SettingsAccentColor"), accentColorEntry),
+		widget.NewFormItem(l.T("SettingsWindowWidth"), windowWidthEntry),
+		widget.NewFormItem(l.T("SettingsWindowHeight"), windowHeightEntry),
+		widget.NewFormItem(l.T("SettingsMiniWidth"), miniWidthEntry),
+		widget.NewFormItem(l.T("SettingsMiniHeight"), miniHeightEntry),
+		widget.NewFormItem(l.T("SettingsMiniOnTop"), miniAlwaysOnTopCheck),
+		widget.NewFormItem(l.T("SettingsOnLaunch"), startHiddenCheck),
+		widget.NewFormItem(l.T("SettingsLanguage"), languageEntry),
+		widget.NewFormItem(l.T("SettingsClock"), timeFormatEntry),
+		widget.NewFormItem(l.T("SettingsDurations"), durationFormatSelect),
+		widget.NewFormItem(l.T("SettingsWeekStartsOn"), firstWeekdayEntry),
+		widget.NewFormItem(l.T("SettingsWorkingHours"), scheduleCheck),
+	)
+	for i := range settings.Weekdays {
+		form.Append(l.Weekday(time.Weekday((i+1)%7)), weekdayEntries[i]) // settings.Weekdays starts on monday
+	}
+	form.AppendItem(widget.NewFormItem(l.T("SettingsHolidays"), holidaysEntry))
+	form.AppendItem(widget.NewFormItem(l.T("SettingsAfterHours"), autoStopCheck))
+	form.AppendItem(widget.NewFormItem(l.T("SettingsStopWhenIdle"), autoStopIdleEntry))
+	form.AppendItem(widget.NewFormItem(l.T("SettingsOutOfHours"), warnOutsideCheck))
+	form.AppendItem(widget.NewFormItem(l.T("SettingsAutoStart"), autoStartCheck))
+	for _, item := range []*widget.FormItem{
+		widget.NewFormItem(l.T("SettingsPomodoro"), focusWorkEntry),
+		widget.NewFormItem(l.T("SettingsShortBreak"), shortBreakEntry),
+		widget.NewFormItem(l.T("SettingsLongBreak"), longBreakEntry),
+		widget.NewFormItem(l.T("SettingsLongBreakEvery"), longBreakEveryEntry),
+		widget.NewFormItem(l.T("SettingsAfterBreak"), autoResumeCheck),
+		widget.NewFormItem(l.T("SettingsTimeboxes"), timeboxesEntry),
+		widget.NewFormItem(l.T("SettingsTimeboxEnds"), timeboxActionSelect),
+		widget.NewFormItem(l.T("SwitchTo"), switchToEntry),
+		widget.NewFormItem(l.T("SettingsOnLock"), lockActionSelect),
+		widget.NewFormItem(l.T("SettingsOnUnlock"), offerResumeCheck),
+		widget.NewFormItem(l.T("SettingsNotifications"), notificationsCheck),
+		widget.NewFormItem(l.T("SettingsBreakAfter"), breakAfterEntry),
+		widget.NewFormItem(l.T("SettingsBreakEvery"), breakRepeatEntry),
+		widget.NewFormItem(l.T("SettingsIdleTracking"), idleAfterEntry),
+		widget.NewFormItem(l.T("SettingsActiveNotTracking"), activeAfterEntry),
+		widget.NewFormItem(l.T("SettingsWorkdayEnds"), workdayEndEntry),
+		widget.NewFormItem(l.T("SettingsAfterHoursEvery"), workdayEndRepeatEntry),
+		widget.NewFormItem(l.T("SettingsQuietFrom"), quietFromEntry),
+		widget.NewFormItem(l.T("SettingsQuietUntil"), quietToEntry),
+		widget.NewFormItem(l.T("SettingsReportPeriod"), presetSelect),
+		widget.NewFormItem(l.T("SettingsReportOutput"), outputPathEntry),
+		widget.NewFormItem(l.T("SettingsReportTimezone"), timezoneEntry),
+		widget.NewFormItem(l.T("SettingsChartBaseline"), barRefEntry),
+		widget.NewFormItem(l.T("SettingsSmoothing"), smoothEntry),
+		widget.NewFormItem(l.T("SettingsEmailProvider"), providerSelect),
+		widget.NewFormItem(l.T("SettingsEmailSender"), senderEntry),
+		widget.NewFormItem(l.T("SettingsEmailRecipients"), recipientsEntry),
+	} {
+		form.AppendItem(item)
+	}
+	hint := widget.NewLabel(l.T("SettingsHint"))
+	buttons := container.NewHBox(closeButton, saveButton)
+	w.SetContent(container.NewBorder(nil, container.NewVBox(hint, container.NewCenter(buttons)), nil, nil, container.NewVScroll(form)))
+	w.Show()
+}
+
+// settingsSnapshot is a copy of the effective settings, for goroutines other than the UI one
+func (t *TrackerApp) settingsSnapshot() settings.Settings {
+	t.Mutex.Lock()
+	defer t.Mutex.Unlock()
+	return t.Settings
+}
+
+/*
+applySettings applies everything that can change while running.
+Work dir, day file layout and tasks file keep their current values until restart or a profile
+switch, the current window mode and position stay as they are.
+*/
+func (t *TrackerApp) applySettings(newSettings settings.Settings) {
+	tl.Log(tl.Notice, palette.Blue, "%s settings", "Applying")
+	t.Mutex.Lock()
+	// the file holds the top-level values, the active profile still overrides them
+	profiled, e := newSettings.WithProfile(t.Settings.ActiveProfile)
+	if e != nil {
+		tl.Log(tl.Warning, palette.Yellow, "%s: %s", "Profile values not applied", e.Msg)
+	} else {
+		newSettings = profiled
+	}
+	newSettings.ActiveProfile = t.Settings.ActiveProfile
+	newSettings.WorkDir = t.Settings.WorkDir
+	newSettings.DayFileLayout = t.Settings.DayFileLayout
+	newSettings.TasksPath = t.Settings.TasksPath
+	newSettings.MiniMode = t.Settings.MiniMode
+	newSettings.WindowPosition = t.Settings.WindowPosition
+	t.Settings = newSettings
+
+	// scheduler
+	t.UITickInterval = newSettings.UITickInterval.Duration
+	t.ActivityTickInterval = newSettings.ActivityTickInterval.Duration
+	t.FlushTickInterval = newSettings.FlushTickInterval.Duration
+	t.Mutex.Unlock()
+	t.wakeScheduler()
+
+	// interface
+	t.applyTheme()
+	t.applyWindowMode() // sizes, always on top
+	t.updateTray()      // daily target may have changed
+	if t.TrayFocusItem != nil {
+		// pomodoro and timebox lengths are in the item labels
+		t.TrayFocusItem.ChildMenu.Items = t.focusItems(t.trayFocusEnd)
+		t.TrayMenu.Refresh()
+	}
+	// notification settings are read on every check, nothing to restart
+
+	tl.Log(tl.Notice1, palette.Green, "%s settings", "Applied")
+}
+
+func newEntryWithText(text string) *widget.Entry {
+	entry := widget.NewEntry()
+	entry.SetText(text)
+	return entry
+}
+
+// splitList splits a comma separated list, dropping blanks
+func splitList(text string) (items []string) {
+	for _, item := range strings.Split(text, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
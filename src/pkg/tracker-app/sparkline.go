@@ -0,0 +1,99 @@
+package trackerapp
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+/*
+Sparkline draws a row of percentages (0..100, oldest first) as a thin line,
+sized like an ActivityBar so the two line up. Negative values are gaps
+(nothing tracked), the line breaks there.
+*/
+type Sparkline struct {
+	widget.BaseWidget
+
+	values     []float64
+	WidthRatio float32 // fraction of available width to use, same as ActivityBar
+}
+
+func NewSparkline() *Sparkline {
+	s := &Sparkline{WidthRatio: 0.5}
+	s.ExtendBaseWidget(s)
+	return s
+}
+
+func (s *Sparkline) SetValues(values []float64) {
+	s.values = values
+	s.Refresh()
+}
+
+// --- widget.Renderer ---
+
+type sparklineRenderer struct {
+	s        *Sparkline
+	baseline *canvas.Line
+	lines    []*canvas.Line // one per pair of neighbouring values
+	objects  []fyne.CanvasObject
+}
+
+func (s *Sparkline) CreateRenderer() fyne.WidgetRenderer {
+	baseline := canvas.NewLine(theme.Color(theme.ColorNameInputBackground))
+	r := &sparklineRenderer{s: s, baseline: baseline}
+	r.objects = []fyne.CanvasObject{baseline}
+	return r
+}
+
+func (r *sparklineRenderer) Layout(sz fyne.Size) {
+	innerW := sz.Width * r.s.WidthRatio
+	innerX := (sz.Width - innerW) / 2
+	top, bottom := float32(1), sz.Height-1
+
+	r.baseline.Position1 = fyne.NewPos(innerX, bottom)
+	r.baseline.Position2 = fyne.NewPos(innerX+innerW, bottom)
+
+	values := r.s.values
+	if len(values) < 2 {
+		return
+	}
+	step := innerW / float32(len(values)-1)
+	pointAt := func(i int) fyne.Position {
+		return fyne.NewPos(innerX+step*float32(i), bottom-(bottom-top)*float32(clamp01(values[i]/100)))
+	}
+	for i, line := range r.lines {
+		if values[i] < 0 || values[i+1] < 0 {
+			line.Hide()
+			continue
+		}
+		line.Position1, line.Position2 = pointAt(i), pointAt(i+1)
+		line.StrokeColor = barColorFor((values[i] + values[i+1]) / 2)
+		line.Show()
+	}
+}
+
+func (r *sparklineRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(220, theme.TextSize()*1.6)
+}
+
+func (r *sparklineRenderer) Refresh() {
+	// one line per segment, rebuilt only when the number of values changes
+	if segments := max(len(r.s.values)-1, 0); segments != len(r.lines) {
+		r.lines = make([]*canvas.Line, segments)
+		r.objects = []fyne.CanvasObject{r.baseline}
+		for i := range r.lines {
+			r.lines[i] = canvas.NewLine(color.Transparent)
+			r.lines[i].StrokeWidth = 1.5
+			r.objects = append(r.objects, r.lines[i])
+		}
+	}
+	r.baseline.StrokeColor = theme.Color(theme.ColorNameInputBackground) // follow the theme variant
+	r.Layout(r.s.Size())
+	canvas.Refresh(r.s)
+}
+
+func (r *sparklineRenderer) Destroy()                     {}
+func (r *sparklineRenderer) Objects() []fyne.CanvasObject { return r.objects }
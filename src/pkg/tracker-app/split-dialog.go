@@ -0,0 +1,84 @@
+package trackerapp
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"work-tracker/src/pkg/history"
+)
+
+/*
+showSplitDialog asks for a range of today and the tasks to share it, one per line
+("Task", "Task=2" or "Task=09:00-10:30", see history.ParseSplitParts), then splits it.
+*/
+func (t *TrackerApp) showSplitDialog() {
+	t.showWindow() // dialogs need a window, and from the tray it's usually hidden
+
+	l := t.Locale
+	fromEntry := widget.NewEntry()
+	fromEntry.SetPlaceHolder(l.T("SplitFromPlaceholder"))
+	toEntry := widget.NewEntry()
+	toEntry.SetPlaceHolder(l.T("SplitToPlaceholder"))
+	partsEntry := widget.NewMultiLineEntry()
+	partsEntry.SetPlaceHolder(l.T("SplitPartsPlaceholder"))
+	partsEntry.SetMinRowsVisible(4)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(l.T("SplitFrom"), fromEntry),
+		widget.NewFormItem(l.T("SplitTo"), toEntry),
+		widget.NewFormItem(l.T("SplitInto"), partsEntry),
+	}
+	formDialog := dialog.NewForm(l.T("SplitTitle"), l.T("SplitButton"), l.T("Cancel"), items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		now := time.Now()
+		parts, err := history.ParseSplitParts(strings.Split(partsEntry.Text, "\n"), now)
+		if err != nil {
+			dialog.ShowError(err, t.Window)
+			return
+		}
+		explicit := !parts[0].From.IsZero()
+		var from, to time.Time
+		if text := strings.TrimSpace(fromEntry.Text); text != "" || !explicit {
+			from, err = parseAsOf(text, now)
+			if err != nil {
+				dialog.ShowError(err, t.Window)
+				return
+			}
+		}
+		if text := strings.TrimSpace(toEntry.Text); text != "" {
+			to, err = parseAsOf(text, now)
+			if err != nil {
+				dialog.ShowError(err, t.Window)
+				return
+			}
+		} else if !explicit {
+			to = now
+		}
+		for _, part := range parts {
+			if part.TaskName != "" && !slices.ContainsFunc(t.Tasks, func(task Task) bool { return task.Name == part.TaskName }) {
+				dialog.ShowError(errors.New(l.T("SplitUnknownTask", "Task", part.TaskName)), t.Window)
+				return
+			}
+		}
+
+		go func() {
+			e := t.splitToday(from, to, parts)
+			if e != nil {
+				fyne.Do(func() { showError(e, t.Window) })
+				return
+			}
+			t.updateInterface()
+			t.updateTray()
+		}()
+	}, t.Window)
+	formDialog.Resize(fyne.NewSize(520, 360))
+	formDialog.Show()
+}
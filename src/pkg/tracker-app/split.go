@@ -0,0 +1,44 @@
+package trackerapp
+
+import (
+	"errors"
+	"time"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/history"
+)
+
+// splitToday splits [from, to) of today's file while the tracker runs, keeping its totals in step.
+func (t *TrackerApp) splitToday(from, to time.Time, parts []history.SplitPart) (e *xerr.Error) {
+	t.Mutex.Lock()
+	defer t.Mutex.Unlock()
+
+	now := time.Now()
+	if to.After(now) {
+		return xerr.NewErrorECOL(errors.New("range in the future"), "The range to split can't reach past now", "to", to.Format(time.TimeOnly))
+	}
+	if from.Before(startOfDay(now)) && !from.IsZero() {
+		return xerr.NewErrorECOL(errors.New("range before today"), "Only today's time can be split here, use cmd/split for other days", "from", from.Format(time.DateTime))
+	}
+	tl.Log(tl.Info, palette.Cyan, "%s %s-%s across %v tasks", "Splitting", from.Format(time.TimeOnly), to.Format(time.TimeOnly), len(parts))
+
+	// a split that fails leaves the file as it was
+	t.flushChunkLocked(now)
+	chunks, e := t.readDayLocked()
+	if e != nil {
+		return e
+	}
+	split, e := history.SplitChunks(chunks, from, to, parts)
+	if e != nil {
+		return e
+	}
+	e = t.writeDayLocked(now, split)
+	if e != nil {
+		return e
+	}
+	t.LastAction = nil // undo would work against the old history
+	return nil
+}
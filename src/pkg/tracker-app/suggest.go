@@ -0,0 +1,258 @@
+package trackerapp
+
+import (
+	"cmp"
+	"errors"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/store"
+)
+
+/*
+Suggestions guess which task the big Start button is about to be used for,
+from the day files only (see taskHistory) - nothing leaves the machine.
+
+A task scores for being worked on this weekday, around this time of day,
+lately, and for being among the tasks started or switched to today. The
+Start button offers the best ones, and an unassigned run is tagged with
+the best one (Chunk.SuggestedTask) so its time can be assigned with one
+click from the bar under the buttons, or the tag dismissed.
+*/
+
+const (
+	maxSuggestions     = 3
+	suggestionHalfLife = 7 * 24 * time.Hour // recency score halves every week
+)
+
+// weights of the scores, each score is 0..1
+const (
+	weekdayWeight = 0.3
+	hourWeight    = 0.3
+	recencyWeight = 0.2
+	recentWeight  = 0.2
+)
+
+type taskSuggestion struct {
+	TaskName string
+	Score    float64
+}
+
+/*
+suggestionsLocked scores the open tasks as of now, best first, at most
+maxSuggestions and only tasks with some history. Caller holds t.Mutex.
+*/
+func (t *TrackerApp) suggestionsLocked(now time.Time) (suggestions []taskSuggestion) {
+	weekday, hour := now.Weekday(), now.Hour()
+	// time around now counts most, the neighbouring hours half
+	aroundNow := func(past taskHistory) (around time.Duration) {
+		around = past.ByHour[hour]
+		around += past.ByHour[(hour+23)%24] / 2
+		around += past.ByHour[(hour+1)%24] / 2
+		return around
+	}
+
+	// most time any task has on this weekday and hour, so the scores are shares of the busiest
+	var topWeekday, topHour time.Duration
+	for _, task := range t.Tasks {
+		past := t.taskHistory[task.Name]
+		topWeekday = max(topWeekday, past.ByWeekday[weekday])
+		topHour = max(topHour, aroundNow(past))
+	}
+
+	for _, task := range t.Tasks {
+		if task.CompletedAt != nil {
+			continue
+		}
+		past := t.taskHistory[task.Name]
+		var score float64
+		if topWeekday > 0 {
+			score += weekdayWeight * float64(past.ByWeekday[weekday]) / float64(topWeekday)
+		}
+		if topHour > 0 {
+			score += hourWeight * float64(aroundNow(past)) / float64(topHour)
+		}
+		if lastWorked := latest(past.LastWorked, t.todayStats[task.Name].LastEnd); !lastWorked.IsZero() {
+			score += recencyWeight * math.Pow(0.5, float64(now.Sub(lastWorked))/float64(suggestionHalfLife))
+		}
+		if i := slices.Index(t.RecentTasks, task.Name); i >= 0 {
+			score += recentWeight * float64(maxRecentTasks-i) / maxRecentTasks
+		}
+		if score > 0 {
+			suggestions = append(suggestions, taskSuggestion{TaskName: task.Name, Score: score})
+		}
+	}
+	slices.SortStableFunc(suggestions, func(a, b taskSuggestion) int { return cmp.Compare(b.Score, a.Score) })
+	return suggestions[:min(len(suggestions), maxSuggestions)]
+}
+
+// suggestionForLocked is what a run of taskName is tagged with: the best suggestion when unassigned. Caller holds t.Mutex.
+func (t *TrackerApp) suggestionForLocked(taskName string, now time.Time) string {
+	if taskName != "" {
+		return ""
+	}
+	suggestions := t.suggestionsLocked(now)
+	if len(suggestions) == 0 {
+		return ""
+	}
+	tl.Log(tl.Info, palette.Cyan, "%s '%s'. Score: %.2f", "Tagging unassigned run with", suggestions[0].TaskName, suggestions[0].Score)
+	return suggestions[0].TaskName
+}
+
+// suggestedTimeFromChunks totals a day's unassigned time per suggested task
+func suggestedTimeFromChunks(chunks []store.Chunk) (suggested map[string]time.Duration) {
+	suggested = make(map[string]time.Duration)
+	for _, chunk := range chunks {
+		if chunk.Kind == store.ChunkKindWork && chunk.TaskName == "" && chunk.SuggestedTask != "" {
+			suggested[chunk.SuggestedTask] += chunk.FinishedAt.Sub(chunk.StartedAt)
+		}
+	}
+	return suggested
+}
+
+/*
+pendingSuggestionLocked is the suggested task with the most unassigned time
+today, open chunk included, "" when there's none. Caller holds t.Mutex.
+*/
+func (t *TrackerApp) pendingSuggestionLocked(now time.Time) (taskName string, tracked time.Duration) {
+	suggested := t.suggestedTime
+	if t.IsRunning && t.CurrentTaskName == "" && t.SuggestedTask != "" && now.After(t.ChunkStart) {
+		suggested = maps.Clone(t.suggestedTime)
+		suggested[t.SuggestedTask] += now.Sub(t.ChunkStart)
+	}
+	for name, d := range suggested {
+		if d > tracked || (d == tracked && name < taskName) {
+			taskName, tracked = name, d
+		}
+	}
+	return taskName, tracked
+}
+
+// onStartButtonTapped offers the suggestions when idle, otherwise it's toggleTracking. Must run on the UI goroutine.
+func (t *TrackerApp) onStartButtonTapped() {
+	t.Mutex.Lock()
+	idle := !t.IsRunning && !t.IsPaused
+	var suggestions []taskSuggestion
+	if idle {
+		suggestions = t.suggestionsLocked(time.Now())
+	}
+	t.Mutex.Unlock()
+	if len(suggestions) == 0 {
+		t.toggleTracking()
+		return
+	}
+
+	var items []*fyne.MenuItem
+	for _, suggestion := range suggestions {
+		items = append(items, fyne.NewMenuItem(t.Locale.T("StartSuggested", "Task", suggestion.TaskName), func() { t.startTask(suggestion.TaskName) }))
+	}
+	items = append(items, fyne.NewMenuItemSeparator())
+	items = append(items, fyne.NewMenuItem(t.Locale.T("StartUnassigned"), func() { t.startTask("") }))
+	below := fyne.NewPos(0, t.Button.Size().Height)
+	widget.ShowPopUpMenuAtRelativePosition(fyne.NewMenu("", items...), t.Window.Canvas(), below, t.Button)
+}
+
+// makeSuggestionBar builds the hidden bar that offers to assign unassigned time, see refreshSuggestionBar
+func (t *TrackerApp) makeSuggestionBar() {
+	t.SuggestionLabel = widget.NewLabel("")
+	assign := widget.NewButtonWithIcon(t.Locale.T("SuggestionAssign"), theme.ConfirmIcon(), func() {
+		taskName := t.shownSuggestion
+		go t.confirmSuggestionFromUI(taskName, true)
+	})
+	assign.Importance = widget.HighImportance
+	dismiss := widget.NewButtonWithIcon(t.Locale.T("SuggestionDismiss"), theme.CancelIcon(), func() {
+		taskName := t.shownSuggestion
+		go t.confirmSuggestionFromUI(taskName, false)
+	})
+	dismiss.Importance = widget.LowImportance
+	t.SuggestionBar = container.NewCenter(container.NewHBox(t.SuggestionLabel, assign, dismiss))
+	t.SuggestionBar.Hide()
+}
+
+// refreshSuggestionBar shows the pending suggestion, or hides the bar. Must run on the UI goroutine.
+func (t *TrackerApp) refreshSuggestionBar(taskName string, tracked time.Duration) {
+	if taskName == "" || tracked < time.Minute {
+		t.shownSuggestion = ""
+		t.SuggestionBar.Hide()
+		return
+	}
+	t.shownSuggestion = taskName
+	text := t.Locale.T("SuggestionPending", "Duration", t.Locale.DurationMinutes(tracked), "Task", taskName)
+	if text != t.SuggestionLabel.Text {
+		t.SuggestionLabel.SetText(text)
+	}
+	t.SuggestionBar.Show()
+}
+
+/*
+confirmSuggestion gives today's unassigned time tagged with taskName to it
+(assign) or drops the tag (dismiss). A running unassigned run with the same
+tag goes on as taskName, or untagged.
+*/
+func (t *TrackerApp) confirmSuggestion(taskName string, assign bool) (e *xerr.Error) {
+	if taskName == "" {
+		return xerr.NewErrorECOL(errors.New("no suggestion"), "There is no suggested task to confirm", "assign", assign)
+	}
+	tl.Log(tl.Info, palette.Blue, "%s suggestion '%s'. Assign: %v", "Confirming", taskName, assign)
+	t.refreshActivityState()
+	t.refreshUIState()
+
+	t.Mutex.Lock()
+	now := time.Now()
+	e = t.rewriteDayLocked(now, func(chunks []store.Chunk) []store.Chunk {
+		return confirmSuggestedChunks(chunks, taskName, assign)
+	})
+	switched := e == nil && assign && t.IsRunning && t.CurrentTaskName == "" && t.SuggestedTask == taskName
+	if e == nil && t.SuggestedTask == taskName {
+		t.SuggestedTask = ""
+	}
+	if switched {
+		t.CurrentTaskName = taskName // rebased, so the run's time so far is in the file already
+	}
+	t.Mutex.Unlock()
+	if e != nil {
+		return e
+	}
+
+	tl.Log(tl.Info1, palette.Green, "%s suggestion '%s'. Assign: %v", "Confirmed", taskName, assign)
+	if switched {
+		t.afterTrackingChanged(taskName)
+		return nil
+	}
+	t.updateInterface()
+	t.updateTray()
+	return nil
+}
+
+// confirmSuggestedChunks assigns (or only untags) the unassigned chunks tagged with taskName
+func confirmSuggestedChunks(chunks []store.Chunk, taskName string, assign bool) (result []store.Chunk) {
+	for _, chunk := range chunks {
+		if chunk.Kind == store.ChunkKindWork && chunk.TaskName == "" && chunk.SuggestedTask == taskName {
+			chunk.SuggestedTask = ""
+			if assign {
+				chunk.TaskName = taskName
+			}
+		}
+		result = append(result, chunk)
+	}
+	return result
+}
+
+// confirmSuggestionFromUI runs confirmSuggestion off the UI goroutine and reports failures in the main window
+func (t *TrackerApp) confirmSuggestionFromUI(taskName string, assign bool) {
+	e := t.confirmSuggestion(taskName, assign)
+	if e != nil {
+		fyne.Do(func() { showError(e, t.Window) })
+	}
+}
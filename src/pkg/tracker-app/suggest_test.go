@@ -0,0 +1,196 @@
+package trackerapp
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"work-tracker/src/pkg/store"
+)
+
+func TestSuggestions(t *testing.T) {
+	now := time.Date(2026, 1, 23, 10, 0, 0, 0, time.Local) // a Friday
+	// worked for d on weekday at hour, last on lastWorked
+	worked := func(weekday time.Weekday, hour int, d time.Duration, lastWorked time.Time) (past taskHistory) {
+		past.ByWeekday[weekday] = d
+		past.ByHour[hour] = d
+		past.Tracked = d
+		past.LastWorked = lastWorked
+		return past
+	}
+	longAgo := now.AddDate(-1, 0, 0) // the recency score is next to nothing
+	done := now.Add(-time.Hour)
+	tests := []struct {
+		name    string
+		tasks   []Task
+		history map[string]taskHistory
+		today   map[string]taskDayStats
+		recent  []string
+		want    []string
+	}{
+		{"no history", []Task{{Name: "A"}, {Name: "B"}}, nil, nil, nil, nil},
+		{"this hour", []Task{{Name: "A"}, {Name: "B"}},
+			map[string]taskHistory{"A": worked(time.Monday, 15, time.Hour, longAgo), "B": worked(time.Monday, 10, time.Hour, longAgo)}, nil, nil,
+			[]string{"B", "A"}},
+		{"the next hour counts half", []Task{{Name: "A"}, {Name: "B"}},
+			map[string]taskHistory{"A": worked(time.Monday, 11, 90*time.Minute, longAgo), "B": worked(time.Monday, 10, time.Hour, longAgo)}, nil, nil,
+			[]string{"B", "A"}},
+		{"the previous hour counts half", []Task{{Name: "A"}, {Name: "B"}},
+			map[string]taskHistory{"A": worked(time.Monday, 9, 3*time.Hour, longAgo), "B": worked(time.Monday, 10, time.Hour, longAgo)}, nil, nil,
+			[]string{"A", "B"}},
+		{"this weekday", []Task{{Name: "A"}, {Name: "B"}},
+			map[string]taskHistory{"A": worked(time.Monday, 3, time.Hour, longAgo), "B": worked(time.Friday, 3, time.Hour, longAgo)}, nil, nil,
+			[]string{"B", "A"}},
+		{"lately", []Task{{Name: "A"}, {Name: "B"}},
+			map[string]taskHistory{"A": worked(time.Monday, 3, time.Hour, now.AddDate(0, 0, -14)), "B": worked(time.Monday, 3, time.Hour, now.AddDate(0, 0, -1))}, nil, nil,
+			[]string{"B", "A"}},
+		{"worked on today", []Task{{Name: "A"}, {Name: "B"}},
+			map[string]taskHistory{"A": worked(time.Monday, 3, time.Hour, now.AddDate(0, 0, -3)), "B": worked(time.Monday, 3, time.Hour, now.AddDate(0, 0, -3))},
+			map[string]taskDayStats{"B": {LastEnd: now.Add(-time.Hour)}}, nil,
+			[]string{"B", "A"}},
+		{"started today", []Task{{Name: "A"}, {Name: "B"}, {Name: "C"}}, nil, nil, []string{"C", "A"},
+			[]string{"C", "A"}},
+		{"ties keep the tasks file's order", []Task{{Name: "B"}, {Name: "A"}},
+			map[string]taskHistory{"A": worked(time.Friday, 10, time.Hour, longAgo), "B": worked(time.Friday, 10, time.Hour, longAgo)}, nil, nil,
+			[]string{"B", "A"}},
+		{"completed tasks left out, at most three", []Task{{Name: "A", CompletedAt: &done}, {Name: "B"}, {Name: "C"}, {Name: "D"}, {Name: "E"}},
+			map[string]taskHistory{
+				"A": worked(time.Friday, 10, 5*time.Hour, longAgo),
+				"B": worked(time.Friday, 10, time.Hour, longAgo),
+				"C": worked(time.Friday, 10, 4*time.Hour, longAgo),
+				"D": worked(time.Friday, 10, 2*time.Hour, longAgo),
+				"E": worked(time.Friday, 10, 3*time.Hour, longAgo),
+			}, nil, nil,
+			[]string{"C", "E", "D"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := &TrackerApp{Tasks: test.tasks, taskHistory: test.history, todayStats: test.today, RecentTasks: test.recent}
+			var got []string
+			for _, suggestion := range app.suggestionsLocked(now) {
+				got = append(got, suggestion.TaskName)
+				if suggestion.Score <= 0 || suggestion.Score > 1 {
+					t.Errorf("%s scores %v, want within (0, 1]", suggestion.TaskName, suggestion.Score)
+				}
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("suggestions = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSuggestionFor(t *testing.T) {
+	now := time.Date(2026, 1, 23, 10, 0, 0, 0, time.Local)
+	app := &TrackerApp{Tasks: []Task{{Name: "Code"}}, RecentTasks: []string{"Code"}}
+	if got := app.suggestionForLocked("", now); got != "Code" {
+		t.Errorf("unassigned run tagged %q, want Code", got)
+	}
+	if got := app.suggestionForLocked("Email", now); got != "" {
+		t.Errorf("a run of Email tagged %q, want nothing", got)
+	}
+	app.RecentTasks = nil
+	if got := app.suggestionForLocked("", now); got != "" {
+		t.Errorf("unassigned run tagged %q without history, want nothing", got)
+	}
+}
+
+func TestPendingSuggestion(t *testing.T) {
+	chunks := []store.Chunk{
+		{TaskName: "", SuggestedTask: "Code", StartedAt: at(9, 0), FinishedAt: at(9, 20)},
+		{TaskName: "", SuggestedTask: "Email", StartedAt: at(9, 20), FinishedAt: at(9, 50)},
+		{TaskName: "Code", SuggestedTask: "Email", StartedAt: at(9, 50), FinishedAt: at(10, 0)}, // assigned, not pending
+		{Kind: store.ChunkKindPause, SuggestedTask: "Code", StartedAt: at(10, 0), FinishedAt: at(11, 0)},
+		{TaskName: "", StartedAt: at(11, 0), FinishedAt: at(11, 10)}, // untagged
+	}
+	suggested := suggestedTimeFromChunks(chunks)
+	if len(suggested) != 2 || suggested["Code"] != 20*time.Minute || suggested["Email"] != 30*time.Minute {
+		t.Fatalf("suggestedTimeFromChunks = %v, want Code 20m, Email 30m", suggested)
+	}
+
+	tests := []struct {
+		name          string
+		running       bool
+		suggestedTask string
+		chunkStart    time.Time
+		wantTask      string
+		wantTracked   time.Duration
+	}{
+		{"stopped", false, "Code", at(11, 10), "Email", 30 * time.Minute},
+		{"the open chunk counts", true, "Code", at(11, 10), "Code", 35 * time.Minute},
+		{"a tie goes by name", true, "Code", at(11, 15), "Code", 30 * time.Minute},
+		{"running untagged", true, "", at(11, 10), "Email", 30 * time.Minute},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := &TrackerApp{suggestedTime: suggested, IsRunning: test.running, SuggestedTask: test.suggestedTask, ChunkStart: test.chunkStart}
+			taskName, tracked := app.pendingSuggestionLocked(at(11, 25))
+			if taskName != test.wantTask || tracked != test.wantTracked {
+				t.Errorf("pending %q for %s, want %q for %s", taskName, tracked, test.wantTask, test.wantTracked)
+			}
+			if suggested["Code"] != 20*time.Minute {
+				t.Error("pendingSuggestionLocked changed the day's totals")
+			}
+		})
+	}
+}
+
+func TestConfirmSuggestedChunks(t *testing.T) {
+	chunks := []store.Chunk{
+		{TaskName: "", SuggestedTask: "Code", StartedAt: at(9, 0), FinishedAt: at(9, 20)},
+		{TaskName: "", SuggestedTask: "Email", StartedAt: at(9, 20), FinishedAt: at(9, 50)},
+		{Kind: store.ChunkKindPause, SuggestedTask: "Code", StartedAt: at(10, 0), FinishedAt: at(11, 0)},
+	}
+	tests := []struct {
+		name   string
+		assign bool
+		want   []string // task/suggested of each chunk
+	}{
+		{"assign", true, []string{"Code/", "/Email", "/Code"}},
+		{"dismiss", false, []string{"/", "/Email", "/Code"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, chunk := range confirmSuggestedChunks(chunks, "Code", test.assign) {
+				got = append(got, chunk.TaskName+"/"+chunk.SuggestedTask)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("chunks %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTaskHistoryByWeekdayAndHour(t *testing.T) {
+	layout := store.MustParseLayout("{{.Year}}/{{.Year}}-{{.Month02}}.jsonl")
+	workDir := t.TempDir()
+	monday := time.Date(2026, 1, 19, 0, 0, 0, 0, time.Local)
+	chunks := []store.Chunk{
+		workChunk("Code", monday.Add(9*time.Hour), monday.Add(9*time.Hour+30*time.Minute), 0),
+		workChunk("Code", monday.Add(9*time.Hour+30*time.Minute), monday.Add(10*time.Hour+30*time.Minute), 0),  // by the hour it started in
+		workChunk("Code", monday.Add(23*time.Hour+50*time.Minute), monday.Add(24*time.Hour+10*time.Minute), 0), // by the day it started on
+		workChunk("Code", monday.AddDate(0, 0, 1).Add(14*time.Hour), monday.AddDate(0, 0, 1).Add(15*time.Hour), 0),
+	}
+	writer := store.NewWriter(workDir, layout)
+	for _, chunk := range chunks {
+		e := writer.AppendDay(chunk.StartedAt, chunk)
+		if e != nil {
+			t.Fatalf("write: %s", e.Msg)
+		}
+	}
+
+	past := loadTaskHistory(workDir, layout, monday.AddDate(0, 0, 4))["Code"]
+	byWeekday := map[time.Weekday]time.Duration{time.Monday: 110 * time.Minute, time.Tuesday: time.Hour}
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if past.ByWeekday[weekday] != byWeekday[weekday] {
+			t.Errorf("%s: %s, want %s", weekday, past.ByWeekday[weekday], byWeekday[weekday])
+		}
+	}
+	byHour := map[int]time.Duration{9: 90 * time.Minute, 14: time.Hour, 23: 20 * time.Minute}
+	for hour := range 24 {
+		if past.ByHour[hour] != byHour[hour] {
+			t.Errorf("%02d:00: %s, want %s", hour, past.ByHour[hour], byHour[hour])
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package trackerapp
+
+import (
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+
+	"work-tracker/src/pkg/store"
+)
+
+/*
+The detail pane opens next to the task table when a task's name or description
+is clicked. It renders the description as markdown (ticket links open in the
+browser) with the task's dates, totals, a bar per day for the last two weeks and
+its latest notes. The days are read from the day files each time the pane opens.
+*/
+
+const (
+	detailChartDays  = 14 // bars in the pane's chart
+	detailRecentDays = 30 // days read for the recent totals
+	detailNotes      = 5  // latest notes shown
+)
+
+// taskDetail is what the pane shows besides the task itself and its stats
+type taskDetail struct {
+	ByDay []time.Duration // the last detailRecentDays days, oldest first, today last
+	Notes []taskNote      // latest first
+}
+
+type taskNote struct {
+	At   time.Time // start of the first chunk with the note
+	Text string
+}
+
+/*
+loadTaskDetail reads the task's time per day and its notes from the day files
+of the last detailRecentDays days. Bad lines are skipped, a file that can't be
+read counts as an empty day.
+*/
+func loadTaskDetail(workDir string, layout *store.Layout, taskName string, now time.Time) (detail taskDetail) {
+	tl.Log(tl.Info, palette.Blue, "%s details of '%s' from '%s'", "Reading", taskName, workDir)
+	var notes []taskNote
+	reader := store.NewReader(workDir, layout, store.Lenient)
+	for day, e := range reader.Days(now.AddDate(0, 0, 1-detailRecentDays), now) {
+		if e != nil {
+			tl.Log(tl.Warning, palette.Yellow, "%s '%s' in the task details: %s", "Skipping", day.Path, e.Msg)
+		}
+		var tracked time.Duration
+		for _, chunk := range day.Chunks {
+			if chunk.Kind != store.ChunkKindWork || chunk.TaskName != taskName {
+				continue
+			}
+			tracked += chunk.Duration()
+			// a note is written on every chunk of its block of work, keep it once
+			if chunk.Note != "" && (len(notes) == 0 || notes[len(notes)-1].Text != chunk.Note) {
+				notes = append(notes, taskNote{At: chunk.StartedAt, Text: chunk.Note})
+			}
+		}
+		detail.ByDay = append(detail.ByDay, tracked)
+	}
+	slices.Reverse(notes)
+	detail.Notes = notes[:min(len(notes), detailNotes)]
+	tl.Log(tl.Info, palette.Green, "%s details of '%s'. Notes: %v", "Read", taskName, len(notes))
+	return detail
+}
+
+// totals are the task's time in the last 7 days and in all of ByDay, today included
+func (d taskDetail) totals() (lastWeek, lastMonth time.Duration) {
+	for i, day := range d.ByDay {
+		if i >= len(d.ByDay)-7 {
+			lastWeek += day
+		}
+		lastMonth += day
+	}
+	return lastWeek, lastMonth
+}
+
+// showTaskDetail opens the pane for task, filled once its days are read. Must run on the UI goroutine.
+func (t *TrackerApp) showTaskDetail(task Task) {
+	now := time.Now()
+	t.Mutex.Lock()
+	stats := t.taskStatsLocked(now)[task.Name]
+	workDir, layout := t.Workdir, t.Layout
+	t.Mutex.Unlock()
+
+	go func() {
+		detail := loadTaskDetail(workDir, layout, task.Name, now)
+		detail.ByDay[len(detail.ByDay)-1] = stats.Tracked // the file is missing the open chunk
+		fyne.Do(func() {
+			t.TaskDetail.Objects = []fyne.CanvasObject{t.makeTaskDetail(task, stats, detail)}
+			t.TaskDetail.Show()
+			t.TaskDetail.Refresh()
+			t.TaskSplit.Refresh()
+		})
+	}()
+}
+
+// hideTaskDetail closes the pane, the table takes the whole width again. Must run on the UI goroutine.
+func (t *TrackerApp) hideTaskDetail() {
+	t.TaskDetail.Hide()
+	t.TaskDetail.Objects = nil
+	t.TaskSplit.Refresh()
+}
+
+func (t *TrackerApp) makeTaskDetail(task Task, stats taskStats, detail taskDetail) fyne.CanvasObject {
+	// name and close
+	name := widget.NewLabelWithStyle(task.Name, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	name.Wrapping = fyne.TextWrapWord
+	closeButton := widget.NewButtonWithIcon("", theme.CancelIcon(), t.hideTaskDetail)
+	closeButton.Importance = widget.LowImportance
+	header := container.NewBorder(nil, nil, nil, container.NewVBox(closeButton), name)
+
+	// description, links are clickable
+	description := widget.NewRichTextFromMarkdown(task.Description)
+	if task.Description == "" {
+		description = widget.NewRichTextWithText(t.Locale.T("DetailNoDescription"))
+	}
+	description.Wrapping = fyne.TextWrapWord
+
+	// dates and totals
+	completed := t.Locale.T("DetailNotCompleted")
+	if task.CompletedAt != nil {
+		completed = t.Locale.Date(*task.CompletedAt)
+	}
+	lastWeek, lastMonth := detail.totals()
+	facts := container.New(layout.NewFormLayout())
+	for _, fact := range [][2]string{
+		{t.Locale.T("DetailCreated"), t.Locale.Date(task.CreatedAt)},
+		{t.Locale.T("DetailCompleted"), completed},
+		{t.Locale.T("DetailToday"), t.Locale.DurationMinutes(stats.Tracked)},
+		{t.Locale.T("DetailLast7Days"), t.Locale.DurationMinutes(lastWeek)},
+		{t.Locale.T("DetailLast30Days"), t.Locale.DurationMinutes(lastMonth)},
+		{t.Locale.T("DetailLifetime"), t.Locale.DurationMinutes(stats.Lifetime)},
+	} {
+		facts.Add(widget.NewLabelWithStyle(fact[0], fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		facts.Add(widget.NewLabel(fact[1]))
+	}
+
+	// a bar per day
+	chart := NewDayBars()
+	chart.SetDays(detail.ByDay[len(detail.ByDay)-detailChartDays:])
+
+	// latest notes
+	notes := container.NewVBox()
+	for _, note := range detail.Notes {
+		label := widget.NewLabel(t.Locale.DayMonth(note.At) + " " + t.Locale.Clock(note.At) + " — " + note.Text)
+		label.Wrapping = fyne.TextWrapWord
+		notes.Add(label)
+	}
+	if len(detail.Notes) == 0 {
+		notes.Add(widget.NewLabel(t.Locale.T("DetailNoNotes")))
+	}
+
+	return container.NewVScroll(container.NewVBox(
+		header,
+		description,
+		widget.NewSeparator(),
+		facts,
+		widget.NewLabelWithStyle(t.Locale.T("DetailChart"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		chart,
+		widget.NewLabelWithStyle(t.Locale.T("DetailRecentNotes"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		notes,
+	))
+}
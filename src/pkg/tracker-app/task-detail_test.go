@@ -0,0 +1,120 @@
+package trackerapp
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"work-tracker/src/pkg/store"
+)
+
+func TestLoadTaskDetail(t *testing.T) {
+	day := func(month time.Month, date, hour int) time.Time {
+		year := 2026
+		if month == time.December {
+			year = 2025
+		}
+		return time.Date(year, month, date, hour, 0, 0, 0, time.Local)
+	}
+	now := day(1, 23, 12)
+	chunks := []store.Chunk{
+		// before the 30 days
+		{TaskName: "Code", StartedAt: day(12, 24, 9), FinishedAt: day(12, 24, 10), Note: "too old"},
+		// the first of the 30 days
+		{TaskName: "Code", StartedAt: day(12, 25, 9), FinishedAt: day(12, 25, 10), Note: "first"},
+		// a note on every chunk of its block, kept once
+		{TaskName: "Code", StartedAt: day(1, 20, 9), FinishedAt: day(1, 20, 10), Note: "parser"},
+		{TaskName: "Code", StartedAt: day(1, 20, 10), FinishedAt: day(1, 20, 11), Note: "parser"},
+		{TaskName: "Email", StartedAt: day(1, 20, 11), FinishedAt: day(1, 20, 12), Note: "inbox"},
+		{Kind: store.ChunkKindPause, TaskName: "Code", StartedAt: day(1, 20, 12), FinishedAt: day(1, 20, 13)},
+		{TaskName: "Code", StartedAt: day(1, 20, 13), FinishedAt: day(1, 20, 14), Note: "parser tests"},
+		{TaskName: "Code", StartedAt: day(1, 23, 8), FinishedAt: day(1, 23, 10)},
+	}
+	layouts := []string{store.DefaultLayout, "{{.Year}}/{{.Year}}-{{.Month02}}.jsonl"}
+	for _, text := range layouts {
+		t.Run(text, func(t *testing.T) {
+			layout := store.MustParseLayout(text)
+			workDir := t.TempDir()
+			writer := store.NewWriter(workDir, layout)
+			for _, chunk := range chunks {
+				e := writer.AppendDay(chunk.StartedAt, chunk)
+				if e != nil {
+					t.Fatalf("write: %s", e.Msg)
+				}
+			}
+
+			detail := loadTaskDetail(workDir, layout, "Code", now)
+			if len(detail.ByDay) != detailRecentDays {
+				t.Fatalf("%v days, want %v", len(detail.ByDay), detailRecentDays)
+			}
+			want := make([]time.Duration, detailRecentDays)
+			want[0] = time.Hour      // December 25th
+			want[26] = 3 * time.Hour // January 20th, the pause left out
+			want[29] = 2 * time.Hour // today
+			if !slices.Equal(detail.ByDay, want) {
+				t.Errorf("ByDay = %v, want %v", detail.ByDay, want)
+			}
+
+			var notes []string
+			for _, note := range detail.Notes {
+				notes = append(notes, fmt.Sprintf("%s %s", note.At.Format("Jan 2 15"), note.Text))
+			}
+			wantNotes := []string{"Jan 20 13 parser tests", "Jan 20 09 parser", "Dec 25 09 first"}
+			if !slices.Equal(notes, wantNotes) {
+				t.Errorf("Notes = %q, want %q", notes, wantNotes)
+			}
+
+			lastWeek, lastMonth := detail.totals()
+			if lastWeek != 5*time.Hour || lastMonth != 6*time.Hour {
+				t.Errorf("totals = %s, %s; want 5h0m0s, 6h0m0s", lastWeek, lastMonth)
+			}
+		})
+	}
+}
+
+func TestLoadTaskDetailKeepsLatestNotes(t *testing.T) {
+	layout := store.MustParseLayout(store.DefaultLayout)
+	workDir := t.TempDir()
+	var chunks []store.Chunk
+	for i := range detailNotes + 2 {
+		start := at(8+i, 0)
+		chunks = append(chunks, store.Chunk{TaskName: "Code", StartedAt: start, FinishedAt: start.Add(30 * time.Minute), Note: fmt.Sprint("note ", i)})
+	}
+	e := store.NewWriter(workDir, layout).RewriteDay(testDay, chunks)
+	if e != nil {
+		t.Fatalf("write: %s", e.Msg)
+	}
+
+	detail := loadTaskDetail(workDir, layout, "Code", at(18, 0))
+	var notes []string
+	for _, note := range detail.Notes {
+		notes = append(notes, note.Text)
+	}
+	want := []string{"note 6", "note 5", "note 4", "note 3", "note 2"}
+	if !slices.Equal(notes, want) {
+		t.Errorf("Notes = %q, want the latest %q", notes, want)
+	}
+}
+
+func TestTaskDetailTotals(t *testing.T) {
+	tests := []struct {
+		name      string
+		byDay     []time.Duration
+		lastWeek  time.Duration
+		lastMonth time.Duration
+	}{
+		{"nothing", nil, 0, 0},
+		{"fewer days than a week", []time.Duration{time.Hour, 2 * time.Hour}, 3 * time.Hour, 3 * time.Hour},
+		{"the week is the last 7", []time.Duration{time.Hour, 0, 0, 0, 0, 0, 0, 0, 2 * time.Hour}, 2 * time.Hour, 3 * time.Hour},
+		{"today counts", []time.Duration{time.Hour, 0, 0, 0, 0, 0, 0, time.Minute}, time.Minute, time.Hour + time.Minute},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lastWeek, lastMonth := taskDetail{ByDay: test.byDay}.totals()
+			if lastWeek != test.lastWeek || lastMonth != test.lastMonth {
+				t.Errorf("totals = %s, %s; want %s, %s", lastWeek, lastMonth, test.lastWeek, test.lastMonth)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package trackerapp
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+
+	"work-tracker/src/pkg/settings"
+	"work-tracker/src/pkg/store"
+)
+
+/*
+Task stats feed the optional columns of the task table (settings.TaskColumns).
+
+Today's numbers come from today's file, read when the day is opened or rewritten
+and then kept up one chunk per flush. Earlier days are read once from the other
+day files in the work dir. The open chunk is added when the stats are shown, the
+same way WorkedToday includes the current run.
+*/
+
+// chunks of one task closer than this belong to one session, a run is flushed in many chunks
+const sessionGap = time.Second
+
+// taskDayStats are one task's numbers from today's file
+type taskDayStats struct {
+	Active   time.Duration
+	Sessions int
+	LastEnd  time.Time // end of the task's latest chunk
+}
+
+// taskHistory is one task's time in the day files before today
+type taskHistory struct {
+	Tracked    time.Duration
+	LastWorked time.Time
+	ByWeekday  [7]time.Duration  // by the weekday chunks started on, for suggestions
+	ByHour     [24]time.Duration // by the hour of day chunks started in, for suggestions
+}
+
+// taskStats is what the table shows for one task
+type taskStats struct {
+	Tracked    time.Duration // today
+	Active     time.Duration // today
+	Sessions   int           // today
+	LastWorked time.Time     // zero when never
+	Running    bool
+	Lifetime   time.Duration
+}
+
+// addToDayStats counts one chunk, chunks must come in time order
+func addToDayStats(stats map[string]taskDayStats, chunk store.Chunk) {
+	if chunk.Kind != store.ChunkKindWork {
+		return
+	}
+	day := stats[chunk.TaskName]
+	day.Active += chunk.ActiveTime
+	if chunk.StartedAt.Sub(day.LastEnd) > sessionGap {
+		day.Sessions++
+	}
+	day.LastEnd = latest(day.LastEnd, chunk.FinishedAt)
+	stats[chunk.TaskName] = day
+}
+
+// dayStatsFromChunks counts a whole day file
+func dayStatsFromChunks(chunks []store.Chunk) (stats map[string]taskDayStats) {
+	chunks = slices.Clone(chunks)
+	slices.SortStableFunc(chunks, func(a, b store.Chunk) int { return a.StartedAt.Compare(b.StartedAt) })
+	stats = make(map[string]taskDayStats)
+	for _, chunk := range chunks {
+		addToDayStats(stats, chunk)
+	}
+	return stats
+}
+
+/*
+loadTaskHistory totals every day under workDir, laid out by layout, except today's.
+Bad lines and files that can't be read are logged and left out, the table only
+loses some history.
+*/
+func loadTaskHistory(workDir string, layout *store.Layout, now time.Time) (history map[string]taskHistory) {
+	tl.Log(tl.Info, palette.Blue, "%s task history from '%s'", "Reading", workDir)
+	history = make(map[string]taskHistory)
+	var days int
+	for day, e := range store.NewReader(workDir, layout, store.Lenient).Files() {
+		if e != nil {
+			tl.Log(tl.Warning, palette.Yellow, "%s '%s' in the task history: %s", "Skipping", day.Path, e.Msg)
+			continue
+		}
+		if day.Date.Equal(startOfDay(now)) {
+			continue
+		}
+		for _, chunk := range day.Chunks {
+			if chunk.Kind != store.ChunkKindWork {
+				continue
+			}
+			past := history[chunk.TaskName]
+			past.Tracked += chunk.Duration()
+			past.LastWorked = latest(past.LastWorked, chunk.FinishedAt)
+			started := chunk.StartedAt.Local()
+			past.ByWeekday[started.Weekday()] += chunk.Duration()
+			past.ByHour[started.Hour()] += chunk.Duration()
+			history[chunk.TaskName] = past
+		}
+		days++
+	}
+	tl.Log(tl.Info, palette.Green, "%s task history of %v tasks from %v days", "Read", len(history), days)
+	return history
+}
+
+// taskStatsLocked is every task's stats as of now, open chunk included. Caller holds t.Mutex.
+func (t *TrackerApp) taskStatsLocked(now time.Time) (stats map[string]taskStats) {
+	stats = make(map[string]taskStats, len(t.Tasks))
+	for _, task := range t.Tasks {
+		day, past := t.todayStats[task.Name], t.taskHistory[task.Name]
+		s := taskStats{
+			Tracked:    t.TimeByTask[task.Name],
+			Active:     day.Active,
+			Sessions:   day.Sessions,
+			LastWorked: latest(past.LastWorked, day.LastEnd),
+		}
+		if t.IsRunning && task.Name == t.CurrentTaskName {
+			s.Running = true
+			s.Active += t.ActiveDuringThisChunk
+			if t.ChunkStart.Sub(day.LastEnd) > sessionGap {
+				s.Sessions++ // nothing of this run is flushed yet
+			}
+			s.LastWorked = now
+		}
+		s.Lifetime = past.Tracked + s.Tracked
+		stats[task.Name] = s
+	}
+	return stats
+}
+
+// activityShare is today's active share in percent, -1 when the task wasn't tracked today
+func (s taskStats) activityShare() float64 {
+	if s.Tracked <= 0 {
+		return -1
+	}
+	return getActivityPercentage(s.Active, s.Tracked)
+}
+
+// taskStatText is the cell text of an optional column
+func (t *TrackerApp) taskStatText(column string, s taskStats, now time.Time) string {
+	switch column {
+	case settings.TaskColumnActivity:
+		if s.Tracked <= 0 {
+			return "–"
+		}
+		return t.Locale.Percent(s.activityShare(), 0)
+	case settings.TaskColumnSessions:
+		return strconv.Itoa(s.Sessions)
+	case settings.TaskColumnLastWorked:
+		return t.sinceText(s, now)
+	case settings.TaskColumnLifetime:
+		return t.Locale.DurationMinutes(s.Lifetime)
+	}
+	return ""
+}
+
+// sinceText is "now", "5m ago", "3h ago", "12d ago" or "never"
+func (t *TrackerApp) sinceText(s taskStats, now time.Time) string {
+	since := now.Sub(s.LastWorked)
+	switch {
+	case s.LastWorked.IsZero():
+		return t.Locale.T("LastWorkedNever")
+	case s.Running, since < time.Minute:
+		return t.Locale.T("LastWorkedNow")
+	case since < time.Hour:
+		return t.Locale.T("MinutesAgo", "Count", int(since.Minutes()))
+	case since < 48*time.Hour:
+		return t.Locale.T("HoursAgo", "Count", int(since.Hours()))
+	}
+	return t.Locale.T("DaysAgo", "Count", int(since.Hours()/24))
+}
+
+/*
+sortTasks orders tasks by a column of the table (see settings.TaskSortColumns),
+ties and "" keep the tasks file's order.
+*/
+func sortTasks(tasks []Task, stats map[string]taskStats, sortBy string, descending bool) (sorted []Task) {
+	sorted = slices.Clone(tasks)
+	if sortBy == "" {
+		return sorted
+	}
+	slices.SortStableFunc(sorted, func(a, b Task) (order int) {
+		statsA, statsB := stats[a.Name], stats[b.Name]
+		switch sortBy {
+		case settings.TaskColumnName:
+			order = strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		case settings.TaskColumnCreatedAt:
+			order = a.CreatedAt.Compare(b.CreatedAt)
+		case settings.TaskColumnHours:
+			order = cmp.Compare(statsA.Tracked, statsB.Tracked)
+		case settings.TaskColumnActivity:
+			order = cmp.Compare(statsA.activityShare(), statsB.activityShare())
+		case settings.TaskColumnSessions:
+			order = cmp.Compare(statsA.Sessions, statsB.Sessions)
+		case settings.TaskColumnLastWorked:
+			order = statsA.LastWorked.Compare(statsB.LastWorked)
+		case settings.TaskColumnLifetime:
+			order = cmp.Compare(statsA.Lifetime, statsB.Lifetime)
+		}
+		if descending {
+			return -order
+		}
+		return order
+	})
+	return sorted
+}
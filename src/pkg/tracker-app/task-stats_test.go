@@ -0,0 +1,212 @@
+package trackerapp
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"work-tracker/src/pkg/locale"
+	"work-tracker/src/pkg/settings"
+	"work-tracker/src/pkg/store"
+)
+
+func TestDayStatsFromChunks(t *testing.T) {
+	chunks := []store.Chunk{
+		workChunk("Code", at(9, 0), at(9, 10), 8*time.Minute),
+		workChunk("Code", at(9, 10), at(9, 20), 6*time.Minute), // flushed in the same run
+		pauseChunk(at(9, 20), at(9, 30)),
+		workChunk("Email", at(9, 30), at(9, 40), 5*time.Minute),
+		workChunk("Code", at(9, 40), at(9, 50), 10*time.Minute), // a second session
+		workChunk("Code", at(8, 0), at(8, 30), 20*time.Minute),  // out of order, the first session
+	}
+	got := dayStatsFromChunks(chunks)
+	want := map[string]taskDayStats{
+		"Code":  {Active: 44 * time.Minute, Sessions: 3, LastEnd: at(9, 50)},
+		"Email": {Active: 5 * time.Minute, Sessions: 1, LastEnd: at(9, 40)},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("stats for %v tasks, want %v: %+v", len(got), len(want), got)
+	}
+	for taskName, stats := range want {
+		if got[taskName] != stats {
+			t.Errorf("%s: %+v, want %+v", taskName, got[taskName], stats)
+		}
+	}
+	if chunks[5].TaskName != "Code" || !chunks[5].StartedAt.Equal(at(8, 0)) {
+		t.Error("dayStatsFromChunks reordered its argument")
+	}
+}
+
+func TestLoadTaskHistory(t *testing.T) {
+	layout := store.MustParseLayout(store.DefaultLayout)
+	workDir := t.TempDir()
+	day := func(date, hour, minute int) time.Time {
+		return time.Date(2026, 1, date, hour, minute, 0, 0, time.Local)
+	}
+	writer := store.NewWriter(workDir, layout)
+	files := map[int][]store.Chunk{
+		20: {
+			workChunk("Code", day(20, 9, 0), day(20, 10, 0), 50*time.Minute),
+			workChunk("Email", day(20, 10, 0), day(20, 10, 30), 10*time.Minute),
+		},
+		21: {
+			workChunk("Code", day(21, 9, 0), day(21, 9, 30), 30*time.Minute),
+			pauseChunk(day(21, 9, 30), day(21, 11, 0)),
+		},
+		23: {workChunk("Code", day(23, 9, 0), day(23, 11, 0), time.Hour)}, // today, counted apart
+	}
+	for date, chunks := range files {
+		e := writer.RewriteDay(day(date, 0, 0), chunks)
+		if e != nil {
+			t.Fatalf("write: %s", e.Msg)
+		}
+	}
+	// a bad line costs only itself
+	_, path := layout.DayFilePath(workDir, day(21, 0, 0))
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err == nil {
+		_, err = file.WriteString("{\"task_name\":\n")
+		file.Close()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	history := loadTaskHistory(workDir, layout, day(23, 12, 0))
+	tests := []struct {
+		taskName   string
+		tracked    time.Duration
+		lastWorked time.Time
+	}{
+		{"Code", 90 * time.Minute, day(21, 9, 30)},
+		{"Email", 30 * time.Minute, day(20, 10, 30)},
+		{"Review", 0, time.Time{}},
+	}
+	for _, test := range tests {
+		past := history[test.taskName]
+		if past.Tracked != test.tracked || !past.LastWorked.Equal(test.lastWorked) {
+			t.Errorf("%s: tracked %s, last worked %s; want %s, %s", test.taskName, past.Tracked, past.LastWorked, test.tracked, test.lastWorked)
+		}
+	}
+	if len(history) != 2 {
+		t.Errorf("history of %v tasks, want 2 (pauses aren't tasks)", len(history))
+	}
+}
+
+func TestTaskStatsLocked(t *testing.T) {
+	app := &TrackerApp{
+		Tasks:       []Task{{Name: "Code"}, {Name: "Email"}, {Name: "Review"}},
+		todayStats:  dayStatsFromChunks([]store.Chunk{workChunk("Code", at(9, 0), at(9, 30), 20*time.Minute), workChunk("Email", at(9, 30), at(10, 0), 5*time.Minute)}),
+		TimeByTask:  map[string]time.Duration{"Code": 40 * time.Minute, "Email": 30 * time.Minute}, // Code's includes the open chunk
+		taskHistory: map[string]taskHistory{"Code": {Tracked: 5 * time.Hour, LastWorked: at(-20, 0)}, "Review": {Tracked: time.Hour, LastWorked: at(-44, 0)}},
+	}
+	now := at(10, 10)
+
+	tests := []struct {
+		name     string
+		chunk    time.Time // start of the open chunk on Code
+		taskName string
+		want     taskStats
+	}{
+		{"running, back after a switch", at(10, 0), "Code", taskStats{Tracked: 40 * time.Minute, Active: 23 * time.Minute, Sessions: 2, LastWorked: now, Running: true, Lifetime: 5*time.Hour + 40*time.Minute}},
+		{"running, the same session", at(9, 30), "Code", taskStats{Tracked: 40 * time.Minute, Active: 23 * time.Minute, Sessions: 1, LastWorked: now, Running: true, Lifetime: 5*time.Hour + 40*time.Minute}},
+		{"another task", at(10, 0), "Email", taskStats{Tracked: 40 * time.Minute, Active: 20 * time.Minute, Sessions: 1, LastWorked: at(9, 30), Lifetime: 5*time.Hour + 40*time.Minute}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app.IsRunning, app.CurrentTaskName, app.ChunkStart, app.ActiveDuringThisChunk = true, test.taskName, test.chunk, 3*time.Minute
+			stats := app.taskStatsLocked(now)
+			if stats["Code"] != test.want {
+				t.Errorf("Code: %+v, want %+v", stats["Code"], test.want)
+			}
+			want := taskStats{Lifetime: time.Hour, LastWorked: at(-44, 0)}
+			if stats["Review"] != want {
+				t.Errorf("Review, only worked on before today: %+v, want %+v", stats["Review"], want)
+			}
+		})
+	}
+}
+
+func TestTaskStatText(t *testing.T) {
+	l, e := locale.New(locale.Options{Language: "en"})
+	if e != nil {
+		t.Fatalf("locale: %s", e.Msg)
+	}
+	app := &TrackerApp{Locale: l}
+	now := at(12, 0)
+	tests := []struct {
+		column string
+		stats  taskStats
+		want   string
+	}{
+		{settings.TaskColumnActivity, taskStats{}, "–"},
+		{settings.TaskColumnActivity, taskStats{Tracked: time.Hour, Active: 45 * time.Minute}, "75%"},
+		{settings.TaskColumnSessions, taskStats{Sessions: 3}, "3"},
+		{settings.TaskColumnLifetime, taskStats{Lifetime: 65 * time.Minute}, "1h 05m"},
+		{settings.TaskColumnLastWorked, taskStats{}, "never"},
+		{settings.TaskColumnLastWorked, taskStats{Running: true, LastWorked: now}, "now"},
+		{settings.TaskColumnLastWorked, taskStats{LastWorked: now.Add(-30 * time.Second)}, "now"},
+		{settings.TaskColumnLastWorked, taskStats{LastWorked: now.Add(-5 * time.Minute)}, "5m ago"},
+		{settings.TaskColumnLastWorked, taskStats{LastWorked: now.Add(-3 * time.Hour)}, "3h ago"},
+		{settings.TaskColumnLastWorked, taskStats{LastWorked: now.Add(-47 * time.Hour)}, "47h ago"},
+		{settings.TaskColumnLastWorked, taskStats{LastWorked: now.Add(-72 * time.Hour)}, "3d ago"},
+	}
+	for _, test := range tests {
+		t.Run(test.column+" "+test.want, func(t *testing.T) {
+			if got := app.taskStatText(test.column, test.stats, now); got != test.want {
+				t.Errorf("taskStatText(%s) = %q, want %q", test.column, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSortTasks(t *testing.T) {
+	tasks := []Task{
+		{Name: "email", CreatedAt: at(9, 0)},
+		{Name: "Code", CreatedAt: at(8, 0)},
+		{Name: "Review", CreatedAt: at(10, 0)},
+		{Name: "Admin", CreatedAt: at(7, 0)},
+	}
+	stats := map[string]taskStats{
+		"email":  {Tracked: time.Hour, Active: 30 * time.Minute, Sessions: 2, LastWorked: at(11, 0), Lifetime: 3 * time.Hour},
+		"Code":   {Tracked: 2 * time.Hour, Active: 90 * time.Minute, Sessions: 2, LastWorked: at(10, 0), Lifetime: 10 * time.Hour},
+		"Review": {Tracked: time.Hour, Active: 50 * time.Minute, Sessions: 1, LastWorked: at(9, 0), Lifetime: time.Hour},
+		// Admin was never tracked
+	}
+	tests := []struct {
+		sortBy     string
+		descending bool
+		want       []string
+	}{
+		{"", false, []string{"email", "Code", "Review", "Admin"}},
+		{"", true, []string{"email", "Code", "Review", "Admin"}},
+		{settings.TaskColumnName, false, []string{"Admin", "Code", "email", "Review"}},
+		{settings.TaskColumnName, true, []string{"Review", "email", "Code", "Admin"}},
+		{settings.TaskColumnCreatedAt, false, []string{"Admin", "Code", "email", "Review"}},
+		{settings.TaskColumnHours, false, []string{"Admin", "email", "Review", "Code"}}, // ties keep the file's order
+		{settings.TaskColumnHours, true, []string{"Code", "email", "Review", "Admin"}},
+		{settings.TaskColumnActivity, false, []string{"Admin", "email", "Code", "Review"}}, // untracked first
+		{settings.TaskColumnSessions, true, []string{"email", "Code", "Review", "Admin"}},
+		{settings.TaskColumnLastWorked, true, []string{"email", "Code", "Review", "Admin"}},
+		{settings.TaskColumnLifetime, false, []string{"Admin", "Review", "email", "Code"}},
+	}
+	for _, test := range tests {
+		name := test.sortBy
+		if test.descending {
+			name += " descending"
+		}
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, task := range sortTasks(tasks, stats, test.sortBy, test.descending) {
+				got = append(got, task.Name)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("sortTasks = %v, want %v", got, test.want)
+			}
+		})
+	}
+	if tasks[0].Name != "email" {
+		t.Error("sortTasks reordered its argument")
+	}
+}
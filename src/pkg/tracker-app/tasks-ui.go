@@ -1,8 +1,7 @@
 package trackerapp
 
 import (
-	"image/color"
-	"maps"
+	"slices"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -14,219 +13,355 @@ import (
 
 	tl "github.com/tuumbleweed/tintlog/logger"
 	"github.com/tuumbleweed/tintlog/palette"
+
+	"work-tracker/src/pkg/settings"
 )
 
-// column widths (px) – tweak to taste
+// columns of the task table that are neither sortable nor optional
 const (
-	colPlayButtonWidth  = 80
-	colNameWidth        = 260
-	colDescriptionWidth = 420
-	colCreatedAtWidth   = 260
-	colHoursWidth       = 100
-	// single-line row height
-	rowHeight = 50
+	taskColumnPlay        = "play"
+	taskColumnDescription = "description"
 )
 
-func (t *TrackerApp) makeTasksUI(tasks []Task) *fyne.Container {
-	t.TableRows = make(map[string]TableRow)
+// starting column widths (px), the user can drag them wider or narrower
+var taskColumnWidths = map[string]float32{
+	taskColumnPlay:                80,
+	settings.TaskColumnName:       260,
+	taskColumnDescription:         420,
+	settings.TaskColumnCreatedAt:  260,
+	settings.TaskColumnHours:      100,
+	settings.TaskColumnActivity:   120,
+	settings.TaskColumnSessions:   120,
+	settings.TaskColumnLastWorked: 120,
+	settings.TaskColumnLifetime:   120,
+}
+
+// catalog ids of the column headers
+var taskColumnLabels = map[string]string{
+	settings.TaskColumnName:       "ColumnTask",
+	taskColumnDescription:         "ColumnDescription",
+	settings.TaskColumnCreatedAt:  "ColumnCreatedAt",
+	settings.TaskColumnHours:      "ColumnHours",
+	settings.TaskColumnActivity:   "ColumnActivity",
+	settings.TaskColumnSessions:   "ColumnSessions",
+	settings.TaskColumnLastWorked: "ColumnLastWorked",
+	settings.TaskColumnLifetime:   "ColumnLifetime",
+}
+
+/*
+taskTableView is what TaskTable shows. The table only asks for the cells on
+screen, so everything it needs is kept here and read on the UI goroutine only.
+Texts of the ticking columns are cached: a tick refreshes just the cells whose
+text changed (see refreshTaskCells).
+*/
+type taskTableView struct {
+	tasks   []Task   // rows, sorted
+	columns []string // taskColumnPlay, settings.TaskColumnName, ... and the shown optional ones
+	texts   map[widget.TableCellID]string
+	running string // task with the pause button, "" when stopped
+	current string // highlighted task
+}
+
+/*
+makeTasksUI builds the title and the task table. The table is virtualized, so
+hundreds of tasks cost no more than the rows on screen, and it scrolls inside
+the window instead of growing it. Rows are only re-sorted when the table is
+rebuilt (see rebuildTasksUI), so they don't jump around while the numbers tick.
+*/
+func (t *TrackerApp) makeTasksUI() *fyne.Container {
 	// Title
-	sectionTitle := canvas.NewText("Tasks", theme.Color(theme.ColorNameForeground))
+	sectionTitle := canvas.NewText(t.Locale.T("Tasks"), theme.Color(theme.ColorNameForeground))
 	sectionTitle.Alignment = fyne.TextAlignCenter
 	sectionTitle.TextStyle = fyne.TextStyle{Bold: true}
 	sectionTitle.TextSize = theme.TextSize() * 1.6
+	t.TasksTitle = sectionTitle
+
+	// picks the optional columns, right of the title
+	columnsButton := widget.NewButtonWithIcon(t.Locale.T("ColumnsMenu"), theme.ListIcon(), nil)
+	columnsButton.Importance = widget.LowImportance
+	columnsButton.OnTapped = func() {
+		position := fyne.CurrentApp().Driver().AbsolutePositionForObject(columnsButton).AddXY(0, columnsButton.Size().Height)
+		widget.ShowPopUpMenuAtPosition(fyne.NewMenu("", t.taskColumnItems()...), t.Window.Canvas(), position)
+	}
+	titleRow := container.NewStack(sectionTitle, container.NewHBox(layout.NewSpacer(), columnsButton))
 
-	// header
-	leftHeader := container.NewHBox(
-		fixedCell(labelHeader(""), colPlayButtonWidth),
-		fixedCell(labelHeader("Task"), colNameWidth),
+	table := widget.NewTable(
+		func() (rows, columns int) { return len(t.taskView.tasks), len(t.taskView.columns) },
+		newTaskCell,
+		t.updateTaskCell,
 	)
-	rightHeader := container.NewHBox(
-		fixedCell(labelHeader("Created At"), colCreatedAtWidth),
-		fixedCell(labelHeader("Hours"), colHoursWidth),
-	)
-	descHead := minWidth(labelHeader("Description"), colDescriptionWidth) // e.g. colDescriptionWidth px minimum
-	header := container.NewBorder(nil, nil, leftHeader, rightHeader, descHead)
-
-	// rows
-	rows := container.NewVBox()
-	for _, task := range tasks {
-		// left group: ▶ + Task (both fixed widths)
-		nameLabel, nameCanvas := fixedCellCenteredTruncated(task.Name, colNameWidth)
-		rowPlayButton, playCell := smallButton(theme.MediaPlayIcon(), nil)
-		leftBox := container.NewHBox(playCell, nameCanvas)
-
-		// need to use mutex with those functions
-		rowPlayButton.OnTapped = func() {
-			t.Mutex.Lock()
-			isRunning := t.IsRunning
-			allTableRows := t.TableRows
-			previousTaskName := t.CurrentTaskName
-			t.Mutex.Unlock()
-			var newTaskName string = task.Name
-			var taskRunStart time.Time
-
-			if isRunning {
-				// it's running which means we either stop it or start another one
-
-				// first showStopped all the buttons
-
-				// if previous task name NOT equal to the new one - keep running,
-				// then showRunning the playButton
-				// if previous task name IS equal to the new one - flipSwitch (onButtonTapped)
-
-				// we can first showStopped all of the buttons
-				for _, tableRow := range allTableRows {
-					showStopped(tableRow.Button)
-				}
-				// then handle the differences
-				if previousTaskName != newTaskName {
-					showRunning(rowPlayButton)
-
-					// make sure to update t.TaskRunStart so that new task does not receive additional time
-					taskRunStart = time.Now()
-					newTaskName = task.Name
-					tl.Log(tl.Info, palette.Cyan, "%s. Previous: '%s', New: '%s'", "Switching tasks", previousTaskName, newTaskName)
-				} else {
-					t.onButtonTapped()
-					newTaskName = "" // reset newTaskName on stopping
-					tl.Log(tl.Info, palette.Cyan, "%s. Previous: '%s', New: '%s'", "Stopping task", previousTaskName, newTaskName)
-				}
-			} else {
-				// it's not running which means we are starting a new task
-
-				// flip the switch then show running
-
-				t.onButtonTapped()
-				showRunning(rowPlayButton)
-				newTaskName = task.Name
-				tl.Log(tl.Info, palette.Cyan, "%s. Previous: '%s', New: '%s'", "Startng new task", previousTaskName, newTaskName)
-			}
-
-			// refresh state and then flush
-			t.refreshActivityState()
-			t.refreshUIState()
-			t.flushChunkIfRunning()
-			// now set t.CurrentTaskName to newTaskName
-			t.Mutex.Lock()
-			t.CurrentTaskName = newTaskName
-			if !taskRunStart.IsZero() {
-				t.TaskRunStart = taskRunStart
-				// save the progress
-				maps.Copy(t.TimeByTaskBeforeStartingThisRun, t.TimeByTask)
-			}
-			t.Mutex.Unlock()
-			t.refreshUIState()
-			t.updateInterface() // update interface to show current task name right away
+	// header, click a column to sort by it, drag the gap between two to resize
+	table.ShowHeaderRow = true
+	table.CreateHeader = newTaskHeader
+	table.UpdateHeader = t.updateTaskHeader
+	table.OnSelected = func(id widget.TableCellID) {
+		table.Unselect(id) // rows are not selectable, the play button starts them
+		switch t.taskView.columns[id.Col] {
+		case settings.TaskColumnName, taskColumnDescription:
+			t.showTaskDetail(t.taskView.tasks[id.Row])
 		}
+	}
+	t.TaskTable = table
+	t.rebuildTasksUI()
 
-		// center: Description (expands; ellipsis)
-		descriptionLabel, descriptionCanvas := flexVCenterTruncated(task.Description)
+	// the detail pane stays hidden until a task is clicked, see task-detail.go
+	t.TaskDetail = container.NewStack()
+	t.TaskDetail.Hide()
+	t.TaskSplit = container.NewHSplit(table, t.TaskDetail)
+	t.TaskSplit.Offset = 0.68
 
-		// right group: Created + Hours (both fixed)
-		createdAtLabel, createdAtCanvas := fixedCellCenteredTruncated(task.CreatedAt.Format("Mon Jan 02 2006 15:04:05"), colCreatedAtWidth)
-		timeLabel, timeCanvas := fixedCellCenteredTruncated(t.TimeByTask[task.Name].String(), colHoursWidth)
-		rightBox := container.NewHBox(createdAtCanvas, timeCanvas)
+	return container.NewBorder(titleRow, nil, nil, nil, t.TaskSplit)
+}
 
-		t.TableRows[task.Name] = TableRow{
-			Button:           rowPlayButton,
-			NameLabel:        nameLabel,
-			DescriptionLabel: descriptionLabel,
-			CreatedAtLabel:   createdAtLabel,
-			TimeLabel:        timeLabel,
-		}
+// newTaskCell holds what any column needs: a label, or the play button
+func newTaskCell() fyne.CanvasObject {
+	label := widget.NewLabel("")
+	label.Wrapping = fyne.TextWrapOff
+	label.Truncation = fyne.TextTruncateEllipsis // "…" when too long
+	button := widget.NewButtonWithIcon("", theme.MediaPlayIcon(), nil)
+	return container.NewStack(label, container.NewCenter(button))
+}
 
-		row := container.NewBorder(nil, nil, leftBox, rightBox, descriptionCanvas)
-		rows.Add(row)
+func (t *TrackerApp) updateTaskCell(id widget.TableCellID, cell fyne.CanvasObject) {
+	view := &t.taskView
+	if id.Row >= len(view.tasks) || id.Col >= len(view.columns) {
+		return
 	}
+	task := view.tasks[id.Row]
+	objects := cell.(*fyne.Container).Objects
+	label := objects[0].(*widget.Label)
+	buttonCell := objects[1].(*fyne.Container)
+
+	if view.columns[id.Col] == taskColumnPlay {
+		label.Hide()
+		buttonCell.Show()
+		button := buttonCell.Objects[0].(*widget.Button)
+		// start, switch to or stop this task
+		button.OnTapped = func() { t.toggleTask(task.Name) }
+		setRunningLook(button, task.Name == view.running)
+		return
+	}
+	buttonCell.Hide()
+	label.Show()
+	label.Importance = widget.MediumImportance
+	if task.Name == view.current {
+		label.Importance = widget.HighImportance
+	}
+	label.SetText(t.taskCellText(task, view.columns[id.Col], id))
+}
 
-	return container.NewVBox(sectionTitle, header, rows)
+// taskCellText is a cell's text, the ticking columns come from the cache
+func (t *TrackerApp) taskCellText(task Task, column string, id widget.TableCellID) string {
+	switch column {
+	case settings.TaskColumnName:
+		return task.Name
+	case taskColumnDescription:
+		return task.Description
+	case settings.TaskColumnCreatedAt:
+		return t.Locale.Date(task.CreatedAt) + " " + t.Locale.ClockSeconds(task.CreatedAt)
+	}
+	return t.taskView.texts[id]
 }
 
-func labelHeader(s string) *widget.Label {
-	l := widget.NewLabelWithStyle(s, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
-	l.Wrapping = fyne.TextWrap(fyne.TextTruncateClip)
-	return l
+// ticks reports whether the column's numbers change while tracking
+func ticks(column string) bool {
+	return column == settings.TaskColumnHours || slices.Contains(settings.TaskColumns, column)
 }
 
-func fixedCell(obj fyne.CanvasObject, w int) fyne.CanvasObject {
-	return container.New(
-		layout.NewGridWrapLayout(fyne.NewSize(float32(w), rowHeight)),
-		obj,
-	)
+func newTaskHeader() fyne.CanvasObject {
+	button := widget.NewButton("", nil)
+	button.Importance = widget.LowImportance
+	button.Alignment = widget.ButtonAlignLeading
+	button.IconPlacement = widget.ButtonIconTrailingText
+	return button
+}
+
+// updateTaskHeader labels a header, with an arrow on the current sort
+func (t *TrackerApp) updateTaskHeader(id widget.TableCellID, header fyne.CanvasObject) {
+	button := header.(*widget.Button)
+	if id.Col < 0 || id.Col >= len(t.taskView.columns) {
+		return
+	}
+	column := t.taskView.columns[id.Col]
+	table := t.settingsSnapshot().TaskTable
+
+	button.Text = ""
+	if labelID, found := taskColumnLabels[column]; found {
+		button.Text = t.Locale.T(labelID)
+	}
+	button.Icon = nil
+	if table.SortBy == column {
+		button.Icon = theme.MenuDropUpIcon()
+		if table.SortDescending {
+			button.Icon = theme.MenuDropDownIcon()
+		}
+	}
+	button.OnTapped = nil
+	if slices.Contains(settings.TaskSortColumns, column) {
+		button.OnTapped = func() { t.sortTasksBy(column) }
+	}
+	button.Refresh()
 }
 
-// minWidth wraps obj so it can expand but never be narrower than minW.
-func minWidth(obj fyne.CanvasObject, minW int) fyne.CanvasObject {
-	shim := canvas.NewRectangle(color.Transparent)
-	shim.SetMinSize(fyne.NewSize(float32(minW), rowHeight)) // rowH if you want a row-height floor
-	return container.NewStack(shim, obj)                    // shim contributes MinSize; obj draws on top
+/*
+rebuildTasksUI re-reads the tasks, columns and sort order into the table.
+Column widths go back to their defaults only when the columns changed.
+Must run on the UI goroutine.
+*/
+func (t *TrackerApp) rebuildTasksUI() {
+	view := &t.taskView
+	table := t.settingsSnapshot().TaskTable
+	columns := taskTableColumns(table)
+
+	now := time.Now()
+	t.Mutex.Lock()
+	stats := t.taskStatsLocked(now)
+	running, current := t.CurrentTaskName, t.CurrentTaskName
+	if !t.IsRunning {
+		running = ""
+	}
+	t.Mutex.Unlock()
+
+	if !slices.Equal(view.columns, columns) {
+		for i, column := range columns {
+			t.TaskTable.SetColumnWidth(i, taskColumnWidths[column])
+		}
+	}
+	view.tasks = sortTasks(t.Tasks, stats, table.SortBy, table.SortDescending)
+	view.columns = columns
+	view.texts = make(map[widget.TableCellID]string)
+	t.refreshTaskCells(stats, now, running, current)
+	t.TaskTable.Refresh()
 }
 
-// fixedTruncVCenterCell gives obj the full cell size (so truncation works) and
-// centers it vertically with spacers. Text stays left-aligned.
-func fixedCellCenteredTruncated(text string, w int) (label *widget.Label, canvas fyne.CanvasObject) {
-	l := widget.NewLabel(text)
-	l.Wrapping = fyne.TextWrapOff            // no wrapping
-	l.Truncation = fyne.TextTruncateEllipsis // "…" when too long
-	l.Alignment = fyne.TextAlignLeading      // left aligned
+/*
+refreshTaskCells updates the ticking columns and the running task's look,
+refreshing only the cells that changed. Must run on the UI goroutine.
+*/
+func (t *TrackerApp) refreshTaskCells(stats map[string]taskStats, now time.Time, running, current string) {
+	view := &t.taskView
+	lookChanged := []string{}
+	if view.running != running || view.current != current {
+		lookChanged = []string{view.running, view.current, running, current}
+		view.running, view.current = running, current
+	}
 
-	// VBox with spacers => vertical center; MaxLayout => child gets full cell size
-	v := container.NewVBox(layout.NewSpacer(), l, layout.NewSpacer())
-	return l, container.New(
-		layout.NewGridWrapLayout(fyne.NewSize(float32(w), rowHeight)),
-		container.New(layout.NewStackLayout(), v), // make v fill the fixed cell
-	)
+	for row, task := range view.tasks {
+		restyle := slices.Contains(lookChanged, task.Name)
+		for col, column := range view.columns {
+			id := widget.TableCellID{Row: row, Col: col}
+			changed := restyle
+			if ticks(column) {
+				text := formatDuration(stats[task.Name].Tracked)
+				if column != settings.TaskColumnHours {
+					text = t.taskStatText(column, stats[task.Name], now)
+				}
+				if cached, found := view.texts[id]; !found || cached != text {
+					view.texts[id] = text
+					changed = true
+				}
+			}
+			if changed {
+				t.TaskTable.RefreshItem(id) // no-op for cells off screen
+			}
+		}
+	}
 }
 
-func flexVCenterTruncated(text string) (label *widget.Label, canvas fyne.CanvasObject) {
-	l := widget.NewLabel(text)
-	l.Wrapping = fyne.TextWrapOff
-	l.Truncation = fyne.TextTruncateEllipsis
-	l.Alignment = fyne.TextAlignLeading
+// taskColumnItems are the optional columns with the shown ones checked
+func (t *TrackerApp) taskColumnItems() (items []*fyne.MenuItem) {
+	table := t.settingsSnapshot().TaskTable
+	for _, column := range settings.TaskColumns {
+		item := fyne.NewMenuItem(t.Locale.T(taskColumnLabels[column]), func() { t.toggleTaskColumn(column) })
+		item.Checked = table.Shows(column)
+		items = append(items, item)
+	}
+	return items
+}
 
-	v := container.NewVBox(layout.NewSpacer(), l, layout.NewSpacer())
-	// Fill all available center space in Border using StackLayout:
-	return l, container.New(layout.NewStackLayout(), v)
+// taskTableColumns are the fixed columns followed by the shown optional ones, in settings.TaskColumns order
+func taskTableColumns(table settings.TaskTableSettings) (columns []string) {
+	columns = []string{taskColumnPlay, settings.TaskColumnName, taskColumnDescription, settings.TaskColumnCreatedAt, settings.TaskColumnHours}
+	for _, column := range settings.TaskColumns {
+		if table.Shows(column) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
 }
 
-// tinyButton centers the icon button inside a fixed cell.
-// Return both: the actual button (to mutate later) and the wrapped cell for layout.
-func smallButton(icon fyne.Resource, onTap func()) (*widget.Button, fyne.CanvasObject) {
-	btn := widget.NewButtonWithIcon("", icon, onTap)
+// sortTasksBy cycles a column through ascending, descending and the tasks file's order. Must run on the UI goroutine.
+func (t *TrackerApp) sortTasksBy(column string) {
+	t.setTaskTable(sortedBy(t.settingsSnapshot().TaskTable, column))
+}
 
-	centered := container.NewCenter(btn)
-	cell := container.New(
-		layout.NewGridWrapLayout(fyne.NewSize(colPlayButtonWidth, rowHeight)),
-		centered,
-	)
-	return btn, cell
+// sortedBy is table with column one step further in its sort cycle
+func sortedBy(table settings.TaskTableSettings, column string) settings.TaskTableSettings {
+	switch {
+	case table.SortBy != column:
+		table.SortBy, table.SortDescending = column, false
+	case !table.SortDescending:
+		table.SortDescending = true
+	default:
+		table.SortBy, table.SortDescending = "", false
+	}
+	return table
 }
 
-// button becomes orange, pause icon displayed
-func showRunning(button *widget.Button) {
-	fyne.Do(func() {
-		button.Importance = widget.WarningImportance // Importance change needs a Refresh()
-		button.SetIcon(theme.MediaPauseIcon())       // SetIcon calls Refresh
-	})
+// toggleTaskColumn shows or hides an optional column. Must run on the UI goroutine.
+func (t *TrackerApp) toggleTaskColumn(column string) {
+	t.setTaskTable(withColumnToggled(t.settingsSnapshot().TaskTable, column))
 }
 
-// button becomes grey, play icon displayed
-func showStopped(button *widget.Button) {
-	fyne.Do(func() {
-		button.Importance = widget.MediumImportance // Importance change needs a Refresh()
-		button.SetIcon(theme.MediaPlayIcon())       // SetIcon calls Refresh
-	})
+// withColumnToggled is current with the optional column shown or hidden, no longer sorted by it once hidden
+func withColumnToggled(current settings.TaskTableSettings, column string) (table settings.TaskTableSettings) {
+	table = current
+	shown := !table.Shows(column)
+	table.Columns = nil
+	for _, other := range settings.TaskColumns {
+		if (other == column && shown) || (other != column && current.Shows(other)) {
+			table.Columns = append(table.Columns, other)
+		}
+	}
+	if !shown && table.SortBy == column {
+		table.SortBy, table.SortDescending = "", false // a hidden column would be a puzzling order
+	}
+	return table
 }
 
-func setRowImportance(tableRow TableRow, widgetImportance widget.Importance) {
-	fyne.Do(func() {
-		tableRow.NameLabel.Importance = widgetImportance
-		tableRow.DescriptionLabel.Importance = widgetImportance
-		tableRow.CreatedAtLabel.Importance = widgetImportance
-		tableRow.TimeLabel.Importance = widgetImportance
-		tableRow.NameLabel.Refresh()
-		tableRow.DescriptionLabel.Refresh()
-		tableRow.CreatedAtLabel.Refresh()
-		tableRow.TimeLabel.Refresh()
+/*
+setTaskTable applies new columns or sorting, rebuilds the table and saves the
+change. Only task_table is written to the settings file.
+*/
+func (t *TrackerApp) setTaskTable(table settings.TaskTableSettings) {
+	tl.Log(tl.Info, palette.Blue, "%s task table. Columns: %v, sort by: '%s', descending: %v", "Changing", table.Columns, table.SortBy, table.SortDescending)
+	t.Mutex.Lock()
+	t.Settings.TaskTable = table
+	t.Mutex.Unlock()
+	t.rebuildTasksUI()
+
+	e := settings.Update(t.SettingsPath, func(saved *settings.Settings) {
+		saved.TaskTable = table
 	})
+	if e != nil {
+		tl.Log(tl.Warning, palette.Yellow, "%s: %s", "Task table not saved", e.Msg)
+		return
+	}
+	tl.Log(tl.Info1, palette.Green, "%s task table to '%s'", "Saved", t.SettingsPath)
+}
+
+/*
+setRunningLook: orange with a pause icon when running, grey with a play icon otherwise.
+Must run on the UI goroutine. Skips the refresh when the look is already right.
+*/
+func setRunningLook(button *widget.Button, running bool) {
+	importance, icon := widget.MediumImportance, theme.MediaPlayIcon()
+	if running {
+		importance, icon = widget.WarningImportance, theme.MediaPauseIcon()
+	}
+	if button.Importance == importance {
+		return
+	}
+	button.Importance = importance // Importance change needs a Refresh()
+	button.SetIcon(icon)           // SetIcon calls Refresh
 }
@@ -0,0 +1,98 @@
+package trackerapp
+
+import (
+	"slices"
+	"testing"
+
+	"work-tracker/src/pkg/settings"
+)
+
+func TestTaskTableColumns(t *testing.T) {
+	fixed := []string{taskColumnPlay, settings.TaskColumnName, taskColumnDescription, settings.TaskColumnCreatedAt, settings.TaskColumnHours}
+	tests := []struct {
+		name    string
+		columns []string
+		want    []string
+	}{
+		{"none shown", nil, fixed},
+		{"in settings order, not the file's", []string{settings.TaskColumnLifetime, settings.TaskColumnActivity}, append(slices.Clone(fixed), settings.TaskColumnActivity, settings.TaskColumnLifetime)},
+		{"unknown ones left out", []string{"velocity", settings.TaskColumnSessions}, append(slices.Clone(fixed), settings.TaskColumnSessions)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := taskTableColumns(settings.TaskTableSettings{Columns: test.columns})
+			if !slices.Equal(got, test.want) {
+				t.Errorf("taskTableColumns = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSortedBy(t *testing.T) {
+	// a column goes ascending, descending, then back to the file's order
+	var table settings.TaskTableSettings
+	steps := []struct {
+		column     string
+		sortBy     string
+		descending bool
+	}{
+		{settings.TaskColumnHours, settings.TaskColumnHours, false},
+		{settings.TaskColumnHours, settings.TaskColumnHours, true},
+		{settings.TaskColumnHours, "", false},
+		{settings.TaskColumnName, settings.TaskColumnName, false},
+		{settings.TaskColumnName, settings.TaskColumnName, true},
+		{settings.TaskColumnHours, settings.TaskColumnHours, false}, // another column starts over
+	}
+	for i, step := range steps {
+		table = sortedBy(table, step.column)
+		if table.SortBy != step.sortBy || table.SortDescending != step.descending {
+			t.Fatalf("step %v, sorting by %s: %q descending %v, want %q descending %v", i, step.column, table.SortBy, table.SortDescending, step.sortBy, step.descending)
+		}
+	}
+}
+
+func TestWithColumnToggled(t *testing.T) {
+	tests := []struct {
+		name    string
+		table   settings.TaskTableSettings
+		column  string
+		columns []string
+		sortBy  string
+	}{
+		{"shown", settings.TaskTableSettings{Columns: []string{settings.TaskColumnLifetime}}, settings.TaskColumnActivity,
+			[]string{settings.TaskColumnActivity, settings.TaskColumnLifetime}, ""},
+		{"hidden", settings.TaskTableSettings{Columns: []string{settings.TaskColumnActivity, settings.TaskColumnLifetime}}, settings.TaskColumnActivity,
+			[]string{settings.TaskColumnLifetime}, ""},
+		{"hidden while sorting by it", settings.TaskTableSettings{Columns: []string{settings.TaskColumnSessions}, SortBy: settings.TaskColumnSessions, SortDescending: true}, settings.TaskColumnSessions,
+			nil, ""},
+		{"hidden while sorting by another", settings.TaskTableSettings{Columns: []string{settings.TaskColumnSessions}, SortBy: settings.TaskColumnHours}, settings.TaskColumnSessions,
+			nil, settings.TaskColumnHours},
+		{"shown while sorting by it", settings.TaskTableSettings{SortBy: settings.TaskColumnLifetime}, settings.TaskColumnLifetime,
+			[]string{settings.TaskColumnLifetime}, settings.TaskColumnLifetime},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			before := slices.Clone(test.table.Columns)
+			got := withColumnToggled(test.table, test.column)
+			if !slices.Equal(got.Columns, test.columns) || got.SortBy != test.sortBy {
+				t.Errorf("columns %v sorted by %q, want %v sorted by %q", got.Columns, got.SortBy, test.columns, test.sortBy)
+			}
+			if !slices.Equal(test.table.Columns, before) {
+				t.Error("withColumnToggled changed the current columns")
+			}
+		})
+	}
+}
+
+func TestTicks(t *testing.T) {
+	for _, column := range []string{taskColumnPlay, settings.TaskColumnName, taskColumnDescription, settings.TaskColumnCreatedAt} {
+		if ticks(column) {
+			t.Errorf("%s ticks", column)
+		}
+	}
+	for _, column := range append([]string{settings.TaskColumnHours}, settings.TaskColumns...) {
+		if !ticks(column) {
+			t.Errorf("%s doesn't tick", column)
+		}
+	}
+}
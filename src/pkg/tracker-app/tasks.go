@@ -13,9 +13,10 @@ import (
 )
 
 type Task struct {
-	Name        string    `json:"task_name"`
-	Description string    `json:"task_description"`
-	CreatedAt   time.Time `json:"created_at"`
+	Name        string     `json:"task_name"`
+	Description string     `json:"task_description"` // markdown, shown in the detail pane
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"` // nil while the task is open
 }
 
 func loadTasks(path string) (tasks []Task, e *xerr.Error) {
@@ -4,17 +4,74 @@ import (
 	"image/color"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/theme"
+
+	"work-tracker/src/pkg/settings"
+)
+
+// tracker-specific color names, resolved by scaledTheme per variant
+const (
+	colorNameActive       fyne.ThemeColorName = "trackerActive"       // running clock and task name
+	colorNameActivity0    fyne.ThemeColorName = "trackerActivity0"    // activity bar at 0%
+	colorNameActivity50   fyne.ThemeColorName = "trackerActivity50"   // activity bar at 50%
+	colorNameActivity75   fyne.ThemeColorName = "trackerActivity75"   // activity bar at 75%
+	colorNameActivity100  fyne.ThemeColorName = "trackerActivity100"  // activity bar at 100%
+	colorNameActivityText fyne.ThemeColorName = "trackerActivityText" // percentage drawn over the bar
 )
 
+/*
+Palette for both variants. Light keeps the original colors;
+dark uses deeper bar colors so the light foreground text stays readable on top of them.
+*/
+var trackerPalette = map[fyne.ThemeVariant]map[fyne.ThemeColorName]color.NRGBA{
+	theme.VariantLight: {
+		colorNameActive:       {R: 0, G: 125, B: 255, A: 255},
+		colorNameActivity0:    {R: 220, G: 60, B: 60, A: 255},
+		colorNameActivity50:   {R: 235, G: 190, B: 50, A: 255},
+		colorNameActivity75:   {R: 60, G: 180, B: 90, A: 255},
+		colorNameActivity100:  {R: 20, G: 180, B: 45, A: 255},
+		colorNameActivityText: {R: 33, G: 33, B: 33, A: 255},
+	},
+	theme.VariantDark: {
+		colorNameActive:       {R: 100, G: 175, B: 255, A: 255},
+		colorNameActivity0:    {R: 170, G: 45, B: 45, A: 255},
+		colorNameActivity50:   {R: 165, G: 125, B: 20, A: 255},
+		colorNameActivity75:   {R: 40, G: 130, B: 65, A: 255},
+		colorNameActivity100:  {R: 15, G: 125, B: 35, A: 255},
+		colorNameActivityText: {R: 240, G: 240, B: 240, A: 255},
+	},
+}
+
 /* ---- Minimal theme scaler ---- */
 type scaledTheme struct {
 	base   fyne.Theme
 	factor float32
+	mode   string      // settings.ThemeModeLight/Dark/System
+	accent color.Color // overrides primary and active colors when set
+}
+
+// variant resolves the variant to draw with; "system" keeps whatever fyne detected
+func (t scaledTheme) variant(systemVariant fyne.ThemeVariant) fyne.ThemeVariant {
+	switch t.mode {
+	case settings.ThemeModeDark:
+		return theme.VariantDark
+	case settings.ThemeModeSystem:
+		return systemVariant
+	default:
+		return theme.VariantLight
+	}
 }
 
-func (t scaledTheme) Color(n fyne.ThemeColorName, _ fyne.ThemeVariant) color.Color {
-	return t.base.Color(n, theme.VariantLight)
+func (t scaledTheme) Color(n fyne.ThemeColorName, v fyne.ThemeVariant) color.Color {
+	v = t.variant(v)
+	if t.accent != nil && (n == theme.ColorNamePrimary || n == colorNameActive) {
+		return t.accent
+	}
+	if c, ok := trackerPalette[v][n]; ok {
+		return c
+	}
+	return t.base.Color(n, v)
 }
 func (t scaledTheme) Font(st fyne.TextStyle) fyne.Resource {
 	return t.base.Font(st)
@@ -29,6 +86,58 @@ func (t scaledTheme) Size(n fyne.ThemeSizeName) float32 {
 }
 
 // when element is active
-func getActiveColor() color.NRGBA {
-	return color.NRGBA{R: 0, G: 125, B: 255, A: 255}
+func getActiveColor() color.Color {
+	return theme.Color(colorNameActive)
+}
+
+// applyTheme (re)installs scaledTheme from the current settings, e.g. after they changed.
+func (t *TrackerApp) applyTheme() {
+	current := t.settingsSnapshot()
+	var accent color.Color
+	accentColor, err := settings.ParseHexColor(current.AccentColor)
+	if err == nil {
+		accent = accentColor
+	}
+	t.App.Settings().SetTheme(scaledTheme{
+		base:   t.BaseTheme,
+		factor: current.ThemeScale,
+		mode:   current.ThemeMode,
+		accent: accent,
+	})
+}
+
+/*
+onThemeChanged runs when fyne reports a settings change: our own SetTheme,
+or the desktop switching between light and dark while in "system" mode.
+canvas.Text sizes and colors are absolute, so they have to follow by hand.
+*/
+func (t *TrackerApp) onThemeChanged(fyne.Settings) {
+	t.applyTextSizes()
+	for _, text := range []*canvas.Text{t.Title, t.TaskLabel, t.Clock, t.FocusLabel, t.TasksTitle} {
+		if text != nil {
+			text.Color = theme.Color(theme.ColorNameForeground)
+			text.Refresh()
+		}
+	}
+	t.AverageActivityBar.Refresh()
+	t.CurrentActivityBar.Refresh()
+	t.ActivitySparkline.Refresh()
+	t.updateInterface() // re-applies the running colors
+}
+
+// canvas.Text sizes are absolute, so they have to follow theme changes (and mini mode) by hand
+func (t *TrackerApp) applyTextSizes() {
+	t.Title.TextSize = theme.TextSize() * 2.0     // 2x normal
+	t.TaskLabel.TextSize = theme.TextSize() * 2.0 // 2x normal
+	t.Clock.TextSize = theme.TextSize() * 3.2     // really big
+	t.FocusLabel.TextSize = theme.TextSize() * 1.5
+	if t.settingsSnapshot().MiniMode {
+		t.TaskLabel.TextSize = theme.TextSize() * 1.2
+		t.Clock.TextSize = theme.TextSize() * 2.2
+		t.FocusLabel.TextSize = theme.TextSize()
+	}
+	t.Title.Refresh()
+	t.TaskLabel.Refresh()
+	t.Clock.Refresh()
+	t.FocusLabel.Refresh()
 }
@@ -8,132 +8,88 @@ import (
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
-	"fyne.io/fyne/v2/widget"
 
 	tl "github.com/tuumbleweed/tintlog/logger"
 	"github.com/tuumbleweed/tintlog/palette"
+
+	"work-tracker/src/pkg/store"
 )
 
 func (t *TrackerApp) Start() {
 	tl.Log(tl.Notice, palette.BlueBold, "%s", "Running work tracker app...")
 
 	// set functions
-	t.Button.OnTapped = func() {
-		t.onButtonTapped()
-		// when main button stopped - showStopped all the buttons
-		t.Mutex.Lock()
-		allTableRows := t.TableRows
-		currentTaskName := t.CurrentTaskName
-		isRunning := t.IsRunning
-		t.Mutex.Unlock()
-		if !isRunning {
-			for _, taskRow := range allTableRows {
-				showStopped(taskRow.Button)
-			}
-			tl.Log(tl.Info, palette.Cyan, "%s. Task name: '%s'", "Stopping task", currentTaskName)
-		} else {
-			tl.Log(tl.Info, palette.Cyan, "%s. Task name: '%s'", "Starting task", currentTaskName)
-		}
-	}
+	t.Button.OnTapped = t.onStartButtonTapped
+	t.PauseButton.OnTapped = t.onPauseButtonTapped
+	t.FocusButton.OnTapped = t.onFocusButtonTapped
 	t.Window.SetCloseIntercept(t.onClose)
+	t.Window.Canvas().AddShortcut(miniModeShortcut, func(fyne.Shortcut) { t.toggleMiniMode() })
+	t.Window.Canvas().AddShortcut(asOfShortcut, func(fyne.Shortcut) { t.showAsOfDialog() })
+	t.Window.Canvas().AddShortcut(undoShortcut, func(fyne.Shortcut) { t.undoFromUI() })
 
-	t.setContent()
+	t.applyWindowMode() // full or mini layout
 
-	go t.uiTickLoop()
-	go t.activityTickLoop()
-	go t.flushTickLoop()
+	go t.schedulerLoop()
+	go t.watchSession()
 
 	t.updateInterface() // initial
-	t.Window.ShowAndRun()
+	t.updateTray()
+	if t.settingsSnapshot().StartHidden && t.DeskApp != nil {
+		tl.Log(tl.Info, palette.Cyan, "%s", "Starting hidden in the tray")
+	} else {
+		t.showWindow()
+	}
+	t.App.Run()
 
 	tl.Log(tl.Notice, palette.GreenBold, "%s", "Closing work tracker app")
 }
 
 func (t *TrackerApp) setContent() {
-	content := container.New(
+	top := container.New(
 		layout.NewVBoxLayout(),
 		vgap(1, 10),
 		t.Title,
 		vgap(1, 10),
 		t.TaskLabel,
+		container.NewCenter(container.NewGridWrap(fyne.NewSize(560, t.NoteEntry.MinSize().Height), t.NoteEntry)),
 		vgap(1, 10),
 		t.Clock,
+		t.FocusLabel,
 		vgap(1, 5),
 		t.AverageActivityBar,
 		t.CurrentActivityBar,
+		t.ActivitySparkline,
 		vgap(1, 10),
-		container.NewCenter(t.Button),
-		vgap(1, 10),
-		t.TasksContainer,
+		container.NewCenter(container.NewHBox(t.Button, t.PauseButton, t.FocusButton)),
 		vgap(1, 10),
+		t.SuggestionBar,
 	)
+	// the task table takes the rest of the window and scrolls
+	content := container.NewBorder(top, vgap(1, 10), nil, nil, t.TasksContainer)
 	t.Window.SetContent(container.NewPadded(content))
 }
 
-func (t *TrackerApp) onButtonTapped() {
-	t.refreshActivityState()
-	t.refreshUIState()
-	t.flushChunkIfRunning()
-	t.flipSwitch()
-	t.updateInterface()
-}
-
 func (t *TrackerApp) onClose() {
 	close(t.done)
 	t.refreshActivityState()
 	t.refreshUIState()
-	t.UITicker.Stop()
-	t.ActivityTicker.Stop()
-	t.FlushTicker.Stop()
 	// flush current run if any (only works when t.IsRunning == true)
 	t.flushChunkIfRunning()
+	t.endPauseAt(time.Now()) // a pause in progress gets recorded too
+	t.endFocus()             // and so does a focus interval
+	t.saveWindowState()
 
 	// remove tray icon/menu BEFORE quitting (desktop only)
 	if t.DeskApp != nil {
 		fyne.Do(func() {
 			t.DeskApp.SetSystemTrayMenu(fyne.NewMenu("")) // non-nil empty menu
 			// optional: leave icon alone; process exit removes it
-			// t.DeskApp.SetSystemTrayIcon(t.TrayIconBlue)
 		})
 	}
 
 	t.App.Quit()
 }
 
-func (t *TrackerApp) uiTickLoop() {
-	for {
-		select {
-		case <-t.UITicker.C:
-			t.refreshUIState()
-			t.updateInterface()
-		case <-t.done:
-			return
-		}
-	}
-}
-
-func (t *TrackerApp) activityTickLoop() {
-	for {
-		select {
-		case <-t.ActivityTicker.C:
-			t.refreshActivityState()
-		case <-t.done:
-			return
-		}
-	}
-}
-
-func (t *TrackerApp) flushTickLoop() {
-	for {
-		select {
-		case <-t.FlushTicker.C:
-			t.flushChunkIfRunning()
-		case <-t.done:
-			return
-		}
-	}
-}
-
 /*
 Update clock and activity labels.
 
@@ -151,20 +107,26 @@ func (t *TrackerApp) updateInterface() {
 	activeToday := t.ActiveToday
 	lastTickActiveDuration := t.LastTickActiveDuration
 	currentTaskName := t.CurrentTaskName
-	tableRows := t.TableRows
-	timeByTask := t.TimeByTask
+	isPaused := t.IsPaused
+	pauseReason := t.PauseReason
+	pauseStart := t.PauseStart
+	statsByTask := t.taskStatsLocked(now)
+	pendingSuggestion, pendingSuggestedTime := t.pendingSuggestionLocked(now)
+	activityWindow := t.Settings.ActivityWindow.Duration
+	windowActivityPercentage := t.activityOverLocked(now, activityWindow)
+	sparkline := t.sparklineLocked(now)
+	activeProfile := t.Settings.ActiveProfile
 	t.Mutex.Unlock()
-
-	for _, tableRow := range tableRows {
-		setRowImportance(tableRow, widget.MediumImportance)
-	}
+	focusText, focusCounting := t.focusStatus(now, formatCountdown)
 
 	var currentTaskNameDisplay string // this is show above the clock
-	if currentTaskName == "" {
+	if isPaused {
+		currentTaskNameDisplay = t.Locale.T("PausedFor", "Reason", t.pauseReasonLabel(pauseReason), "Duration", formatDuration(now.Sub(pauseStart)))
+	} else if currentTaskName == "" {
 		if isRunning {
-			currentTaskNameDisplay = "Unassigned Task"
+			currentTaskNameDisplay = t.Locale.T("UnassignedTask")
 		} else {
-			currentTaskNameDisplay = "Not Tracking"
+			currentTaskNameDisplay = t.Locale.T("NotTracking")
 		}
 	} else {
 		currentTaskNameDisplay = currentTaskName
@@ -172,11 +134,17 @@ func (t *TrackerApp) updateInterface() {
 
 	activeToday = Clamp(activeToday, 0, workedToday)
 	todayAverageActivityPercentage := getActivityPercentage(activeToday, workedToday)
-	lastTickActivityPercentage := getActivityPercentage(lastTickActiveDuration, time.Since(t.LastActivityTickStart))
+	currentActivityPercentage := getActivityPercentage(lastTickActiveDuration, time.Since(t.LastActivityTickStart))
+	if activityWindow > 0 {
+		currentActivityPercentage = windowActivityPercentage // a rolling window instead of the last tick
+	}
 
 	clockText := formatDuration(workedToday)
 
-	titleText := now.Format("Monday, January 02, 15:04:05")
+	titleText := t.Locale.DateLong(now) + ", " + t.Locale.ClockSeconds(now)
+	if activeProfile != "" {
+		titleText += " · " + activeProfile
+	}
 
 	fyne.Do(func() {
 		// Update title
@@ -194,28 +162,47 @@ func (t *TrackerApp) updateInterface() {
 		}
 		t.Clock.Refresh()
 		t.TaskLabel.Refresh()
-		// update activity bars
-		t.AverageActivityBar.SetPercent(todayAverageActivityPercentage)
-		t.CurrentActivityBar.SetPercent(lastTickActivityPercentage)
-
-		// update button
-		if isRunning {
-			t.Button.SetText("Stop")
-			showRunning(t.Button)
+		// focus countdown
+		t.FocusLabel.Text = focusText
+		t.FocusLabel.Color = theme.Color(theme.ColorNameForeground)
+		if focusCounting {
+			t.FocusLabel.Color = getActiveColor()
+		}
+		if focusText == "" {
+			t.FocusLabel.Hide()
 		} else {
-			t.Button.SetText("Start")
-			showStopped(t.Button)
+			t.FocusLabel.Show()
 		}
-
-		// update table rows
-		for taskName, tableRow := range tableRows {
-			tableRow.TimeLabel.Text = formatDuration(timeByTask[taskName])
-			tableRow.TimeLabel.Refresh()
+		t.FocusLabel.Refresh()
+		// update activity bars
+		t.AverageActivityBar.SetPercent(todayAverageActivityPercentage)
+		t.CurrentActivityBar.SetPercent(currentActivityPercentage)
+		t.ActivitySparkline.SetValues(sparkline)
+
+		// update buttons
+		switch {
+		case isRunning:
+			t.Button.SetText(t.Locale.T("Stop"))
+			t.PauseButton.SetText(t.Locale.T("PauseMenu"))
+			t.PauseButton.Enable()
+		case isPaused:
+			t.Button.SetText(t.Locale.T("Resume"))
+			t.PauseButton.SetText(t.Locale.T("EndPause"))
+			t.PauseButton.Enable()
+		default:
+			t.Button.SetText(t.Locale.T("Start"))
+			t.PauseButton.SetText(t.Locale.T("PauseMenu"))
+			t.PauseButton.Disable()
 		}
+		setRunningLook(t.Button, isRunning)
+		t.refreshSuggestionBar(pendingSuggestion, pendingSuggestedTime)
 
-		if currentTaskName != "" {
-			setRowImportance(tableRows[currentTaskName], widget.HighImportance)
+		// update the task table, only cells that changed are redrawn
+		runningTaskName := currentTaskName
+		if !isRunning {
+			runningTaskName = ""
 		}
+		t.refreshTaskCells(statsByTask, now, runningTaskName, currentTaskName)
 	})
 	tl.Log(tl.Verbose1, palette.Green, "%s", "Updated interface")
 }
@@ -260,11 +247,18 @@ func (t *TrackerApp) refreshActivityState() {
 	if !t.IsRunning {
 		tl.Log(tl.Verbose1, palette.Cyan, "%s", "No need to refresh activity state")
 		t.LastActivityTickStart = now
+		if t.idleNeededWhileStopped() {
+			idleMs := tryXprintidle()
+			t.ActivityUnknown = idleMs < 0
+			t.IdleFor = time.Duration(idleMs) * time.Millisecond
+		}
 		t.Mutex.Unlock()
 		return
 	}
 
 	idleMs := tryXprintidle() // milliseconds since last input (may be -1 on error)
+	t.ActivityUnknown = idleMs < 0
+	t.IdleFor = time.Duration(idleMs) * time.Millisecond
 	t.LastTickActiveDuration = 0
 	lastTickDurationMs := time.Since(t.LastActivityTickStart).Milliseconds()
 	var activeMs int64
@@ -277,6 +271,7 @@ func (t *TrackerApp) refreshActivityState() {
 		activeMs = lastTickDurationMs
 	}
 	t.LastTickActiveDuration = time.Duration(activeMs) * time.Millisecond
+	t.recordActivityLocked(now, time.Duration(lastTickDurationMs)*time.Millisecond, t.LastTickActiveDuration)
 
 	// add last active duration to use later
 	t.ActiveToday += t.LastTickActiveDuration
@@ -289,18 +284,26 @@ func (t *TrackerApp) refreshActivityState() {
 	tl.Log(tl.Verbose1, palette.Green, "%s", "Refreshed activity state")
 }
 
-// Runs when we press on start/stop button
-func (t *TrackerApp) flipSwitch() {
+/*
+Runs when we press on start/stop button.
+
+When starting, the run begins at startAt (now, or earlier for a retroactive start)
+and taskName becomes the current task in the same critical section,
+so a flush can never see the run without its task.
+*/
+func (t *TrackerApp) flipSwitch(taskName string, startAt time.Time) {
 	tl.Log(tl.Verbose, palette.Blue, "%s", "Flipping switch")
 	t.Mutex.Lock()
 	if !t.IsRunning {
 		// starting
 		t.IsRunning = true
-		now := time.Now()
-		t.RunStart = now
-		t.TaskRunStart = now
-		t.ChunkStart = now
-		t.setTrayIcon(t.TrayIconGreen)
+		t.SessionStart = startAt
+		t.RunStart = startAt
+		t.TaskRunStart = startAt
+		t.ChunkStart = startAt
+		t.noteBlockStart = startAt
+		t.CurrentTaskName = taskName
+		t.SuggestedTask = t.suggestionForLocked(taskName, startAt)
 	} else {
 		// stopping
 		t.IsRunning = false
@@ -309,49 +312,41 @@ func (t *TrackerApp) flipSwitch() {
 		t.WorkedTodayBeforeStartingThisRun = t.WorkedToday
 		// set new t.TimeByTaskBeforeStartingThisRun
 		maps.Copy(t.TimeByTaskBeforeStartingThisRun, t.TimeByTask)
-		t.setTrayIcon(t.TrayIconBlue)
+		t.CurrentTaskName = ""
+		t.SuggestedTask = ""
+		t.noteBlockStart = time.Time{}
+		t.StartReason, t.StopReason = "", "" // not flushed (nothing left to write), don't leak into the next run
 	}
-	t.CurrentTaskName = "" // set to empty here, can be overriden later
 	t.Mutex.Unlock()
 	tl.Log(tl.Verbose1, palette.Green, "%s", "Flipped switch")
 }
 
 func (t *TrackerApp) flushChunkIfRunning() {
 	t.Mutex.Lock()
-	if t.IsRunning {
-		now := time.Now()
-		e := flushChunk(t.CurrentFilePath, t.ChunkStart, now, t.ActiveDuringThisChunk, t.CurrentTaskName)
-		if e != nil {
-			e.QuitIf("error") // don't expect any errors here, so quit if found one
-		}
-		t.ActiveDuringThisChunk = 0
-		t.ChunkStart = now
-	}
+	t.flushChunkLocked(time.Now())
 	t.Mutex.Unlock()
 }
 
-func (t *TrackerApp) setTrayIcon(res fyne.Resource) {
-	if t == nil {
-		return
-	}
-	if t.DeskApp == nil {
-		return
-	}
-	if res == nil {
+// flushChunkLocked writes the open chunk up to now. Caller holds t.Mutex.
+func (t *TrackerApp) flushChunkLocked(now time.Time) {
+	if !t.IsRunning || !now.After(t.ChunkStart) {
 		return
 	}
-
-	drv := t.App.Driver()
-	if drv == nil {
-		return
+	e := flushChunk(t.CurrentFilePath, t.ChunkStart, now, t.ActiveDuringThisChunk, t.CurrentTaskName, t.StartReason, t.StopReason, t.CurrentNote, t.SuggestedTask)
+	if e != nil {
+		e.QuitIf("error") // don't expect any errors here, so quit if found one
 	}
-
-	fyne.Do(func() {
-		t.DeskApp.SetSystemTrayIcon(res)
-
-		// Optional: if you want the window icon to match too
-		if t.Window != nil {
-			t.Window.SetIcon(res)
-		}
+	t.stampDayFileLocked()
+	addToDayStats(t.todayStats, store.Chunk{
+		TaskName:   t.CurrentTaskName,
+		StartedAt:  t.ChunkStart.Round(0),
+		FinishedAt: now.Round(0),
+		ActiveTime: Clamp(t.ActiveDuringThisChunk, 0, now.Sub(t.ChunkStart)),
 	})
+	if t.CurrentTaskName == "" && t.SuggestedTask != "" {
+		t.suggestedTime[t.SuggestedTask] += now.Sub(t.ChunkStart)
+	}
+	t.ActiveDuringThisChunk = 0
+	t.StartReason, t.StopReason = "", "" // each is written once
+	t.ChunkStart = now
 }
@@ -0,0 +1,214 @@
+package trackerapp
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+)
+
+/*
+Tracking actions used by the big button, row buttons and the tray menu.
+
+Each one refreshes state and flushes the open chunk first, so time is always
+attributed to the task that was running when the action happened.
+*/
+
+const maxRecentTasks = 8
+
+type trackerActionKind string
+
+const (
+	actionStart  trackerActionKind = "Start"
+	actionStop   trackerActionKind = "Stop"
+	actionSwitch trackerActionKind = "Switch"
+)
+
+// trackerAction remembers the last start/stop/switch so it can be undone (see undoLastAction).
+type trackerAction struct {
+	Kind             trackerActionKind
+	At               time.Time // when the action took effect, earlier than PerformedAt when retroactive
+	PerformedAt      time.Time // when the user did it, the undo grace period counts from here
+	TaskName         string    // started or switched-to task, stopped task for actionStop
+	PreviousTaskName string    // task switched away from (actionSwitch only)
+}
+
+// toggleTracking is the big Start/Stop button: stop when running, resume when paused, otherwise start unassigned.
+func (t *TrackerApp) toggleTracking() {
+	t.Mutex.Lock()
+	isRunning := t.IsRunning
+	isPaused := t.IsPaused
+	t.Mutex.Unlock()
+
+	switch {
+	case isRunning:
+		block := t.currentNoteBlock()
+		t.stopTracking()
+		t.promptNote(block)
+	case isPaused:
+		t.resumeTracking()
+	default:
+		t.startTask("")
+	}
+}
+
+// toggleTask is a row's play button: stop if this task is running, otherwise start or switch to it.
+func (t *TrackerApp) toggleTask(taskName string) {
+	t.Mutex.Lock()
+	isRunning := t.IsRunning
+	currentTaskName := t.CurrentTaskName
+	t.Mutex.Unlock()
+
+	if isRunning && currentTaskName == taskName {
+		block := t.currentNoteBlock()
+		t.stopTracking()
+		t.promptNote(block)
+	} else {
+		t.startTask(taskName)
+	}
+}
+
+// startTask starts tracking taskName ("" means unassigned), switching tasks if already running.
+func (t *TrackerApp) startTask(taskName string) {
+	t.Mutex.Lock()
+	isRunning := t.IsRunning
+	t.Mutex.Unlock()
+	if isRunning {
+		block := t.currentNoteBlock()
+		t.switchTask(taskName)
+		t.promptNote(block)
+		return
+	}
+	t.startTaskAt(taskName, time.Now())
+}
+
+// startTaskAt starts a run at startAt, which may be in the past (see startAsOf). No-op when running.
+func (t *TrackerApp) startTaskAt(taskName string, startAt time.Time) {
+	t.Mutex.Lock()
+	isRunning := t.IsRunning
+	t.Mutex.Unlock()
+	if isRunning {
+		return
+	}
+
+	tl.Log(tl.Info, palette.Cyan, "%s. Task name: '%s', as of: %s", "Starting task", taskName, startAt.Format(time.TimeOnly))
+	t.endPauseAt(startAt) // starting anything ends a pause
+	t.refreshActivityState()
+	t.refreshUIState()
+	t.flipSwitch(taskName, startAt)
+	t.Mutex.Lock()
+	t.LastAction = &trackerAction{Kind: actionStart, At: startAt, PerformedAt: time.Now(), TaskName: taskName}
+	t.Mutex.Unlock()
+	t.refreshUIState() // a retroactive start counts right away
+	t.afterTrackingChanged(taskName)
+	t.warnIfOutsideHours(startAt)
+}
+
+// switchTask moves tracking to taskName without stopping the run.
+func (t *TrackerApp) switchTask(taskName string) {
+	t.Mutex.Lock()
+	previousTaskName := t.CurrentTaskName
+	t.Mutex.Unlock()
+	if previousTaskName == taskName {
+		return
+	}
+
+	tl.Log(tl.Info, palette.Cyan, "%s. Previous: '%s', New: '%s'", "Switching tasks", previousTaskName, taskName)
+	// refresh state and then flush, so the previous task gets its time
+	t.refreshActivityState()
+	t.refreshUIState()
+	t.flushChunkIfRunning()
+	block := t.currentNoteBlock()
+
+	t.Mutex.Lock()
+	// save the progress, then make sure new task does not receive additional time
+	maps.Copy(t.TimeByTaskBeforeStartingThisRun, t.TimeByTask)
+	now := time.Now()
+	t.TaskRunStart = now
+	t.noteBlockStart = now
+	t.CurrentTaskName = taskName
+	t.SuggestedTask = t.suggestionForLocked(taskName, now)
+	t.CurrentNote = "" // in the same critical section, so the new task never gets the old note
+	t.LastAction = &trackerAction{Kind: actionSwitch, At: now, PerformedAt: now, TaskName: taskName, PreviousTaskName: previousTaskName}
+	t.Mutex.Unlock()
+	t.clearNote() // and the entry
+	t.backfillNote(block, now)
+	t.refreshUIState()
+	t.afterTrackingChanged(taskName)
+}
+
+// stopTracking flushes the open chunk and stops the run; when paused it ends the pause without resuming.
+func (t *TrackerApp) stopTracking() {
+	t.Mutex.Lock()
+	isRunning := t.IsRunning
+	isPaused := t.IsPaused
+	currentTaskName := t.CurrentTaskName
+	t.Mutex.Unlock()
+	if isPaused {
+		tl.Log(tl.Info, palette.Cyan, "%s", "Ending pause without resuming")
+		t.endPauseAt(time.Now())
+		t.afterTrackingChanged("")
+		return
+	}
+	if !isRunning {
+		return
+	}
+
+	tl.Log(tl.Info, palette.Cyan, "%s. Task name: '%s'", "Stopping task", currentTaskName)
+	t.refreshActivityState()
+	t.refreshUIState()
+	t.flushChunkIfRunning()
+	block := t.currentNoteBlock()
+	t.Mutex.Lock()
+	stoppedAt := t.ChunkStart // end of the chunk just flushed
+	t.Mutex.Unlock()
+	t.flipSwitch("", time.Time{})
+	t.Mutex.Lock()
+	t.LastAction = &trackerAction{Kind: actionStop, At: stoppedAt, PerformedAt: time.Now(), TaskName: currentTaskName}
+	t.Mutex.Unlock()
+	t.clearNote()
+	t.backfillNote(block, stoppedAt)
+	t.afterTrackingChanged("")
+}
+
+// afterTrackingChanged remembers the task as recent and updates every view of the state right away.
+func (t *TrackerApp) afterTrackingChanged(taskName string) {
+	if taskName != "" {
+		t.Mutex.Lock()
+		t.RecentTasks = slices.DeleteFunc(t.RecentTasks, func(name string) bool { return name == taskName })
+		t.RecentTasks = slices.Insert(t.RecentTasks, 0, taskName)
+		if len(t.RecentTasks) > maxRecentTasks {
+			t.RecentTasks = t.RecentTasks[:maxRecentTasks]
+		}
+		t.Mutex.Unlock()
+	}
+	t.focusTrackingChanged()
+	t.updateInterface()
+	t.updateTray()
+	t.wakeScheduler() // running and stopped poll activity at different rates
+}
+
+// seeds the recent tasks from today's totals (most time first)
+func recentTasksFromTotals(timeByTask map[string]time.Duration) (recentTasks []string) {
+	for taskName := range timeByTask {
+		if taskName != "" {
+			recentTasks = append(recentTasks, taskName)
+		}
+	}
+	slices.SortFunc(recentTasks, func(a, b string) int {
+		if timeByTask[a] == timeByTask[b] {
+			return strings.Compare(a, b)
+		}
+		if timeByTask[a] > timeByTask[b] {
+			return -1
+		}
+		return 1
+	})
+	if len(recentTasks) > maxRecentTasks {
+		recentTasks = recentTasks[:maxRecentTasks]
+	}
+	return recentTasks
+}
@@ -0,0 +1,117 @@
+package trackerapp
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+
+	"fyne.io/fyne/v2"
+)
+
+/*
+Tray icon rendered at runtime: a ring that fills clockwise toward the daily target,
+green while tracking, blue while stopped, and grey when activity can't be read
+(xprintidle missing or failing), so a broken idle detector is visible from the tray.
+*/
+
+const (
+	trayIconSize  = 64 // px, the tray scales it down
+	trayIconSteps = 48 // progress is quantized to this many steps, so at most that many PNGs are encoded per state
+)
+
+type trayIconState int
+
+const (
+	trayIconStopped trayIconState = iota
+	trayIconRunning
+	trayIconUnknown
+)
+
+type trayIconKey struct {
+	state trayIconState
+	step  int
+}
+
+var (
+	trayIconTrackColor   = color.NRGBA{R: 160, G: 160, B: 160, A: 110}
+	trayIconRunningColor = color.NRGBA{R: 20, G: 180, B: 45, A: 255}
+	trayIconStoppedColor = color.NRGBA{R: 0, G: 125, B: 255, A: 255}
+	trayIconUnknownColor = color.NRGBA{R: 130, G: 130, B: 130, A: 255}
+)
+
+// trayIcon returns the (cached) icon for state and progress in [0..1]
+func (t *TrackerApp) trayIcon(state trayIconState, progress float64) fyne.Resource {
+	key := trayIconKey{state: state, step: int(math.Round(clamp01(progress) * trayIconSteps))}
+	if t.trayIconCache == nil {
+		t.trayIconCache = make(map[trayIconKey]fyne.Resource)
+	}
+	if res, ok := t.trayIconCache[key]; ok {
+		return res
+	}
+
+	var buf bytes.Buffer
+	err := png.Encode(&buf, renderTrayIcon(key))
+	if err != nil {
+		return nil // caller keeps the previous icon
+	}
+	res := fyne.NewStaticResource(fmt.Sprintf("tray-%d-%02d.png", key.state, key.step), buf.Bytes())
+	t.trayIconCache[key] = res
+	return res
+}
+
+func renderTrayIcon(key trayIconKey) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, trayIconSize, trayIconSize))
+
+	fillColor := trayIconStoppedColor
+	switch key.state {
+	case trayIconRunning:
+		fillColor = trayIconRunningColor
+	case trayIconUnknown:
+		fillColor = trayIconUnknownColor
+	}
+	progress := float64(key.step) / trayIconSteps
+
+	center := float64(trayIconSize) / 2
+	outer := center - 2
+	inner := outer - float64(trayIconSize)/7
+	dot := inner / 2.2
+
+	for y := range trayIconSize {
+		for x := range trayIconSize {
+			dx := float64(x) + 0.5 - center
+			dy := float64(y) + 0.5 - center
+			r := math.Hypot(dx, dy)
+
+			// ring: 1px soft edges on both sides
+			if ringCoverage := clamp01(outer-r+0.5) * clamp01(r-inner+0.5); ringCoverage > 0 {
+				// angle measured clockwise from 12 o'clock, in [0..1)
+				angle := math.Atan2(dx, -dy) / (2 * math.Pi)
+				if angle < 0 {
+					angle++
+				}
+				c := trayIconTrackColor
+				if angle < progress {
+					c = fillColor
+				}
+				img.SetNRGBA(x, y, withCoverage(c, ringCoverage))
+				continue
+			}
+
+			// center: full dot while running, hollow while stopped
+			if key.state == trayIconRunning || key.state == trayIconUnknown {
+				if dotCoverage := clamp01(dot - r + 0.5); dotCoverage > 0 {
+					img.SetNRGBA(x, y, withCoverage(fillColor, dotCoverage))
+				}
+			}
+		}
+	}
+	return img
+}
+
+func withCoverage(c color.NRGBA, coverage float64) color.NRGBA {
+	c.A = uint8(float64(c.A) * coverage)
+	return c
+}
@@ -1,8 +1,9 @@
 package trackerapp
 
 import (
-	"os"
-	"path/filepath"
+	"fmt"
+	"slices"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/driver/desktop"
@@ -12,7 +13,7 @@ import (
 	"github.com/tuumbleweed/xerr"
 )
 
-// initTray sets up system tray (icons + menu). No-op if driver doesn't support tray.
+// initTray sets up system tray (icon + menu). No-op if driver doesn't support tray.
 func (t *TrackerApp) initTray() (e *xerr.Error) {
 	if t == nil {
 		return nil
@@ -31,62 +32,214 @@ func (t *TrackerApp) initTray() (e *xerr.Error) {
 	}
 	t.DeskApp = deskApp
 
-	// Resolve icon paths relative to the executable (not CWD)
-	exePath, err := os.Executable()
-	if err != nil {
-		return xerr.NewError(err, "Unable to resolve executable path", exePath)
+	// Initial state: not running, nothing tracked yet
+	icon := t.trayIcon(trayIconStopped, 0)
+	t.Window.SetIcon(icon)
+	t.DeskApp.SetSystemTrayIcon(icon)
+	t.trayIconCurrent = icon
+
+	// labels are filled in by updateTray
+	t.TrayStatusItem = fyne.NewMenuItem(t.Locale.T("TrayNotTracking"), nil)
+	t.TrayStatusItem.Disabled = true
+	t.TrayTodayItem = fyne.NewMenuItem(t.Locale.T("TrayToday", "Duration", t.Locale.DurationMinutes(0)), nil)
+	t.TrayTodayItem.Disabled = true
+	t.TrayToggleItem = fyne.NewMenuItem(t.Locale.T("Start"), t.toggleTracking)
+	t.TraySwitchItem = fyne.NewMenuItem(t.Locale.T("SwitchTo"), nil)
+	t.TraySwitchItem.ChildMenu = fyne.NewMenu("")
+	t.TrayPauseItem = fyne.NewMenuItem(t.Locale.T("Pause"), nil)
+	t.TrayPauseItem.ChildMenu = fyne.NewMenu("", t.pauseReasonItems()...)
+	t.TrayPauseItem.Disabled = true
+	t.TrayFocusItem = fyne.NewMenuItem(t.Locale.T("Focus"), nil)
+	t.TrayFocusItem.ChildMenu = fyne.NewMenu("", t.focusItems(false)...)
+	t.TrayUndoItem = fyne.NewMenuItem(t.undoLabel(nil), t.undoFromUI)
+	t.TrayUndoItem.Disabled = true
+	t.TrayMiniItem = fyne.NewMenuItem(t.Locale.T("MiniMode"), t.toggleMiniMode)
+	current := t.settingsSnapshot()
+	t.TrayMiniItem.Checked = current.MiniMode
+
+	items := []*fyne.MenuItem{
+		t.TrayStatusItem,
+		t.TrayTodayItem,
+		fyne.NewMenuItemSeparator(),
+		t.TrayToggleItem,
+		t.TrayPauseItem,
+		t.TrayFocusItem,
+		t.TraySwitchItem,
+		fyne.NewMenuItem(t.Locale.T("StartOrSwitchAsOf"), t.showAsOfDialog),
+		fyne.NewMenuItem(t.Locale.T("SplitTime"), t.showSplitDialog),
+		t.TrayUndoItem,
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(t.Locale.T("Show"), t.showWindow),
+		fyne.NewMenuItem(t.Locale.T("Hide"), t.hideWindow),
+		t.TrayMiniItem,
+		fyne.NewMenuItemSeparator(),
+	}
+	if len(current.Profiles) > 0 {
+		t.TrayProfileItem = fyne.NewMenuItem(t.Locale.T("Profile"), nil)
+		t.TrayProfileItem.ChildMenu = fyne.NewMenu("", t.profileItems()...)
+		items = append(items, t.TrayProfileItem)
 	}
-	exeDir := filepath.Dir(exePath)
+	items = append(items,
+		fyne.NewMenuItem(t.Locale.T("ReportsMenu"), t.showReportsWindow),
+		fyne.NewMenuItem(t.Locale.T("SettingsMenu"), t.showSettingsWindow),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(t.Locale.T("Quit"), func() {
+			// Call cleanup path, not just Quit, so tray gets cleared, the scheduler stops, etc.
+			t.onClose()
+		}),
+	)
+	t.TrayMenu = fyne.NewMenu("Work Tracker", items...)
+	t.DeskApp.SetSystemTrayMenu(t.TrayMenu)
 
-	loadIcon := func(relPath string) (r fyne.Resource, e *xerr.Error) {
-		rel := filepath.FromSlash(relPath)
+	return nil
+}
+
+/*
+updateTray brings the tray menu labels, the switch submenu and the icon up to date.
+
+Labels use minute precision: re-sending the menu every second makes some
+desktops close it while it's open. The menu is only re-sent when something changed.
+*/
+func (t *TrackerApp) updateTray() {
+	if t.DeskApp == nil || t.TrayMenu == nil {
+		return
+	}
+
+	t.Mutex.Lock()
+	isRunning := t.IsRunning
+	activityUnknown := t.ActivityUnknown
+	currentTaskName := t.CurrentTaskName
+	workedToday := t.WorkedToday
+	timeOnTask := t.TimeByTask[currentTaskName]
+	recentTasks := slices.Clone(t.RecentTasks)
+	isPaused := t.IsPaused
+	pauseReason := t.PauseReason
+	dailyTarget := t.Settings.DailyTarget.Duration
+	t.Mutex.Unlock()
+	undoAction := t.undoableAction()
+	pauseDuration := t.pauseDuration(time.Now())
+	focusText, _ := t.focusStatus(time.Now(), t.Locale.DurationMinutes)
 
-		abs := filepath.Join(exeDir, rel)
-		res, err := fyne.LoadResourceFromPath(abs)
-		if err == nil {
-			return res, nil
+	// labels
+	l := t.Locale
+	statusText := l.T("TrayNotTracking")
+	toggleText := l.T("Start")
+	pauseText := l.T("Pause")
+	if isPaused {
+		statusText = l.T("TrayPaused", "Reason", t.pauseReasonLabel(pauseReason), "Duration", l.DurationMinutes(pauseDuration))
+		toggleText = l.T("Resume")
+		pauseText = l.T("EndPause")
+	}
+	if isRunning {
+		taskName := currentTaskName
+		if taskName == "" {
+			taskName = l.T("UnassignedTask")
 		}
+		statusText = l.T("TrayRunning", "Task", taskName, "Duration", l.DurationMinutes(timeOnTask))
+		toggleText = l.T("Stop")
+	}
+	focusMenuText := l.T("Focus")
+	if focusText != "" {
+		focusMenuText = focusText
+	}
+	todayText := l.T("TrayToday", "Duration", l.DurationMinutes(workedToday))
+	if activityUnknown && isRunning {
+		todayText = l.T("TrayTodayUnknown", "Duration", l.DurationMinutes(workedToday))
+	}
 
-		// Fallback to working-directory relative (dev runs)
-		cwd := filepath.FromSlash("./" + relPath)
-		res, err2 := fyne.LoadResourceFromPath(cwd)
-		if err2 != nil {
-			return nil, xerr.NewError(err2, "Unable to load tray icon", abs)
+	// switch submenu: recent tasks first, then the rest of the task list up to the same limit
+	switchNames := recentTasks
+	for _, task := range t.Tasks {
+		if len(switchNames) >= maxRecentTasks {
+			break
+		}
+		if !slices.Contains(switchNames, task.Name) {
+			switchNames = append(switchNames, task.Name)
 		}
-		return res, nil
 	}
 
-	blue, e := loadIcon("pictures/tray-clock-blue-24.png")
-	if e != nil {
-		return e
+	// icon
+	iconState := trayIconStopped
+	if isRunning {
+		iconState = trayIconRunning
+		if activityUnknown {
+			iconState = trayIconUnknown
+		}
 	}
-	green, e := loadIcon("pictures/tray-clock-green-24.png")
-	if e != nil {
-		return e
+	progress := 0.0
+	if dailyTarget > 0 {
+		progress = float64(workedToday) / float64(dailyTarget)
 	}
 
-	t.TrayIconBlue = blue
-	t.TrayIconGreen = green
+	fyne.Do(func() {
+		icon := t.trayIcon(iconState, progress)
+		if icon != nil && icon != t.trayIconCurrent {
+			t.trayIconCurrent = icon
+			t.DeskApp.SetSystemTrayIcon(icon)
+			t.Window.SetIcon(icon)
+		}
 
-	// Initial state: not running => blue
-	t.Window.SetIcon(t.TrayIconBlue)
-	t.DeskApp.SetSystemTrayIcon(t.TrayIconBlue)
+		changed := false
+		setLabel := func(item *fyne.MenuItem, label string) {
+			if item.Label != label {
+				item.Label = label
+				changed = true
+			}
+		}
+		setLabel(t.TrayStatusItem, statusText)
+		setLabel(t.TrayTodayItem, todayText)
+		setLabel(t.TrayToggleItem, toggleText)
+		setLabel(t.TrayUndoItem, t.undoLabel(undoAction))
+		if t.TrayUndoItem.Disabled != (undoAction == nil) {
+			t.TrayUndoItem.Disabled = undoAction == nil
+			changed = true
+		}
 
-	m := fyne.NewMenu("Work Tracker",
-		fyne.NewMenuItem("Show", func() {
-			t.Window.Show()
-			t.Window.RequestFocus()
-		}),
-		fyne.NewMenuItem("Hide", func() {
-			t.Window.Hide()
-		}),
-		fyne.NewMenuItemSeparator(),
-		fyne.NewMenuItem("Quit", func() {
-			// Call cleanup path, not just Quit, so tray gets cleared, tickers stop, etc.
-			t.onClose()
-		}),
-	)
-	t.DeskApp.SetSystemTrayMenu(m)
+		// pause: reasons while running, a plain "End pause" while paused, disabled while stopped
+		setLabel(t.TrayPauseItem, pauseText)
+		if pauseDisabled := !isRunning && !isPaused; t.TrayPauseItem.Disabled != pauseDisabled {
+			t.TrayPauseItem.Disabled = pauseDisabled
+			changed = true
+		}
+		if isPaused && t.TrayPauseItem.ChildMenu != nil {
+			t.TrayPauseItem.ChildMenu = nil
+			t.TrayPauseItem.Action = t.stopTracking
+			changed = true
+		} else if !isPaused && t.TrayPauseItem.ChildMenu == nil {
+			t.TrayPauseItem.ChildMenu = fyne.NewMenu("", t.pauseReasonItems()...)
+			t.TrayPauseItem.Action = nil
+			changed = true
+		}
 
-	return nil
+		// focus: the countdown during a session, which adds "End focus" to the submenu
+		setLabel(t.TrayFocusItem, focusMenuText)
+		if inSession := focusText != ""; inSession != t.trayFocusEnd {
+			t.trayFocusEnd = inSession
+			t.TrayFocusItem.ChildMenu.Items = t.focusItems(inSession)
+			changed = true
+		}
+
+		switchKey := fmt.Sprintf("%q|%t|%q", switchNames, isRunning, currentTaskName)
+		if switchKey != t.traySwitchKey {
+			t.traySwitchKey = switchKey
+			t.TraySwitchItem.ChildMenu.Items = t.makeSwitchItems(switchNames, isRunning, currentTaskName)
+			changed = true
+		}
+
+		if changed {
+			t.TrayMenu.Refresh()
+		}
+	})
+}
+
+// one item per task, the running one is checked; "Unassigned" is always available
+func (t *TrackerApp) makeSwitchItems(taskNames []string, isRunning bool, currentTaskName string) (items []*fyne.MenuItem) {
+	for _, taskName := range taskNames {
+		item := fyne.NewMenuItem(taskName, func() { t.startTask(taskName) })
+		item.Checked = isRunning && taskName == currentTaskName
+		items = append(items, item)
+	}
+	unassigned := fyne.NewMenuItem(t.Locale.T("Unassigned"), func() { t.startTask("") })
+	unassigned.Checked = isRunning && currentTaskName == ""
+	return append(items, fyne.NewMenuItemSeparator(), unassigned)
 }
@@ -0,0 +1,86 @@
+package trackerapp
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+)
+
+/*
+Fyne has no API for window position or "always on top", so those go through
+wmctrl (X11 / XWayland), the same way activity goes through xprintidle.
+Windows are matched by their exact title. Without wmctrl the tracker still
+works, it just can't restore the position or keep the mini window on top.
+*/
+
+// wmctrlWindowPosition returns the top-left corner of the window titled title.
+func wmctrlWindowPosition(title string) (x, y int, e *xerr.Error) {
+	out, err := exec.Command("wmctrl", "-l", "-G").Output()
+	if err != nil {
+		return 0, 0, xerr.NewErrorECOL(err, "Unable to list windows with wmctrl", "title", title)
+	}
+	// <id> <desktop> <x> <y> <width> <height> <host> <title...>
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 || strings.Join(fields[7:], " ") != title {
+			continue
+		}
+		x, errX := strconv.Atoi(fields[2])
+		y, errY := strconv.Atoi(fields[3])
+		if errX != nil || errY != nil {
+			return 0, 0, xerr.NewErrorECOL(fmt.Errorf("bad wmctrl line '%s'", scanner.Text()), "Unable to parse window position", "title", title)
+		}
+		return x, y, nil
+	}
+	return 0, 0, xerr.NewErrorECOL(fmt.Errorf("no window titled '%s'", title), "Unable to find window", "title", title)
+}
+
+// wmctrlMoveWindow moves the window titled title, keeping its size.
+func wmctrlMoveWindow(title string, x, y int) (e *xerr.Error) {
+	err := exec.Command("wmctrl", "-F", "-r", title, "-e", fmt.Sprintf("0,%d,%d,-1,-1", x, y)).Run()
+	if err != nil {
+		return xerr.NewErrorECOL(err, "Unable to move window with wmctrl", "title", title)
+	}
+	return nil
+}
+
+// wmctrlSetAbove adds or removes the "above" (always on top) state of the window titled title.
+func wmctrlSetAbove(title string, above bool) (e *xerr.Error) {
+	action := "remove,above"
+	if above {
+		action = "add,above"
+	}
+	err := exec.Command("wmctrl", "-F", "-r", title, "-b", action).Run()
+	if err != nil {
+		return xerr.NewErrorECOL(err, "Unable to change window state with wmctrl", "title", title)
+	}
+	return nil
+}
+
+/*
+whenWindowMapped retries fn until it succeeds or the window had a couple of
+seconds to appear; right after Show() the window manager may not know it yet.
+Runs in the background, failures are logged, not shown.
+*/
+func whenWindowMapped(what string, fn func() *xerr.Error) {
+	go func() {
+		var e *xerr.Error
+		for range 20 {
+			e = fn()
+			if e == nil {
+				return
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		tl.Log(tl.Warning, palette.Yellow, "Unable to %s: %s. %s", what, e.Msg, "Is wmctrl installed?")
+	}()
+}
@@ -0,0 +1,144 @@
+package trackerapp
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+	"github.com/tuumbleweed/xerr"
+
+	"work-tracker/src/pkg/settings"
+)
+
+/*
+Mini mode swaps the window content for a compact layout (task, clock,
+start/stop, current activity) and optionally keeps it above other windows.
+Size, position and mode are written back to the settings file on quit.
+*/
+
+// Ctrl+M (Cmd+M on macOS) toggles mini mode
+var miniModeShortcut = &desktop.CustomShortcut{KeyName: fyne.KeyM, Modifier: fyne.KeyModifierShortcutDefault}
+
+// toggleMiniMode switches between the full and the compact window. Must run on the UI goroutine.
+func (t *TrackerApp) toggleMiniMode() {
+	t.rememberWindowSize() // of the layout we're leaving
+	t.Mutex.Lock()
+	t.Settings.MiniMode = !t.Settings.MiniMode
+	miniMode := t.Settings.MiniMode
+	t.Mutex.Unlock()
+	tl.Log(tl.Info, palette.Cyan, "%s. Mini mode: %v", "Switching window mode", miniMode)
+	t.applyWindowMode()
+}
+
+// applyWindowMode lays out the window for the current mode. Must run on the UI goroutine.
+func (t *TrackerApp) applyWindowMode() {
+	current := t.settingsSnapshot()
+	if current.MiniMode {
+		t.Window.SetMainMenu(nil) // no room for a menu bar, the tray and Ctrl+M still work
+		t.setMiniContent()
+		t.Window.Resize(fyne.NewSize(current.MiniWidth, current.MiniHeight))
+	} else {
+		t.setMainMenu()
+		t.setContent()
+		t.Window.Resize(fyne.NewSize(current.WindowWidth, current.WindowHeight))
+	}
+	t.applyTextSizes()
+	t.applyAlwaysOnTop()
+
+	if t.TrayMiniItem != nil {
+		t.TrayMiniItem.Checked = current.MiniMode
+		t.TrayMenu.Refresh()
+	}
+}
+
+func (t *TrackerApp) setMiniContent() {
+	labels := container.NewVBox(t.TaskLabel, t.Clock, t.FocusLabel)
+	top := container.NewBorder(nil, nil, nil, container.NewCenter(t.Button), labels)
+	t.Window.SetContent(container.NewPadded(container.NewVBox(top, t.CurrentActivityBar)))
+}
+
+// showWindow shows and focuses the main window, restoring its saved position the first time.
+func (t *TrackerApp) showWindow() {
+	t.Window.Show()
+	t.setWindowVisible(true) // the scheduler renders the widgets right away
+	t.Window.RequestFocus()
+	t.applyAlwaysOnTop()
+	t.restoreWindowPosition()
+}
+
+// hideWindow hides the main window to the tray, widgets stop being rendered until it's shown again.
+func (t *TrackerApp) hideWindow() {
+	t.Window.Hide()
+	t.setWindowVisible(false)
+}
+
+func (t *TrackerApp) applyAlwaysOnTop() {
+	current := t.settingsSnapshot()
+	above := current.MiniMode && current.MiniAlwaysOnTop
+	if !above && !t.windowAbove {
+		return // nothing to add or remove, don't bother the window manager
+	}
+	t.windowAbove = above
+	title := t.Window.Title()
+	whenWindowMapped("keep the window on top", func() *xerr.Error { return wmctrlSetAbove(title, above) })
+}
+
+func (t *TrackerApp) restoreWindowPosition() {
+	position := t.settingsSnapshot().WindowPosition
+	if t.windowPositionRestored || position == nil {
+		return
+	}
+	t.windowPositionRestored = true
+	title := t.Window.Title()
+	whenWindowMapped("restore the window position", func() *xerr.Error { return wmctrlMoveWindow(title, position.X, position.Y) })
+}
+
+// rememberWindowSize stores the current window size as the size of the current mode.
+func (t *TrackerApp) rememberWindowSize() {
+	size := t.Window.Canvas().Size()
+	if size.Width <= 0 || size.Height <= 0 {
+		return // never shown
+	}
+	// clamp to what Settings.Validate accepts, so saving never fails because of a tiny window
+	t.Mutex.Lock()
+	defer t.Mutex.Unlock()
+	if t.Settings.MiniMode {
+		t.Settings.MiniWidth = Clamp(size.Width, 160, 7680)
+		t.Settings.MiniHeight = Clamp(size.Height, 80, 4320)
+	} else {
+		t.Settings.WindowWidth = Clamp(size.Width, 320, 7680)
+		t.Settings.WindowHeight = Clamp(size.Height, 240, 4320)
+	}
+}
+
+/*
+saveWindowState writes size, position and mode into the settings file.
+Only window fields are touched, so command line overrides are never persisted.
+*/
+func (t *TrackerApp) saveWindowState() {
+	t.rememberWindowSize()
+	if t.windowPositionRestored || t.settingsSnapshot().WindowPosition == nil {
+		// the window was shown, so its current position is worth keeping
+		x, y, e := wmctrlWindowPosition(t.Window.Title())
+		if e == nil {
+			t.Mutex.Lock()
+			t.Settings.WindowPosition = &settings.Position{X: x, Y: y}
+			t.Mutex.Unlock()
+		}
+	}
+
+	current := t.settingsSnapshot()
+	e := settings.Update(t.SettingsPath, func(saved *settings.Settings) {
+		saved.WindowWidth = current.WindowWidth
+		saved.WindowHeight = current.WindowHeight
+		saved.WindowPosition = current.WindowPosition
+		saved.MiniMode = current.MiniMode
+		saved.MiniWidth = current.MiniWidth
+		saved.MiniHeight = current.MiniHeight
+	})
+	if e != nil {
+		tl.Log(tl.Warning, palette.Yellow, "%s: %s", "Window state not saved", e.Msg)
+	}
+}
@@ -0,0 +1,133 @@
+package trackerapp
+
+import (
+	"time"
+
+	tl "github.com/tuumbleweed/tintlog/logger"
+	"github.com/tuumbleweed/tintlog/palette"
+
+	"work-tracker/src/pkg/notify"
+	"work-tracker/src/pkg/settings"
+	"work-tracker/src/pkg/store"
+)
+
+/*
+Working hours (Settings.Schedule), checked on every activity tick:
+
+  - a run still going outside working hours is stopped once there was no input
+    for AutoStopIdle; the idle tail is cut off, never time inside a window
+  - starting outside working hours shows a warning
+  - with AutoStart, the first activity in a window starts the last task (once per window)
+*/
+
+const notifyKeyOutsideHours = "outside-hours"
+
+func (t *TrackerApp) checkSchedule(now time.Time) {
+	schedule := t.settingsSnapshot().Schedule
+	if !schedule.Enabled {
+		return
+	}
+	window, inWindow := schedule.WindowAt(now)
+
+	t.Mutex.Lock()
+	isRunning := t.IsRunning
+	isPaused := t.IsPaused
+	sessionStart := t.SessionStart
+	idleKnown := !t.ActivityUnknown
+	idleFor := t.IdleFor
+	lastTaskName := ""
+	if len(t.RecentTasks) > 0 {
+		lastTaskName = t.RecentTasks[0]
+	}
+	if inWindow && (isRunning || isPaused) {
+		t.scheduleWindowUsed = window.Start // already tracking, no auto-start later in this window
+	}
+	autoStart := schedule.AutoStart && inWindow && !isRunning && !isPaused &&
+		!window.Start.Equal(t.scheduleWindowUsed) && idleKnown && idleFor < idleThreshold
+	if autoStart {
+		t.scheduleWindowUsed = window.Start
+		t.StartReason = StartReasonSchedule
+	}
+	t.Mutex.Unlock()
+
+	switch {
+	case isRunning && !inWindow && schedule.AutoStop && idleKnown && idleFor >= schedule.AutoStopIdle.Duration:
+		t.autoStop(autoStopPoint(schedule, sessionStart, idleFor, now), now)
+	case autoStart:
+		tl.Log(tl.Info, palette.Cyan, "%s. Task name: '%s'", "Starting on first activity in working hours", lastTaskName)
+		t.startTask(lastTaskName)
+	}
+}
+
+/*
+autoStopPoint is where a run stopped outside working hours ends: when the input
+stopped, but not before the run started nor before the end of the last window
+it was going in, idle time inside working hours is kept.
+*/
+func autoStopPoint(schedule settings.ScheduleSettings, sessionStart time.Time, idleFor time.Duration, now time.Time) (stopAt time.Time) {
+	stopAt = latest(now.Add(-idleFor), sessionStart)
+	if windowEnd, ok := schedule.LastEndBetween(sessionStart, now); ok {
+		stopAt = latest(stopAt, windowEnd)
+	}
+	return stopAt
+}
+
+/*
+autoStop stops the run and gives back everything after stopAt (when the input stopped),
+marking the run's new last chunk with the schedule stop reason.
+Undo restarts as of stopAt, so it gets the cut time back.
+*/
+func (t *TrackerApp) autoStop(stopAt, now time.Time) {
+	t.Mutex.Lock()
+	taskName := t.CurrentTaskName
+	t.StopReason = StopReasonSchedule
+	t.Mutex.Unlock()
+	tl.Log(tl.Notice, palette.Cyan, "%s. Task name: '%s', no input since: %s", "Stopping outside working hours", taskName, stopAt.Format(time.TimeOnly))
+	t.stopTracking()
+
+	t.Mutex.Lock()
+	e := t.rewriteDayLocked(now, func(chunks []store.Chunk) []store.Chunk {
+		chunks = dropChunksSince(chunks, stopAt)
+		for i := range chunks {
+			if chunks[i].Kind == store.ChunkKindWork && chunks[i].FinishedAt.Equal(stopAt.Round(0)) {
+				chunks[i].StopReason = StopReasonSchedule
+			}
+		}
+		return chunks
+	})
+	if e == nil && t.LastAction != nil && t.LastAction.Kind == actionStop {
+		t.LastAction.At = stopAt
+	}
+	t.Mutex.Unlock()
+	if e != nil {
+		tl.Log(tl.Warning, palette.Yellow, "%s: %s", "Stopped, but the idle time could not be cut off", e.Msg)
+	}
+	t.afterTrackingChanged("")
+
+	if taskName == "" {
+		taskName = t.Locale.T("UnassignedTask")
+	}
+	t.showNotification(notify.Notification{
+		Key:   notifyKeyOutsideHours,
+		Title: t.Locale.T("NotifyAutoStopTitle"),
+		Body:  t.Locale.T("NotifyAutoStopBody", "Task", taskName, "Time", t.Locale.Clock(stopAt)),
+	}, now)
+}
+
+// warnIfOutsideHours notifies when a run starts outside working hours
+func (t *TrackerApp) warnIfOutsideHours(startAt time.Time) {
+	schedule := t.settingsSnapshot().Schedule
+	if !schedule.Enabled || !schedule.WarnOutside {
+		return
+	}
+	if _, inWindow := schedule.WindowAt(startAt); inWindow {
+		return
+	}
+
+	tl.Log(tl.Notice, palette.Yellow, "%s at %s", "Started tracking outside working hours", startAt.Format(time.TimeOnly))
+	body := t.Locale.T("NotifyOutsideHoursBody", "Time", t.Locale.Clock(startAt))
+	if schedule.AutoStop {
+		body += " " + t.Locale.T("NotifyOutsideHoursAutoStop", "Duration", t.Locale.DurationMinutes(schedule.AutoStopIdle.Duration))
+	}
+	t.showNotification(notify.Notification{Key: notifyKeyOutsideHours, Title: t.Locale.T("NotifyOutsideHoursTitle"), Body: body}, time.Now())
+}
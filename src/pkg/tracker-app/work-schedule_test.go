@@ -0,0 +1,45 @@
+package trackerapp
+
+import (
+	"testing"
+	"time"
+
+	"work-tracker/src/pkg/settings"
+)
+
+func TestAutoStopPoint(t *testing.T) {
+	// Friday 2026-01-23 and Saturday: 09:00-12:00 and 13:00-17:00, then 20:00-23:30 on Saturday
+	schedule := settings.ScheduleSettings{
+		Enabled: true,
+		Week: map[string][]settings.HoursWindow{
+			"friday":   {{From: "09:00", To: "12:00"}, {From: "13:00", To: "17:00"}},
+			"saturday": {{From: "20:00", To: "23:30"}},
+		},
+		AutoStop:     true,
+		AutoStopIdle: settings.Duration{Duration: 30 * time.Minute},
+	}
+	on := func(day, hour, minute int) time.Time { return time.Date(2026, 1, day, hour, minute, 0, 0, time.Local) }
+	tests := []struct {
+		name         string
+		sessionStart time.Time
+		idleFor      time.Duration
+		now          time.Time
+		want         time.Time
+	}{
+		{"idle after hours, cut where the input stopped", on(23, 13, 0), 30 * time.Minute, on(23, 18, 0), on(23, 17, 30)},
+		{"idle since inside hours, clamped to the window end", on(23, 13, 0), 2 * time.Hour, on(23, 18, 0), on(23, 17, 0)},
+		{"idle between windows, clamped to the earlier end", on(23, 9, 0), 45 * time.Minute, on(23, 12, 40), on(23, 12, 0)},
+		{"started after hours, not before the start", on(23, 19, 0), 5 * time.Hour, on(23, 22, 0), on(23, 19, 0)},
+		{"run spanning midnight", on(24, 21, 0), 3 * time.Hour, on(25, 1, 0), on(24, 23, 30)},
+		{"run spanning midnight, input until after it", on(24, 21, 0), 30 * time.Minute, on(25, 1, 0), on(25, 0, 30)},
+		{"over days, the last window counts", on(23, 9, 0), 30 * time.Hour, on(25, 8, 0), on(24, 23, 30)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := autoStopPoint(schedule, test.sessionStart, test.idleFor, test.now)
+			if !got.Equal(test.want) {
+				t.Errorf("autoStopPoint = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"flag"
 	"os"
 	"strings"
 
@@ -28,3 +29,17 @@ func EnsureFlags() {
 		os.Exit(1)
 	}
 }
+
+/*
+FlagWasSet reports whether flag name was given on the command line of flagSet
+(pass flag.CommandLine for the default set).
+Used to let explicit flags win over values from the settings file.
+*/
+func FlagWasSet(flagSet *flag.FlagSet, name string) (wasSet bool) {
+	flagSet.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			wasSet = true
+		}
+	})
+	return wasSet
+}